@@ -2,19 +2,19 @@ package app
 
 import (
 	"database/sql"
-	"time"
 
+	"sudutkampus/gorestfulapi/config"
 	"sudutkampus/gorestfulapi/helper"
 )
 
-func NewDB() *sql.DB {
-	db, err := sql.Open("mysql", "root:root@tcp(localhost:8889)/gorestfulapi")
+func NewDB(databaseConfig config.DatabaseConfig) *sql.DB {
+	db, err := sql.Open(databaseConfig.Driver, databaseConfig.DSN)
 	helper.PanicIfError(err)
 
-	db.SetMaxIdleConns(5)
-	db.SetMaxOpenConns(20)
-	db.SetConnMaxIdleTime(10 * time.Minute)
-	db.SetConnMaxLifetime(60 * time.Minute)
+	db.SetMaxIdleConns(databaseConfig.MaxIdleConns)
+	db.SetMaxOpenConns(databaseConfig.MaxOpenConns)
+	db.SetConnMaxIdleTime(databaseConfig.ConnMaxIdleTime)
+	db.SetConnMaxLifetime(databaseConfig.ConnMaxLifetime)
 
 	return db
 }
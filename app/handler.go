@@ -0,0 +1,18 @@
+package app
+
+import "net/http"
+
+// NewHandler serves requests for the exact paths of endpoints, such as
+// /metrics, with their own handler and everything else with api. Those
+// endpoints bypass the middleware wrapped around api, authentication
+// included.
+func NewHandler(api http.Handler, endpoints map[string]http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if handler, ok := endpoints[r.URL.Path]; ok {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		api.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,27 @@
+package app
+
+import (
+	"sudutkampus/gorestfulapi/config"
+	"sudutkampus/gorestfulapi/health"
+	"sudutkampus/gorestfulapi/logging"
+	"sudutkampus/gorestfulapi/migration"
+)
+
+// NewHealthChecker checks the database pool and that every migration has
+// been applied on SQL backends, and that lifecycle is not shutting down.
+// Failed checks are logged to logger.
+func NewHealthChecker(storage *Storage, databaseConfig config.DatabaseConfig, healthConfig config.HealthConfig, lifecycle *Lifecycle, logger *logging.Logger) (*health.Checker, error) {
+	var checks []health.Check
+	if storage.DB != nil {
+		migrator, err := migration.NewMigrator(storage.DB, databaseConfig.Driver)
+		if err != nil {
+			return nil, err
+		}
+
+		checks = append(checks, health.Database(storage.DB), health.Migrations(migrator))
+	}
+
+	checks = append(checks, health.Draining(lifecycle.Stopping))
+
+	return health.NewChecker(healthConfig.Timeout, logger, checks...), nil
+}
@@ -0,0 +1,111 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Hook is a pair of callbacks run when the application starts and stops.
+// Either callback may be nil.
+type Hook struct {
+	Name    string
+	OnStart func(ctx context.Context) error
+	OnStop  func(ctx context.Context) error
+}
+
+// Lifecycle is the registry of start/stop hooks. Hooks start in the order
+// they were appended and stop in reverse order.
+type Lifecycle struct {
+	mutex    sync.Mutex
+	hooks    []Hook
+	started  int
+	stopping bool
+}
+
+func NewLifecycle() *Lifecycle {
+	return &Lifecycle{}
+}
+
+func (lifecycle *Lifecycle) Append(hook Hook) {
+	lifecycle.mutex.Lock()
+	defer lifecycle.mutex.Unlock()
+
+	lifecycle.hooks = append(lifecycle.hooks, hook)
+}
+
+// Start runs every OnStart callback. When one fails, the hooks started so far
+// are stopped again and the error is returned.
+func (lifecycle *Lifecycle) Start(ctx context.Context) error {
+	lifecycle.mutex.Lock()
+	hooks := lifecycle.hooks
+	lifecycle.mutex.Unlock()
+
+	for _, hook := range hooks[lifecycle.started:] {
+		if hook.OnStart != nil {
+			err := hook.OnStart(ctx)
+			if err != nil {
+				err = fmt.Errorf("start %s: %w", hook.Name, err)
+				return joinErrors([]error{err, lifecycle.Stop(ctx)})
+			}
+		}
+
+		lifecycle.mutex.Lock()
+		lifecycle.started++
+		lifecycle.mutex.Unlock()
+	}
+
+	return nil
+}
+
+// Stop runs the OnStop callbacks of the started hooks in reverse order. Every
+// hook is stopped even if an earlier one fails; the errors are joined.
+func (lifecycle *Lifecycle) Stop(ctx context.Context) error {
+	lifecycle.mutex.Lock()
+	lifecycle.stopping = true
+	hooks := lifecycle.hooks[:lifecycle.started]
+	lifecycle.started = 0
+	lifecycle.mutex.Unlock()
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if hooks[i].OnStop != nil {
+			err := hooks[i].OnStop(ctx)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("stop %s: %w", hooks[i].Name, err))
+			}
+		}
+	}
+
+	return joinErrors(errs)
+}
+
+// Stopping reports whether Stop has been called, e.g. to fail readiness
+// checks while in-flight requests drain.
+func (lifecycle *Lifecycle) Stopping() bool {
+	lifecycle.mutex.Lock()
+	defer lifecycle.mutex.Unlock()
+
+	return lifecycle.stopping
+}
+
+func joinErrors(errs []error) error {
+	var failed []error
+	var messages []string
+	for _, err := range errs {
+		if err != nil {
+			failed = append(failed, err)
+			messages = append(messages, err.Error())
+		}
+	}
+
+	switch len(failed) {
+	case 0:
+		return nil
+	case 1:
+		return failed[0]
+	default:
+		return fmt.Errorf("%s", strings.Join(messages, "; "))
+	}
+}
@@ -0,0 +1,91 @@
+package app
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+
+	"sudutkampus/gorestfulapi/auth"
+	"sudutkampus/gorestfulapi/config"
+	"sudutkampus/gorestfulapi/exception"
+	"sudutkampus/gorestfulapi/logging"
+	"sudutkampus/gorestfulapi/ratelimit"
+)
+
+// rateLimiter returns limit, which takes a token from the bucket of the
+// client for the route group of a handler before calling it, answering
+// 429 Too Many Requests when none is left. Every response carries the
+// X-RateLimit-* headers. A failing store lets requests through.
+func rateLimiter(rateLimitConfig config.RateLimitConfig, store ratelimit.Store, logger *logging.Logger) func(group string, next httprouter.Handle) httprouter.Handle {
+	return func(group string, next httprouter.Handle) httprouter.Handle {
+		if !rateLimitConfig.Enabled {
+			return next
+		}
+
+		limit := rateLimitConfig.Default
+		if groupLimit, ok := rateLimitConfig.Groups[group]; ok {
+			limit = groupLimit
+		}
+		bucketLimit := ratelimit.Limit{Rate: limit.Rate, Burst: limit.Burst}
+
+		return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+			result, err := store.Take(r.Context(), group+"|"+rateLimitClient(r), bucketLimit)
+			if err != nil {
+				logger.Error(r.Context(), "rate limit store failed", "error", err)
+				next(w, r, params)
+				return
+			}
+
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(bucketLimit.Burst))
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			w.Header().Set("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+
+			if !result.Allowed {
+				retryAfter := ceilSeconds(result.RetryAfter)
+				if retryAfter < 1 {
+					retryAfter = 1
+				}
+				w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+				exception.WriteError(w, r, exception.NewTooManyRequestsError("rate limit exceeded, retry in "+strconv.Itoa(retryAfter)+"s"))
+				return
+			}
+
+			next(w, r, params)
+		}
+	}
+}
+
+// rateLimitClient names the client of a request by the principal that
+// authenticated it, such as api_key:3 or user:12, or by its IP address.
+func rateLimitClient(r *http.Request) string {
+	if principal, ok := auth.FromContext(r.Context()); ok {
+		if principal.Id == "" {
+			return principal.Type
+		}
+		return principal.Type + ":" + principal.Id
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	return "ip:" + host
+}
+
+// routeGroup is the path segment after /api of a route pattern, such as
+// categories for /api/categories/:category.
+func routeGroup(path string) string {
+	segments := strings.SplitN(strings.TrimPrefix(path, "/api/"), "/", 2)
+
+	return segments[0]
+}
+
+func ceilSeconds(duration time.Duration) int {
+	return int(math.Ceil(duration.Seconds()))
+}
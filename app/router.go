@@ -1,22 +1,261 @@
 package app
 
 import (
+	"net/http"
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"sudutkampus/gorestfulapi/auth"
+	"sudutkampus/gorestfulapi/config"
 	"sudutkampus/gorestfulapi/controller"
 	"sudutkampus/gorestfulapi/exception"
+	"sudutkampus/gorestfulapi/logging"
+	"sudutkampus/gorestfulapi/ratelimit"
+	"sudutkampus/gorestfulapi/tracing"
 
 	"github.com/julienschmidt/httprouter"
 )
 
-func NewRouter(categoryController controller.CategoryController) *httprouter.Router {
-	router := httprouter.New()
+// RouterDependencies groups what every route shares: the router and rate
+// limit settings, the store of the rate limit counters, the logger and the
+// tracer.
+type RouterDependencies struct {
+	Config         config.RouterConfig
+	RateLimit      config.RateLimitConfig
+	RateLimitStore ratelimit.Store
+	Logger         *logging.Logger
+	Tracer         trace.Tracer
+}
+
+// Controllers groups the controllers the routes dispatch to.
+type Controllers struct {
+	CategoryController controller.CategoryController
+	ProductController  controller.ProductController
+	AuditController    controller.AuditController
+	ApiKeyController   controller.ApiKeyController
+	UserController     controller.UserController
+}
+
+func NewRouter(dependencies RouterDependencies, controllers Controllers) *httprouter.Router {
+	logger := dependencies.Logger
+	router := patternRouter{Router: httprouter.New(), limit: rateLimiter(dependencies.RateLimit, dependencies.RateLimitStore, logger)}
+	router.RedirectTrailingSlash = dependencies.Config.RedirectTrailingSlash
+	router.HandleMethodNotAllowed = dependencies.Config.HandleMethodNotAllowed
+
+	handle := errorHandler(logger, dependencies.Tracer)
+	categoryController := controllers.CategoryController
+	productController := controllers.ProductController
+	auditController := controllers.AuditController
+	apiKeyController := controllers.ApiKeyController
+	userController := controllers.UserController
+
+	canReadCategories := auth.Permission(auth.ScopeCategoriesRead)
+	canWriteCategories := auth.Permission(auth.ScopeCategoriesWrite)
+	canReadProducts := auth.Permission(auth.ScopeProductsRead)
+	canWriteProducts := auth.Permission(auth.ScopeProductsWrite)
+	canAdminApiKeys := auth.Permission(auth.ScopeApiKeysAdmin)
+	canAdminUsers := auth.AnyOf(auth.Role(auth.RoleAdmin), auth.Permission(auth.ScopeUsersAdmin))
+	canReadAudit := auth.Permission(auth.ScopeAuditRead)
+
+	router.GET("/api/categories", authorize(canReadCategories, handle(categoryController.FindAll)))
+	router.GET("/api/categories/:category", staticParam("category", map[string]httprouter.Handle{
+		"trash":  authorize(canWriteCategories, handle(categoryController.FindTrashed)),
+		"export": authorize(canReadCategories, handle(categoryController.Export)),
+		"tree":   authorize(canReadCategories, handle(categoryController.FindTree)),
+	}, authorize(canReadCategories, handle(categoryController.FindById))))
+	router.POST("/api/categories", authorize(canWriteCategories, handle(categoryController.Create)))
+	router.POST("/api/categories/:category", staticParam("category", map[string]httprouter.Handle{
+		"bulk":   authorize(canWriteCategories, handle(categoryController.Bulk)),
+		"import": authorize(canWriteCategories, handle(categoryController.Import)),
+	}, notFound(router.Router)))
+	router.PUT("/api/categories/:category", authorize(canWriteCategories, handle(categoryController.Update)))
+	router.PATCH("/api/categories/:category", authorize(canWriteCategories, handle(categoryController.Patch)))
+	router.DELETE("/api/categories/:category", authorize(canWriteCategories, handle(categoryController.Delete)))
+	router.POST("/api/categories/:category/restore", authorize(canWriteCategories, handle(categoryController.Restore)))
+	router.POST("/api/categories/:category/move", authorize(canWriteCategories, handle(categoryController.Move)))
+	router.GET("/api/categories/:category/:relation", staticParam("category", map[string]httprouter.Handle{
+		"by-slug": renameParam("relation", "slug", authorize(canReadCategories, handle(categoryController.FindBySlug))),
+	}, staticParam("relation", map[string]httprouter.Handle{
+		"children":  authorize(canReadCategories, handle(categoryController.FindChildren)),
+		"ancestors": authorize(canReadCategories, handle(categoryController.FindAncestors)),
+		"products":  authorize(canReadProducts, handle(productController.FindByCategory)),
+	}, notFound(router.Router))))
+
+	router.GET("/api/products", authorize(canReadProducts, handle(productController.FindAll)))
+	router.GET("/api/products/:product", authorize(canReadProducts, handle(productController.FindById)))
+	router.POST("/api/products", authorize(canWriteProducts, handle(productController.Create)))
+	router.PUT("/api/products/:product", authorize(canWriteProducts, handle(productController.Update)))
+	router.DELETE("/api/products/:product", authorize(canWriteProducts, handle(productController.Delete)))
+
+	router.GET("/api/audit", authorize(canReadAudit, handle(auditController.FindAll)))
+
+	router.GET("/api/api-keys", authorize(canAdminApiKeys, handle(apiKeyController.FindAll)))
+	router.POST("/api/api-keys", authorize(canAdminApiKeys, handle(apiKeyController.Create)))
+	router.POST("/api/api-keys/:apiKey/rotate", authorize(canAdminApiKeys, handle(apiKeyController.Rotate)))
+	router.DELETE("/api/api-keys/:apiKey", authorize(canAdminApiKeys, handle(apiKeyController.Revoke)))
+
+	router.POST("/api/auth/register", handle(userController.Register))
+	router.POST("/api/auth/login", handle(userController.Login))
+	router.POST("/api/auth/refresh", handle(userController.Refresh))
+	router.POST("/api/auth/logout", handle(userController.Logout))
+	router.PUT("/api/auth/password", handle(userController.ChangePassword))
+	router.GET("/api/auth/me", handle(userController.Me))
+
+	router.GET("/api/users", authorize(canAdminUsers, handle(userController.FindAll)))
+	router.PUT("/api/users/:user/roles", authorize(canAdminUsers, handle(userController.AssignRoles)))
+
+	router.PanicHandler = func(w http.ResponseWriter, r *http.Request, err interface{}) {
+		logger.Error(r.Context(), "panic", "panic", err, "stack", string(debug.Stack()))
+		exception.ErrorHandler(w, r, err)
+	}
+
+	return router.Router
+}
+
+// patternRouter registers handlers that pass the pattern they were
+// registered with to a response writer that records it, since httprouter
+// does not keep track of it. Each handler is rate limited by the group of
+// its pattern.
+type patternRouter struct {
+	*httprouter.Router
+	limit func(group string, next httprouter.Handle) httprouter.Handle
+}
+
+type routeSetter interface {
+	SetRoute(pattern string)
+}
+
+func (router patternRouter) Handle(method string, path string, handle httprouter.Handle) {
+	handle = router.limit(routeGroup(path), handle)
+	router.Router.Handle(method, path, func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		if setter, ok := w.(routeSetter); ok {
+			setter.SetRoute(path)
+		}
+		handle(w, r, params)
+	})
+}
+
+func (router patternRouter) GET(path string, handle httprouter.Handle) {
+	router.Handle(http.MethodGet, path, handle)
+}
+
+func (router patternRouter) POST(path string, handle httprouter.Handle) {
+	router.Handle(http.MethodPost, path, handle)
+}
+
+func (router patternRouter) PUT(path string, handle httprouter.Handle) {
+	router.Handle(http.MethodPut, path, handle)
+}
+
+func (router patternRouter) PATCH(path string, handle httprouter.Handle) {
+	router.Handle(http.MethodPatch, path, handle)
+}
+
+func (router patternRouter) DELETE(path string, handle httprouter.Handle) {
+	router.Handle(http.MethodDelete, path, handle)
+}
+
+// errorHandler returns handle, which adapts an error-returning controller
+// method to httprouter, writing any returned error through the exception
+// mapper and logging those answered with a 5xx status. Every call is
+// recorded in a span named after the controller method.
+func errorHandler(logger *logging.Logger, tracer trace.Tracer) func(handler func(w http.ResponseWriter, r *http.Request, params httprouter.Params) error) httprouter.Handle {
+	return func(handler func(w http.ResponseWriter, r *http.Request, params httprouter.Params) error) httprouter.Handle {
+		name := handlerName(handler)
+
+		return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+			ctx, span := tracer.Start(r.Context(), name)
+			r = r.WithContext(ctx)
+
+			err := handler(w, r, params)
+			tracing.End(span, err)
+			if err == nil {
+				return
+			}
+
+			webResponse := exception.WriteError(w, r, err)
+			if webResponse.Code >= http.StatusInternalServerError {
+				logger.Error(r.Context(), "request failed", "error", err)
+			}
+		}
+	}
+}
 
-	router.GET("/api/categories", categoryController.FindAll)
-	router.GET("/api/categories/:category", categoryController.FindById)
-	router.POST("/api/categories", categoryController.Create)
-	router.PUT("/api/categories/:category", categoryController.Update)
-	router.DELETE("/api/categories/:category", categoryController.Delete)
+// handlerName turns the name of a method value such as
+// sudutkampus/gorestfulapi/controller.CategoryController.FindAll-fm into
+// CategoryController.FindAll.
+func handlerName(handler interface{}) string {
+	name := runtime.FuncForPC(reflect.ValueOf(handler).Pointer()).Name()
+	name = strings.TrimSuffix(name[strings.LastIndex(name, "/")+1:], "-fm")
+
+	return name[strings.Index(name, ".")+1:]
+}
+
+// authorize evaluates policy against the authenticated principal before
+// calling next, rejecting anonymous requests with 401 and refused ones
+// with 403.
+func authorize(policy auth.Policy, next httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		principal, ok := auth.FromContext(r.Context())
+		if !ok {
+			exception.WriteError(w, r, exception.NewUnauthorizedError("not authenticated"))
+			return
+		}
+
+		err := policy(principal)
+		if err != nil {
+			exception.WriteError(w, r, exception.NewForbiddenError(err.Error()))
+			return
+		}
+
+		next(w, r, params)
+	}
+}
+
+// staticParam routes requests whose name parameter matches a key of static
+// to that handler and all others to next. httprouter cannot register a
+// static segment such as /api/categories/trash beside a parameter.
+func staticParam(name string, static map[string]httprouter.Handle, next httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		if handler, ok := static[params.ByName(name)]; ok {
+			handler(w, r, params)
+			return
+		}
+
+		next(w, r, params)
+	}
+}
+
+// renameParam passes the value of parameter from to next as parameter to,
+// for a handler reached through staticParam on a route whose parameter is
+// named for other handlers.
+func renameParam(from string, to string, next httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		renamed := make(httprouter.Params, len(params))
+		for i, param := range params {
+			if param.Key == from {
+				param.Key = to
+			}
+			renamed[i] = param
+		}
+
+		next(w, r, renamed)
+	}
+}
 
-	router.PanicHandler = exception.ErrorHandler
+// notFound answers like httprouter does for a path it has no route for,
+// for the parameter values staticParam does not route.
+func notFound(router *httprouter.Router) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		if router.NotFound != nil {
+			router.NotFound.ServeHTTP(w, r)
+			return
+		}
 
-	return router
+		http.NotFound(w, r)
+	}
 }
@@ -0,0 +1,59 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"sudutkampus/gorestfulapi/config"
+)
+
+func NewServer(serverConfig config.ServerConfig, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:         serverConfig.Addr,
+		Handler:      handler,
+		ReadTimeout:  serverConfig.ReadTimeout,
+		WriteTimeout: serverConfig.WriteTimeout,
+		IdleTimeout:  serverConfig.IdleTimeout,
+	}
+}
+
+// ServerHook binds the listener on start, so address errors abort the
+// startup, and drains in-flight requests on stop. Errors from serving after a
+// successful start are sent to errs. On stop it first keeps serving for
+// drainDelay, during which the lifecycle already reports that it is
+// stopping, so that /readyz fails and load balancers stop routing to it
+// before the listener closes.
+func ServerHook(server *http.Server, drainDelay time.Duration, errs chan<- error) Hook {
+	return Hook{
+		Name: "http server",
+		OnStart: func(ctx context.Context) error {
+			listener, err := net.Listen("tcp", server.Addr)
+			if err != nil {
+				return err
+			}
+
+			go func() {
+				err := server.Serve(listener)
+				if !errors.Is(err, http.ErrServerClosed) {
+					errs <- err
+				}
+			}()
+
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			timer := time.NewTimer(drainDelay)
+			defer timer.Stop()
+
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+			}
+
+			return server.Shutdown(ctx)
+		},
+	}
+}
@@ -0,0 +1,69 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"sudutkampus/gorestfulapi/config"
+	"sudutkampus/gorestfulapi/logging"
+	"sudutkampus/gorestfulapi/repository"
+)
+
+// Storage groups the repositories of one backend with the unit of work
+// that opens transactions for them.
+type Storage struct {
+	DB                 *sql.DB
+	UnitOfWork         repository.UnitOfWork
+	CategoryRepository repository.CategoryRepository
+	ApiKeyRepository   repository.ApiKeyRepository
+	UserRepository     repository.UserRepository
+	SessionRepository  repository.SessionRepository
+	ProductRepository  repository.ProductRepository
+	AuditLogRepository repository.AuditLogRepository
+}
+
+// NewStorage traces every category repository call and, on SQL backends,
+// every transaction and statement. The unit of work of either backend logs
+// its transactions; the repositories return their errors for the services
+// and the router to log.
+func NewStorage(databaseConfig config.DatabaseConfig, logger *logging.Logger, tracer trace.Tracer) *Storage {
+	if databaseConfig.Driver == config.DriverMemory {
+		return &Storage{
+			UnitOfWork:         repository.NewMemoryUnitOfWork(repository.NewMemoryDB(), logger),
+			CategoryRepository: repository.NewCategoryRepositoryTraced(repository.NewCategoryRepositoryMemory(), tracer),
+			ApiKeyRepository:   repository.NewApiKeyRepositoryMemory(),
+			UserRepository:     repository.NewUserRepositoryMemory(),
+			SessionRepository:  repository.NewSessionRepositoryMemory(),
+			ProductRepository:  repository.NewProductRepositoryMemory(),
+			AuditLogRepository: repository.NewAuditLogRepositoryMemory(),
+		}
+	}
+
+	db := NewDB(databaseConfig)
+
+	return &Storage{
+		DB:                 db,
+		UnitOfWork:         repository.NewSQLUnitOfWork(db, databaseConfig.Driver, logger, tracer),
+		CategoryRepository: repository.NewCategoryRepositoryTraced(repository.NewCategoryRepository(databaseConfig.Driver), tracer),
+		ApiKeyRepository:   repository.NewApiKeyRepository(),
+		UserRepository:     repository.NewUserRepository(),
+		SessionRepository:  repository.NewSessionRepository(),
+		ProductRepository:  repository.NewProductRepository(),
+		AuditLogRepository: repository.NewAuditLogRepository(),
+	}
+}
+
+// Hook closes the database pool when the application stops.
+func (storage *Storage) Hook() Hook {
+	return Hook{
+		Name: "storage",
+		OnStop: func(ctx context.Context) error {
+			if storage.DB == nil {
+				return nil
+			}
+			return storage.DB.Close()
+		},
+	}
+}
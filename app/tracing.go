@@ -0,0 +1,31 @@
+package app
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"sudutkampus/gorestfulapi/config"
+	"sudutkampus/gorestfulapi/tracing"
+)
+
+// NewTracer returns the tracer the layers record their spans with, and the
+// hook that flushes the spans not yet exported when the application stops.
+// A disabled tracing config gives a tracer that records nothing.
+func NewTracer(tracingConfig config.TracingConfig) (trace.Tracer, Hook, error) {
+	if !tracingConfig.Enabled {
+		return tracing.Noop(), Hook{Name: "tracing"}, nil
+	}
+
+	provider, err := tracing.NewProvider(tracingConfig)
+	if err != nil {
+		return nil, Hook{}, err
+	}
+
+	return tracing.Tracer(provider), Hook{
+		Name: "tracing",
+		OnStop: func(ctx context.Context) error {
+			return provider.Shutdown(ctx)
+		},
+	}, nil
+}
@@ -0,0 +1,65 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"sudutkampus/gorestfulapi/config"
+	"sudutkampus/gorestfulapi/logging"
+	"sudutkampus/gorestfulapi/service"
+)
+
+// TrashPurgeHook purges categories that have been in the trash for longer
+// than the retention period, once on start and then every purge interval,
+// until the application stops.
+func TrashPurgeHook(categoryService service.CategoryService, trashConfig config.TrashConfig, logger *logging.Logger) Hook {
+	var cancel context.CancelFunc
+	done := make(chan struct{})
+
+	purge := func(ctx context.Context) {
+		purged, err := categoryService.PurgeTrashed(ctx, time.Now().UTC().Add(-trashConfig.Retention))
+		if err != nil && ctx.Err() == nil {
+			logger.Error(ctx, "purge trash failed", "error", err)
+		}
+		if purged > 0 {
+			logger.Info(ctx, "purged trash", "categories", purged)
+		}
+	}
+
+	return Hook{
+		Name: "trash purge",
+		OnStart: func(ctx context.Context) error {
+			var purgeCtx context.Context
+			purgeCtx, cancel = context.WithCancel(context.Background())
+
+			go func() {
+				defer close(done)
+
+				ticker := time.NewTicker(trashConfig.PurgeInterval)
+				defer ticker.Stop()
+
+				for {
+					purge(purgeCtx)
+
+					select {
+					case <-purgeCtx.Done():
+						return
+					case <-ticker.C:
+					}
+				}
+			}()
+
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			cancel()
+
+			select {
+			case <-done:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	}
+}
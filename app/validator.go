@@ -0,0 +1,54 @@
+package app
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+
+	"sudutkampus/gorestfulapi/auth"
+	"sudutkampus/gorestfulapi/exception"
+	"sudutkampus/gorestfulapi/helper"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var (
+	validate     *validator.Validate
+	validateOnce sync.Once
+)
+
+// NewValidator returns the shared validator. Translations can only be
+// registered once on the translators in package exception, so every caller
+// gets the same instance.
+func NewValidator() *validator.Validate {
+	validateOnce.Do(func() {
+		validate = validator.New()
+
+		validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+			if name == "-" {
+				return ""
+			}
+			return name
+		})
+
+		err := exception.RegisterTranslations(validate)
+		helper.PanicIfError(err)
+
+		// scope accepts the scopes of package auth, so the list is not
+		// copied into a oneof tag
+		err = validate.RegisterValidation("scope", func(fieldLevel validator.FieldLevel) bool {
+			return auth.IsScope(fieldLevel.Field().String())
+		})
+		helper.PanicIfError(err)
+
+		known := strings.Join(auth.Scopes(), " ")
+		err = exception.RegisterTagTranslation(validate, "scope", map[string]string{
+			"en": "{0} must be one of [" + known + "]",
+			"id": "{0} harus berupa salah satu dari [" + known + "]",
+		})
+		helper.PanicIfError(err)
+	})
+
+	return validate
+}
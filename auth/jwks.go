@@ -0,0 +1,124 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+// verificationKey is a key usable for exactly one signing algorithm.
+type verificationKey struct {
+	Kid string
+	Alg string
+	Key interface{}
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	K   string `json:"k"`
+}
+
+// LoadJWKS reads a JSON Web Key Set from a local file. RSA keys verify RS256,
+// P-256 EC keys ES256 and symmetric (oct) keys HS256.
+func LoadJWKS(path string) ([]verificationKey, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	err = json.Unmarshal(content, &set)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	var keys []verificationKey
+	for i, key := range set.Keys {
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+
+		verification, err := key.verificationKey()
+		if err != nil {
+			return nil, fmt.Errorf("%s: key %d: %w", path, i, err)
+		}
+
+		if key.Alg != "" && key.Alg != verification.Alg {
+			return nil, fmt.Errorf("%s: key %d: unsupported alg %s", path, i, key.Alg)
+		}
+
+		keys = append(keys, verification)
+	}
+
+	return keys, nil
+}
+
+func (key jwk) verificationKey() (verificationKey, error) {
+	switch key.Kty {
+	case "RSA":
+		n, err := decodeBigInt(key.N)
+		if err != nil {
+			return verificationKey{}, err
+		}
+		e, err := decodeBigInt(key.E)
+		if err != nil {
+			return verificationKey{}, err
+		}
+
+		publicKey := &rsa.PublicKey{N: n, E: int(e.Int64())}
+		return verificationKey{Kid: key.Kid, Alg: "RS256", Key: publicKey}, nil
+	case "EC":
+		if key.Crv != "P-256" {
+			return verificationKey{}, fmt.Errorf("unsupported curve %s", key.Crv)
+		}
+		x, err := decodeBigInt(key.X)
+		if err != nil {
+			return verificationKey{}, err
+		}
+		y, err := decodeBigInt(key.Y)
+		if err != nil {
+			return verificationKey{}, err
+		}
+
+		publicKey := &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}
+		if !publicKey.Curve.IsOnCurve(x, y) {
+			return verificationKey{}, fmt.Errorf("point is not on curve %s", key.Crv)
+		}
+		return verificationKey{Kid: key.Kid, Alg: "ES256", Key: publicKey}, nil
+	case "oct":
+		secret, err := base64.RawURLEncoding.DecodeString(key.K)
+		if err != nil {
+			return verificationKey{}, err
+		}
+		return verificationKey{Kid: key.Kid, Alg: "HS256", Key: secret}, nil
+	default:
+		return verificationKey{}, fmt.Errorf("unsupported key type %s", key.Kty)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("empty key parameter")
+	}
+
+	return new(big.Int).SetBytes(raw), nil
+}
@@ -0,0 +1,156 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"sudutkampus/gorestfulapi/config"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const PrincipalJWT = "jwt"
+
+var ErrInvalidToken = errors.New("invalid token")
+
+type JWTVerifier struct {
+	Keys     []verificationKey
+	Issuer   string
+	Audience string
+	Leeway   time.Duration
+	Now      func() time.Time
+}
+
+// NewJWTVerifier returns nil when neither an HMAC secret nor a JWKS file is
+// configured, meaning bearer tokens are disabled.
+func NewJWTVerifier(jwtConfig config.JWTConfig) (*JWTVerifier, error) {
+	var keys []verificationKey
+
+	if jwtConfig.HMACSecret != "" {
+		keys = append(keys, verificationKey{Alg: "HS256", Key: []byte(jwtConfig.HMACSecret)})
+	}
+
+	if jwtConfig.JWKSFile != "" {
+		jwks, err := LoadJWKS(jwtConfig.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, jwks...)
+	}
+
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	return &JWTVerifier{
+		Keys:     keys,
+		Issuer:   jwtConfig.Issuer,
+		Audience: jwtConfig.Audience,
+		Leeway:   jwtConfig.Leeway,
+		Now:      time.Now,
+	}, nil
+}
+
+// Verify checks the signature, expiry, issuer and audience of a token and
+// returns the principal described by its claims.
+func (verifier *JWTVerifier) Verify(token string) (Principal, error) {
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{"HS256", "RS256", "ES256"}), jwt.WithoutClaimsValidation())
+
+	claims := jwt.MapClaims{}
+	_, err := parser.ParseWithClaims(token, claims, verifier.key)
+	if err != nil {
+		return Principal{}, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	err = verifier.validate(claims)
+	if err != nil {
+		return Principal{}, err
+	}
+
+	subject, _ := claims["sub"].(string)
+	name, _ := claims["name"].(string)
+	if name == "" {
+		name = subject
+	}
+
+	return Principal{
+		Type:   PrincipalJWT,
+		Id:     subject,
+		Name:   name,
+		Scopes: claimScopes(claims),
+		Claims: claims,
+	}, nil
+}
+
+func (verifier *JWTVerifier) key(token *jwt.Token) (interface{}, error) {
+	alg := token.Method.Alg()
+	kid, _ := token.Header["kid"].(string)
+
+	for _, key := range verifier.Keys {
+		if key.Alg != alg {
+			continue
+		}
+		if kid == "" || key.Kid == "" || key.Kid == kid {
+			return key.Key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no %s key with id %q", alg, kid)
+}
+
+func (verifier *JWTVerifier) validate(claims jwt.MapClaims) error {
+	now := verifier.Now()
+
+	if _, ok := claims["exp"]; !ok {
+		return fmt.Errorf("%w: missing exp", ErrInvalidToken)
+	}
+	if !claims.VerifyExpiresAt(now.Add(-verifier.Leeway).Unix(), true) {
+		return fmt.Errorf("%w: token is expired", ErrInvalidToken)
+	}
+	if !claims.VerifyNotBefore(now.Add(verifier.Leeway).Unix(), false) {
+		return fmt.Errorf("%w: token is not valid yet", ErrInvalidToken)
+	}
+	if verifier.Issuer != "" && !claims.VerifyIssuer(verifier.Issuer, true) {
+		return fmt.Errorf("%w: unexpected issuer", ErrInvalidToken)
+	}
+	if verifier.Audience != "" && !claims.VerifyAudience(verifier.Audience, true) {
+		return fmt.Errorf("%w: unexpected audience", ErrInvalidToken)
+	}
+
+	return nil
+}
+
+// claimScopes reads the space separated "scope" claim (RFC 8693) or a "scp"
+// or "scopes" array. Only scopes that can be granted are kept, so a token
+// claiming ScopeAll or an unknown scope gets nothing from it.
+func claimScopes(claims jwt.MapClaims) []string {
+	var claimed []string
+	if scope, ok := claims["scope"].(string); ok {
+		claimed = strings.Fields(scope)
+	} else {
+		for _, name := range []string{"scp", "scopes"} {
+			values, ok := claims[name].([]interface{})
+			if !ok {
+				continue
+			}
+
+			for _, value := range values {
+				if scope, ok := value.(string); ok {
+					claimed = append(claimed, scope)
+				}
+			}
+			break
+		}
+	}
+
+	var scopes []string
+	for _, scope := range claimed {
+		if IsScope(scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	return scopes
+}
@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Policy decides whether a principal may perform an operation, returning
+// an error that explains the refusal.
+type Policy func(principal Principal) error
+
+// Permission allows principals granted scope, either directly or through
+// one of their roles.
+func Permission(scope string) Policy {
+	return func(principal Principal) error {
+		if !principal.HasScope(scope) {
+			return fmt.Errorf("missing permission %s", scope)
+		}
+
+		return nil
+	}
+}
+
+// Role allows principals holding role.
+func Role(role string) Policy {
+	return func(principal Principal) error {
+		if !principal.HasRole(role) {
+			return fmt.Errorf("missing role %s", role)
+		}
+
+		return nil
+	}
+}
+
+// AnyOf allows principals satisfying at least one of policies.
+func AnyOf(policies ...Policy) Policy {
+	return func(principal Principal) error {
+		var reasons []string
+		for _, policy := range policies {
+			err := policy(principal)
+			if err == nil {
+				return nil
+			}
+			reasons = append(reasons, err.Error())
+		}
+
+		return fmt.Errorf("%s", strings.Join(reasons, " or "))
+	}
+}
+
+// AllOf allows principals satisfying every one of policies.
+func AllOf(policies ...Policy) Policy {
+	return func(principal Principal) error {
+		for _, policy := range policies {
+			err := policy(principal)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,54 @@
+package auth
+
+import "context"
+
+const (
+	PrincipalApiKey    = "api_key"
+	PrincipalBootstrap = "bootstrap"
+	PrincipalUser      = "user"
+)
+
+// Principal is the authenticated caller of a request. Users carry Roles,
+// with Scopes holding the permissions those roles grant. Claims holds the
+// verified token claims of bearer-authenticated callers and SessionId the
+// login session of users.
+type Principal struct {
+	Type      string
+	Id        string
+	Name      string
+	Roles     []string
+	Scopes    []string
+	Claims    map[string]interface{}
+	SessionId string
+}
+
+func (principal Principal) HasScope(scope string) bool {
+	for _, granted := range principal.Scopes {
+		if granted == scope || granted == ScopeAll {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (principal Principal) HasRole(role string) bool {
+	for _, granted := range principal.Roles {
+		if granted == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+type principalKey struct{}
+
+func NewContext(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+func FromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
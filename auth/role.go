@@ -0,0 +1,32 @@
+package auth
+
+const (
+	RoleViewer = "viewer"
+	RoleEditor = "editor"
+	RoleAdmin  = "admin"
+)
+
+// rolePermissions lists the scopes each role grants. Roles are cumulative,
+// every role includes the permissions of the ones before it.
+var rolePermissions = map[string][]string{
+	RoleViewer: {ScopeCategoriesRead, ScopeProductsRead},
+	RoleEditor: {ScopeCategoriesRead, ScopeCategoriesWrite, ScopeProductsRead, ScopeProductsWrite},
+	RoleAdmin:  scopes,
+}
+
+// RolePermissions returns the distinct scopes granted by roles, ignoring
+// unknown roles.
+func RolePermissions(roles []string) []string {
+	seen := map[string]bool{}
+	var permissions []string
+	for _, role := range roles {
+		for _, permission := range rolePermissions[role] {
+			if !seen[permission] {
+				seen[permission] = true
+				permissions = append(permissions, permission)
+			}
+		}
+	}
+
+	return permissions
+}
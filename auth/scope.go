@@ -0,0 +1,35 @@
+package auth
+
+const (
+	ScopeAll             = "*"
+	ScopeCategoriesRead  = "categories:read"
+	ScopeCategoriesWrite = "categories:write"
+	ScopeProductsRead    = "products:read"
+	ScopeProductsWrite   = "products:write"
+	ScopeApiKeysAdmin    = "api_keys:admin"
+	ScopeUsersAdmin      = "users:admin"
+	ScopeAuditRead       = "audit:read"
+)
+
+// scopes lists every scope that can be granted, which excludes ScopeAll,
+// the scope of the bootstrap key.
+var scopes = []string{
+	ScopeCategoriesRead, ScopeCategoriesWrite, ScopeProductsRead, ScopeProductsWrite,
+	ScopeApiKeysAdmin, ScopeUsersAdmin, ScopeAuditRead,
+}
+
+// Scopes returns every scope that can be granted.
+func Scopes() []string {
+	return append([]string(nil), scopes...)
+}
+
+// IsScope reports whether scope can be granted.
+func IsScope(scope string) bool {
+	for _, known := range scopes {
+		if scope == known {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,243 @@
+package config
+
+import (
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	DriverMySQL  = "mysql"
+	DriverSQLite = "sqlite3"
+	DriverMemory = "memory"
+)
+
+const (
+	ExporterOTLP = "otlp"
+	ExporterFile = "file"
+)
+
+const (
+	OnDeleteReject   = "reject"
+	OnDeleteReparent = "reparent"
+	OnDeleteCascade  = "cascade"
+)
+
+type Config struct {
+	Server      ServerConfig      `yaml:"server"`
+	Database    DatabaseConfig    `yaml:"database"`
+	Router      RouterConfig      `yaml:"router"`
+	Auth        AuthConfig        `yaml:"auth"`
+	Trash       TrashConfig       `yaml:"trash"`
+	Concurrency ConcurrencyConfig `yaml:"concurrency"`
+	Hierarchy   HierarchyConfig   `yaml:"hierarchy"`
+	Log         LogConfig         `yaml:"log"`
+	Metrics     MetricsConfig     `yaml:"metrics"`
+	Tracing     TracingConfig     `yaml:"tracing"`
+	Health      HealthConfig      `yaml:"health"`
+	RateLimit   RateLimitConfig   `yaml:"rate_limit"`
+}
+
+type ServerConfig struct {
+	Addr            string        `yaml:"addr" validate:"required,hostname_port"`
+	ReadTimeout     time.Duration `yaml:"read_timeout" validate:"min=0"`
+	WriteTimeout    time.Duration `yaml:"write_timeout" validate:"min=0"`
+	IdleTimeout     time.Duration `yaml:"idle_timeout" validate:"min=0"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" validate:"min=0"`
+	// DrainDelay is how long the server keeps serving after /readyz starts
+	// failing on shutdown, before it stops accepting connections.
+	DrainDelay time.Duration `yaml:"drain_delay" validate:"min=0"`
+}
+
+type DatabaseConfig struct {
+	Driver          string        `yaml:"driver" validate:"required,oneof=mysql sqlite3 memory"`
+	DSN             string        `yaml:"dsn" validate:"required_unless=Driver memory"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" validate:"min=0"`
+	MaxOpenConns    int           `yaml:"max_open_conns" validate:"min=0"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time" validate:"min=0"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" validate:"min=0"`
+	AutoMigrate     bool          `yaml:"auto_migrate"`
+}
+
+type RouterConfig struct {
+	RedirectTrailingSlash  bool `yaml:"redirect_trailing_slash"`
+	HandleMethodNotAllowed bool `yaml:"handle_method_not_allowed"`
+}
+
+type AuthConfig struct {
+	// APIKey is an optional bootstrap key granted every scope, meant for
+	// issuing the first stored keys. The change-me placeholder of the
+	// example config is refused so it is never deployed as is.
+	APIKey           string        `yaml:"api_key" validate:"ne=change-me"`
+	APIKeyHashCost   int           `yaml:"api_key_hash_cost" validate:"min=4,max=31"`
+	PasswordHashCost int           `yaml:"password_hash_cost" validate:"min=4,max=31"`
+	Session          SessionConfig `yaml:"session"`
+	JWT              JWTConfig     `yaml:"jwt"`
+}
+
+// SessionConfig sets the lifetime of the access and refresh tokens issued
+// when a user logs in.
+type SessionConfig struct {
+	AccessTokenTTL  time.Duration `yaml:"access_token_ttl" validate:"min=1s"`
+	RefreshTokenTTL time.Duration `yaml:"refresh_token_ttl" validate:"gtefield=AccessTokenTTL"`
+}
+
+// JWTConfig enables Authorization: Bearer tokens when an HMAC secret or a
+// JWKS file is set.
+type JWTConfig struct {
+	HMACSecret string        `yaml:"hmac_secret" validate:"omitempty,min=32"`
+	JWKSFile   string        `yaml:"jwks_file"`
+	Issuer     string        `yaml:"issuer"`
+	Audience   string        `yaml:"audience"`
+	Leeway     time.Duration `yaml:"leeway" validate:"min=0"`
+}
+
+// TrashConfig controls the background purge of deleted categories. Items
+// stay restorable for Retention; a zero Retention keeps them forever.
+type TrashConfig struct {
+	Retention     time.Duration `yaml:"retention" validate:"min=0"`
+	PurgeInterval time.Duration `yaml:"purge_interval" validate:"min=1s"`
+}
+
+// ConcurrencyConfig.RequireIfMatch rejects category updates and deletes
+// without an If-Match header with 428 Precondition Required. It is off by
+// default so that existing clients keep working; a stale If-Match is
+// rejected either way.
+type ConcurrencyConfig struct {
+	RequireIfMatch bool `yaml:"require_if_match"`
+}
+
+// HierarchyConfig.OnDelete decides what deleting a category with children
+// does: reject it with 409 Conflict, reparent the children to the
+// category's own parent, or cascade to all its descendants.
+type HierarchyConfig struct {
+	OnDelete string `yaml:"on_delete" validate:"oneof=reject reparent cascade"`
+}
+
+// LogConfig sets the lowest level written to standard output and whether
+// every request is written to the access log.
+type LogConfig struct {
+	Level     string `yaml:"level" validate:"oneof=debug info warn error"`
+	AccessLog bool   `yaml:"access_log"`
+}
+
+// MetricsConfig serves Prometheus metrics at Path, without authentication,
+// when Enabled.
+type MetricsConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Path    string `yaml:"path" validate:"startswith=/"`
+}
+
+// TracingConfig sends spans to an OTLP/HTTP collector at Endpoint, or
+// writes them as JSON to File, when Enabled. SampleRatio is the share of
+// traces started here that are recorded; traces continued from a
+// traceparent header follow the caller's decision.
+type TracingConfig struct {
+	Enabled     bool    `yaml:"enabled"`
+	Exporter    string  `yaml:"exporter" validate:"oneof=otlp file"`
+	Endpoint    string  `yaml:"endpoint" validate:"required_if=Exporter otlp"`
+	Insecure    bool    `yaml:"insecure"`
+	File        string  `yaml:"file" validate:"required_if=Exporter file"`
+	ServiceName string  `yaml:"service_name" validate:"required"`
+	SampleRatio float64 `yaml:"sample_ratio" validate:"min=0,max=1"`
+}
+
+// HealthConfig.Timeout bounds each dependency check of the readiness and
+// health endpoints.
+type HealthConfig struct {
+	Timeout time.Duration `yaml:"timeout" validate:"min=1ms"`
+}
+
+// RateLimitConfig gives every client a token bucket per route group, the
+// path segment after /api such as categories or auth, refilled as Groups
+// sets for that group and as Default sets for the others. Clients are told
+// apart by API key or user, and anonymous ones by IP address. AuthFailures
+// limits the failed X-API-Key and Bearer authentications of each IP address,
+// which are rejected before any route group is known.
+type RateLimitConfig struct {
+	Enabled      bool                 `yaml:"enabled"`
+	Default      RateLimit            `yaml:"default"`
+	Groups       map[string]RateLimit `yaml:"groups" validate:"dive"`
+	AuthFailures RateLimit            `yaml:"auth_failures"`
+}
+
+// RateLimit allows Burst requests at once, refilled at Rate requests per
+// second.
+type RateLimit struct {
+	Rate  float64 `yaml:"rate" validate:"gt=0"`
+	Burst int     `yaml:"burst" validate:"min=1"`
+}
+
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Addr:            "localhost:3000",
+			ReadTimeout:     15 * time.Second,
+			WriteTimeout:    15 * time.Second,
+			IdleTimeout:     60 * time.Second,
+			ShutdownTimeout: 30 * time.Second,
+			DrainDelay:      5 * time.Second,
+		},
+		Database: DatabaseConfig{
+			Driver:          DriverMySQL,
+			DSN:             "root:root@tcp(localhost:8889)/gorestfulapi?parseTime=true",
+			MaxIdleConns:    5,
+			MaxOpenConns:    20,
+			ConnMaxIdleTime: 10 * time.Minute,
+			ConnMaxLifetime: 60 * time.Minute,
+		},
+		Router: RouterConfig{
+			RedirectTrailingSlash:  true,
+			HandleMethodNotAllowed: true,
+		},
+		Auth: AuthConfig{
+			APIKeyHashCost:   10,
+			PasswordHashCost: 10,
+			Session: SessionConfig{
+				AccessTokenTTL:  15 * time.Minute,
+				RefreshTokenTTL: 30 * 24 * time.Hour,
+			},
+		},
+		Trash: TrashConfig{
+			Retention:     30 * 24 * time.Hour,
+			PurgeInterval: time.Hour,
+		},
+		Hierarchy: HierarchyConfig{
+			OnDelete: OnDeleteReject,
+		},
+		Log: LogConfig{
+			Level:     "info",
+			AccessLog: true,
+		},
+		Metrics: MetricsConfig{
+			Enabled: true,
+			Path:    "/metrics",
+		},
+		Tracing: TracingConfig{
+			Exporter:    ExporterOTLP,
+			Endpoint:    "localhost:4318",
+			Insecure:    true,
+			ServiceName: "gorestfulapi",
+			SampleRatio: 1,
+		},
+		Health: HealthConfig{
+			Timeout: 2 * time.Second,
+		},
+		RateLimit: RateLimitConfig{
+			Enabled: true,
+			Default: RateLimit{Rate: 10, Burst: 50},
+			Groups: map[string]RateLimit{
+				"auth": {Rate: 1, Burst: 10},
+			},
+			AuthFailures: RateLimit{Rate: 0.2, Burst: 10},
+		},
+	}
+}
+
+func (config Config) Validate() error {
+	return validator.New().Struct(config)
+}
+
+func (databaseConfig DatabaseConfig) Validate() error {
+	return validator.New().Struct(databaseConfig)
+}
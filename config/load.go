@@ -0,0 +1,153 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const EnvPrefix = "GORESTFULAPI_"
+
+// Load builds the configuration from, in increasing order of precedence, the
+// defaults, the config file (YAML or JSON), GORESTFULAPI_* environment
+// variables and command-line flags, and validates it.
+func Load(args []string) (Config, error) {
+	config, err := Parse(args)
+	if err != nil {
+		return config, err
+	}
+
+	err = config.Validate()
+	if err != nil {
+		return config, fmt.Errorf("invalid config: %w", err)
+	}
+
+	return config, nil
+}
+
+// Parse is Load without validation, for commands that only need part of
+// the configuration.
+func Parse(args []string) (Config, error) {
+	config := Default()
+	var path string
+
+	flags := NewFlagSet(&config, &path)
+	err := flags.Parse(args)
+	if err != nil {
+		return config, err
+	}
+
+	if path == "" {
+		path = os.Getenv(envName("config"))
+	}
+
+	if path != "" {
+		err = loadFile(path, &config)
+		if err != nil {
+			return config, err
+		}
+	}
+
+	err = loadEnv(flags)
+	if err != nil {
+		return config, err
+	}
+
+	// parse again so flags win over the file and environment
+	err = flags.Parse(args)
+
+	return config, err
+}
+
+func NewFlagSet(config *Config, path *string) *flag.FlagSet {
+	flags := flag.NewFlagSet("gorestfulapi", flag.ContinueOnError)
+
+	flags.StringVar(path, "config", "", "path to a YAML or JSON config file")
+	flags.StringVar(&config.Server.Addr, "server-addr", config.Server.Addr, "address the HTTP server listens on")
+	flags.DurationVar(&config.Server.ReadTimeout, "server-read-timeout", config.Server.ReadTimeout, "maximum duration for reading a request")
+	flags.DurationVar(&config.Server.WriteTimeout, "server-write-timeout", config.Server.WriteTimeout, "maximum duration for writing a response")
+	flags.DurationVar(&config.Server.IdleTimeout, "server-idle-timeout", config.Server.IdleTimeout, "maximum idle time of a keep-alive connection")
+	flags.DurationVar(&config.Server.ShutdownTimeout, "server-shutdown-timeout", config.Server.ShutdownTimeout, "deadline for draining in-flight requests on shutdown")
+	flags.DurationVar(&config.Server.DrainDelay, "server-drain-delay", config.Server.DrainDelay, "time to keep serving on shutdown while /readyz fails")
+	flags.StringVar(&config.Database.Driver, "database-driver", config.Database.Driver, "database driver: mysql, sqlite3 or memory")
+	flags.StringVar(&config.Database.DSN, "database-dsn", config.Database.DSN, "database connection string")
+	flags.IntVar(&config.Database.MaxIdleConns, "database-max-idle-conns", config.Database.MaxIdleConns, "maximum idle connections")
+	flags.IntVar(&config.Database.MaxOpenConns, "database-max-open-conns", config.Database.MaxOpenConns, "maximum open connections")
+	flags.DurationVar(&config.Database.ConnMaxIdleTime, "database-conn-max-idle-time", config.Database.ConnMaxIdleTime, "maximum idle time of a connection")
+	flags.DurationVar(&config.Database.ConnMaxLifetime, "database-conn-max-lifetime", config.Database.ConnMaxLifetime, "maximum lifetime of a connection")
+	flags.BoolVar(&config.Database.AutoMigrate, "database-auto-migrate", config.Database.AutoMigrate, "apply pending migrations on startup")
+	flags.BoolVar(&config.Router.RedirectTrailingSlash, "router-redirect-trailing-slash", config.Router.RedirectTrailingSlash, "redirect paths with a trailing slash")
+	flags.BoolVar(&config.Router.HandleMethodNotAllowed, "router-handle-method-not-allowed", config.Router.HandleMethodNotAllowed, "answer 405 for known paths with another method")
+	flags.StringVar(&config.Auth.APIKey, "auth-api-key", config.Auth.APIKey, "bootstrap API key granted every scope")
+	flags.IntVar(&config.Auth.APIKeyHashCost, "auth-api-key-hash-cost", config.Auth.APIKeyHashCost, "bcrypt cost of stored API keys")
+	flags.IntVar(&config.Auth.PasswordHashCost, "auth-password-hash-cost", config.Auth.PasswordHashCost, "bcrypt cost of user passwords")
+	flags.DurationVar(&config.Auth.Session.AccessTokenTTL, "auth-session-access-token-ttl", config.Auth.Session.AccessTokenTTL, "lifetime of session access tokens")
+	flags.DurationVar(&config.Auth.Session.RefreshTokenTTL, "auth-session-refresh-token-ttl", config.Auth.Session.RefreshTokenTTL, "lifetime of session refresh tokens")
+	flags.StringVar(&config.Auth.JWT.HMACSecret, "auth-jwt-hmac-secret", config.Auth.JWT.HMACSecret, "secret verifying HS256 bearer tokens")
+	flags.StringVar(&config.Auth.JWT.JWKSFile, "auth-jwt-jwks-file", config.Auth.JWT.JWKSFile, "JWKS file with keys verifying bearer tokens")
+	flags.StringVar(&config.Auth.JWT.Issuer, "auth-jwt-issuer", config.Auth.JWT.Issuer, "required iss claim of bearer tokens")
+	flags.StringVar(&config.Auth.JWT.Audience, "auth-jwt-audience", config.Auth.JWT.Audience, "required aud claim of bearer tokens")
+	flags.DurationVar(&config.Auth.JWT.Leeway, "auth-jwt-leeway", config.Auth.JWT.Leeway, "allowed clock skew for exp and nbf")
+	flags.DurationVar(&config.Trash.Retention, "trash-retention", config.Trash.Retention, "how long deleted categories stay restorable, 0 keeps them forever")
+	flags.DurationVar(&config.Trash.PurgeInterval, "trash-purge-interval", config.Trash.PurgeInterval, "how often expired trash is purged")
+	flags.BoolVar(&config.Concurrency.RequireIfMatch, "concurrency-require-if-match", config.Concurrency.RequireIfMatch, "require If-Match on category updates and deletes")
+	flags.StringVar(&config.Hierarchy.OnDelete, "hierarchy-on-delete", config.Hierarchy.OnDelete, "what deleting a category with children does: reject, reparent or cascade")
+	flags.StringVar(&config.Log.Level, "log-level", config.Log.Level, "lowest level logged: debug, info, warn or error")
+	flags.BoolVar(&config.Log.AccessLog, "log-access-log", config.Log.AccessLog, "log every request")
+	flags.BoolVar(&config.Metrics.Enabled, "metrics-enabled", config.Metrics.Enabled, "serve Prometheus metrics")
+	flags.StringVar(&config.Metrics.Path, "metrics-path", config.Metrics.Path, "path of the Prometheus metrics, served without authentication")
+	flags.BoolVar(&config.Tracing.Enabled, "tracing-enabled", config.Tracing.Enabled, "export OpenTelemetry traces")
+	flags.StringVar(&config.Tracing.Exporter, "tracing-exporter", config.Tracing.Exporter, "where spans are sent: otlp or file")
+	flags.StringVar(&config.Tracing.Endpoint, "tracing-endpoint", config.Tracing.Endpoint, "host:port of the OTLP/HTTP collector")
+	flags.BoolVar(&config.Tracing.Insecure, "tracing-insecure", config.Tracing.Insecure, "send spans to the collector without TLS")
+	flags.StringVar(&config.Tracing.File, "tracing-file", config.Tracing.File, "file spans are written to as JSON by the file exporter")
+	flags.StringVar(&config.Tracing.ServiceName, "tracing-service-name", config.Tracing.ServiceName, "service.name of the exported spans")
+	flags.Float64Var(&config.Tracing.SampleRatio, "tracing-sample-ratio", config.Tracing.SampleRatio, "share of new traces recorded, from 0 to 1")
+	flags.DurationVar(&config.Health.Timeout, "health-timeout", config.Health.Timeout, "deadline of each dependency check of /readyz and /health")
+	flags.BoolVar(&config.RateLimit.Enabled, "rate-limit-enabled", config.RateLimit.Enabled, "limit requests per client and route group")
+	flags.Float64Var(&config.RateLimit.Default.Rate, "rate-limit-rate", config.RateLimit.Default.Rate, "requests per second refilled for route groups without their own limit")
+	flags.IntVar(&config.RateLimit.Default.Burst, "rate-limit-burst", config.RateLimit.Default.Burst, "requests allowed at once for route groups without their own limit")
+
+	return flags
+}
+
+func loadFile(path string, config *Config) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	// JSON is valid YAML, so one decoder handles both formats
+	err = yaml.Unmarshal(content, config)
+	if err != nil {
+		return fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func loadEnv(flags *flag.FlagSet) error {
+	var err error
+	flags.VisitAll(func(f *flag.Flag) {
+		if err != nil || f.Name == "config" {
+			return
+		}
+
+		value, ok := os.LookupEnv(envName(f.Name))
+		if ok {
+			if errSet := f.Value.Set(value); errSet != nil {
+				err = fmt.Errorf("%s: %w", envName(f.Name), errSet)
+			}
+		}
+	})
+
+	return err
+}
+
+// envName maps a flag such as database-dsn to GORESTFULAPI_DATABASE_DSN.
+func envName(flagName string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
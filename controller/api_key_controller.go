@@ -0,0 +1,14 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type ApiKeyController interface {
+	Create(w http.ResponseWriter, r *http.Request, params httprouter.Params) error
+	Rotate(w http.ResponseWriter, r *http.Request, params httprouter.Params) error
+	Revoke(w http.ResponseWriter, r *http.Request, params httprouter.Params) error
+	FindAll(w http.ResponseWriter, r *http.Request, params httprouter.Params) error
+}
@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"net/http"
+
+	"sudutkampus/gorestfulapi/exception"
+	"sudutkampus/gorestfulapi/helper"
+	"sudutkampus/gorestfulapi/model/web"
+	"sudutkampus/gorestfulapi/service"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type ApiKeyControllerImpl struct {
+	ApiKeyService service.ApiKeyService
+}
+
+func NewApiKeyController(apiKeyService service.ApiKeyService) ApiKeyController {
+	return &ApiKeyControllerImpl{
+		ApiKeyService: apiKeyService,
+	}
+}
+
+func (ctrl *ApiKeyControllerImpl) Create(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	apiKeyCreateRequest := web.ApiKeyCreateRequest{}
+	err := helper.ReadFromRequestBody(r, &apiKeyCreateRequest)
+	if err != nil {
+		return exception.NewValidationError("invalid request body")
+	}
+
+	apiKeyResponse, err := ctrl.ApiKeyService.Issue(r.Context(), apiKeyCreateRequest)
+	if err != nil {
+		return err
+	}
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   apiKeyResponse,
+	}
+
+	return helper.WriteToResponseBody(w, webResponse)
+}
+
+func (ctrl *ApiKeyControllerImpl) Rotate(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	apiKeyId, err := idParam(params, "apiKey", "api key not found")
+	if err != nil {
+		return err
+	}
+
+	apiKeyResponse, err := ctrl.ApiKeyService.Rotate(r.Context(), apiKeyId)
+	if err != nil {
+		return err
+	}
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   apiKeyResponse,
+	}
+
+	return helper.WriteToResponseBody(w, webResponse)
+}
+
+func (ctrl *ApiKeyControllerImpl) Revoke(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	apiKeyId, err := idParam(params, "apiKey", "api key not found")
+	if err != nil {
+		return err
+	}
+
+	err = ctrl.ApiKeyService.Revoke(r.Context(), apiKeyId)
+	if err != nil {
+		return err
+	}
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+	}
+
+	return helper.WriteToResponseBody(w, webResponse)
+}
+
+func (ctrl *ApiKeyControllerImpl) FindAll(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	apiKeyResponses, err := ctrl.ApiKeyService.FindAll(r.Context())
+	if err != nil {
+		return err
+	}
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   apiKeyResponses,
+	}
+
+	return helper.WriteToResponseBody(w, webResponse)
+}
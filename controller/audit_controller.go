@@ -0,0 +1,11 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type AuditController interface {
+	FindAll(w http.ResponseWriter, r *http.Request, params httprouter.Params) error
+}
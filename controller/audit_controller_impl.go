@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"net/http"
+
+	"sudutkampus/gorestfulapi/helper"
+	"sudutkampus/gorestfulapi/model/web"
+	"sudutkampus/gorestfulapi/service"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type AuditControllerImpl struct {
+	AuditService service.AuditService
+}
+
+func NewAuditController(auditService service.AuditService) AuditController {
+	return &AuditControllerImpl{
+		AuditService: auditService,
+	}
+}
+
+// FindAll lists audit entries newest first, filtered by entity and id,
+// actor, action, and a since/until time range.
+func (ctrl *AuditControllerImpl) FindAll(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	query := r.URL.Query()
+	auditLogFindAllRequest := web.AuditLogFindAllRequest{
+		Entity: query.Get("entity"),
+		Actor:  query.Get("actor"),
+		Action: query.Get("action"),
+	}
+
+	var err error
+	auditLogFindAllRequest.Page, err = queryInt(query, "page")
+	if err != nil {
+		return err
+	}
+
+	auditLogFindAllRequest.PerPage, err = queryInt(query, "per_page")
+	if err != nil {
+		return err
+	}
+
+	auditLogFindAllRequest.Id, err = queryInt(query, "id")
+	if err != nil {
+		return err
+	}
+
+	auditLogFindAllRequest.Since, err = queryTime(query, "since")
+	if err != nil {
+		return err
+	}
+
+	auditLogFindAllRequest.Until, err = queryTime(query, "until")
+	if err != nil {
+		return err
+	}
+
+	auditLogResponses, pageMeta, err := ctrl.AuditService.FindAll(r.Context(), auditLogFindAllRequest)
+	if err != nil {
+		return err
+	}
+
+	pageMeta.Links = helper.ToPageLinks(r, pageMeta, false)
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   auditLogResponses,
+		Meta:   pageMeta,
+	}
+
+	return helper.WriteToResponseBody(w, webResponse)
+}
@@ -7,9 +7,20 @@ import (
 )
 
 type CategoryController interface {
-	Create(w http.ResponseWriter, r *http.Request, params httprouter.Params)
-	Update(w http.ResponseWriter, r *http.Request, params httprouter.Params)
-	Delete(w http.ResponseWriter, r *http.Request, params httprouter.Params)
-	FindById(w http.ResponseWriter, r *http.Request, params httprouter.Params)
-	FindAll(w http.ResponseWriter, r *http.Request, params httprouter.Params)
+	Create(w http.ResponseWriter, r *http.Request, params httprouter.Params) error
+	Update(w http.ResponseWriter, r *http.Request, params httprouter.Params) error
+	Patch(w http.ResponseWriter, r *http.Request, params httprouter.Params) error
+	Delete(w http.ResponseWriter, r *http.Request, params httprouter.Params) error
+	Bulk(w http.ResponseWriter, r *http.Request, params httprouter.Params) error
+	Move(w http.ResponseWriter, r *http.Request, params httprouter.Params) error
+	Restore(w http.ResponseWriter, r *http.Request, params httprouter.Params) error
+	FindById(w http.ResponseWriter, r *http.Request, params httprouter.Params) error
+	FindBySlug(w http.ResponseWriter, r *http.Request, params httprouter.Params) error
+	FindChildren(w http.ResponseWriter, r *http.Request, params httprouter.Params) error
+	FindAncestors(w http.ResponseWriter, r *http.Request, params httprouter.Params) error
+	FindTree(w http.ResponseWriter, r *http.Request, params httprouter.Params) error
+	Export(w http.ResponseWriter, r *http.Request, params httprouter.Params) error
+	Import(w http.ResponseWriter, r *http.Request, params httprouter.Params) error
+	FindAll(w http.ResponseWriter, r *http.Request, params httprouter.Params) error
+	FindTrashed(w http.ResponseWriter, r *http.Request, params httprouter.Params) error
 }
@@ -1,10 +1,20 @@
 package controller
 
 import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"mime"
 	"net/http"
+	"path"
 	"strconv"
+	"strings"
 
+	"sudutkampus/gorestfulapi/config"
+	"sudutkampus/gorestfulapi/exception"
 	"sudutkampus/gorestfulapi/helper"
+	"sudutkampus/gorestfulapi/logging"
 	"sudutkampus/gorestfulapi/model/web"
 	"sudutkampus/gorestfulapi/service"
 
@@ -13,81 +23,558 @@ import (
 
 type CategoryControllerImpl struct {
 	CategoryService service.CategoryService
+	RequireIfMatch  bool
+	Logger          *logging.Logger
 }
 
-func NewCategoryController(categoryService service.CategoryService) CategoryController {
+func NewCategoryController(categoryService service.CategoryService, concurrencyConfig config.ConcurrencyConfig, logger *logging.Logger) CategoryController {
 	return &CategoryControllerImpl{
 		CategoryService: categoryService,
+		RequireIfMatch:  concurrencyConfig.RequireIfMatch,
+		Logger:          logger,
 	}
 }
 
-func (ctrl *CategoryControllerImpl) Create(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+func (ctrl *CategoryControllerImpl) Create(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
 	categoryCreateRequest := web.CategoryCreateRequest{}
-	helper.ReadFromRequestBody(r, &categoryCreateRequest)
+	err := helper.ReadFromRequestBody(r, &categoryCreateRequest)
+	if err != nil {
+		return exception.NewValidationError("invalid request body")
+	}
+
+	categoryResponse, err := ctrl.CategoryService.Create(r.Context(), categoryCreateRequest)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("ETag", helper.ETag(categoryResponse.Version))
 
-	categoryResponse := ctrl.CategoryService.Create(r.Context(), categoryCreateRequest)
 	webResponse := web.WebResponse{
 		Code:   200,
 		Status: "OK",
 		Data:   categoryResponse,
 	}
 
-	helper.WriteToResponseBody(w, webResponse)
+	return helper.WriteToResponseBody(w, webResponse)
 }
 
-func (ctrl *CategoryControllerImpl) Update(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+func (ctrl *CategoryControllerImpl) Update(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
 	categoryUpdateRequest := web.CategoryUpdateRequest{}
-	helper.ReadFromRequestBody(r, &categoryUpdateRequest)
+	err := helper.ReadFromRequestBody(r, &categoryUpdateRequest)
+	if err != nil {
+		return exception.NewValidationError("invalid request body")
+	}
 
-	categoryId, err := strconv.Atoi(params.ByName("category"))
-	helper.PanicIfError(err)
+	categoryId, err := categoryIdParam(params)
+	if err != nil {
+		return err
+	}
 
 	categoryUpdateRequest.Id = categoryId
+	categoryUpdateRequest.Version, err = ifMatchVersion(r, ctrl.RequireIfMatch)
+	if err != nil {
+		return err
+	}
+
+	categoryResponse, err := ctrl.CategoryService.Update(r.Context(), categoryUpdateRequest)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("ETag", helper.ETag(categoryResponse.Version))
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   categoryResponse,
+	}
+
+	return helper.WriteToResponseBody(w, webResponse)
+}
+
+func (ctrl *CategoryControllerImpl) Patch(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	w.Header().Set("Accept-Patch", web.MergePatchMediaType+", "+web.JSONPatchMediaType)
+
+	categoryId, err := categoryIdParam(params)
+	if err != nil {
+		return err
+	}
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return exception.NewUnsupportedMediaTypeError("missing or invalid Content-Type")
+	}
+
+	version, err := ifMatchVersion(r, ctrl.RequireIfMatch)
+	if err != nil {
+		return err
+	}
+
+	patch, err := io.ReadAll(r.Body)
+	if err != nil {
+		return exception.NewValidationError("invalid request body")
+	}
+
+	categoryResponse, err := ctrl.CategoryService.Patch(r.Context(), web.CategoryPatchRequest{
+		Id:        categoryId,
+		Version:   version,
+		MediaType: mediaType,
+		Patch:     patch,
+	})
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("ETag", helper.ETag(categoryResponse.Version))
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   categoryResponse,
+	}
+
+	return helper.WriteToResponseBody(w, webResponse)
+}
+
+// Delete moves a category to the trash, or removes it for good with
+// ?purge=true.
+func (ctrl *CategoryControllerImpl) Delete(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	categoryId, err := categoryIdParam(params)
+	if err != nil {
+		return err
+	}
+
+	purge, err := queryBool(r.URL.Query(), "purge")
+	if err != nil {
+		return err
+	}
+
+	version, err := ifMatchVersion(r, ctrl.RequireIfMatch)
+	if err != nil {
+		return err
+	}
+
+	if purge {
+		err = ctrl.CategoryService.Purge(r.Context(), categoryId, version)
+	} else {
+		err = ctrl.CategoryService.Delete(r.Context(), categoryId, version)
+	}
+	if err != nil {
+		return err
+	}
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+	}
+
+	return helper.WriteToResponseBody(w, webResponse)
+}
+
+// Bulk answers 200 with a result for each operation. A failed atomic
+// request answers with the status of the operation that failed instead.
+func (ctrl *CategoryControllerImpl) Bulk(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	categoryBulkRequest := web.CategoryBulkRequest{}
+	err := helper.ReadFromRequestBody(r, &categoryBulkRequest)
+	if err != nil {
+		return exception.NewValidationError("invalid request body")
+	}
+
+	categoryBulkRequest.RequireVersion = ctrl.RequireIfMatch
+
+	results, err := ctrl.CategoryService.Bulk(r.Context(), categoryBulkRequest)
+	if err != nil {
+		return err
+	}
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   results,
+	}
+
+	translator := exception.Translator(r.Header.Get("Accept-Language"))
+	for i := range results {
+		if results[i].Err == nil {
+			results[i].Code = http.StatusOK
+			results[i].Status = "OK"
+			continue
+		}
+
+		errorResponse := exception.ToWebResponse(results[i].Err, translator)
+		if errorResponse.Code >= http.StatusInternalServerError {
+			ctrl.Logger.Error(r.Context(), "bulk operation failed", "index", i, "op", results[i].Op, "error", results[i].Err)
+		}
+		results[i].Code = errorResponse.Code
+		results[i].Status = errorResponse.Status
+		results[i].Error = errorResponse.Data
+
+		atomic := categoryBulkRequest.Mode != web.BulkModePartial
+		if atomic && (webResponse.Code == http.StatusOK || webResponse.Code == http.StatusFailedDependency) {
+			webResponse.Code = errorResponse.Code
+			webResponse.Status = errorResponse.Status
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(webResponse.Code)
+
+	return helper.WriteToResponseBody(w, webResponse)
+}
+
+func (ctrl *CategoryControllerImpl) Move(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	categoryMoveRequest := web.CategoryMoveRequest{}
+	err := helper.ReadFromRequestBody(r, &categoryMoveRequest)
+	if err != nil {
+		return exception.NewValidationError("invalid request body")
+	}
+
+	categoryMoveRequest.Id, err = categoryIdParam(params)
+	if err != nil {
+		return err
+	}
+
+	categoryMoveRequest.Version, err = ifMatchVersion(r, ctrl.RequireIfMatch)
+	if err != nil {
+		return err
+	}
+
+	categoryResponse, err := ctrl.CategoryService.Move(r.Context(), categoryMoveRequest)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("ETag", helper.ETag(categoryResponse.Version))
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   categoryResponse,
+	}
+
+	return helper.WriteToResponseBody(w, webResponse)
+}
+
+func (ctrl *CategoryControllerImpl) Restore(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	categoryId, err := categoryIdParam(params)
+	if err != nil {
+		return err
+	}
+
+	categoryResponse, err := ctrl.CategoryService.Restore(r.Context(), categoryId)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("ETag", helper.ETag(categoryResponse.Version))
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   categoryResponse,
+	}
+
+	return helper.WriteToResponseBody(w, webResponse)
+}
+
+func (ctrl *CategoryControllerImpl) FindById(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	categoryId, err := categoryIdParam(params)
+	if err != nil {
+		return err
+	}
+
+	categoryResponse, err := ctrl.CategoryService.FindById(r.Context(), categoryId)
+	if err != nil {
+		return err
+	}
+
+	etag := helper.ETag(categoryResponse.Version)
+	w.Header().Set("ETag", etag)
+	if notModified(w, r, etag) {
+		return nil
+	}
 
-	categoryResponse := ctrl.CategoryService.Update(r.Context(), categoryUpdateRequest)
 	webResponse := web.WebResponse{
 		Code:   200,
 		Status: "OK",
 		Data:   categoryResponse,
 	}
 
-	helper.WriteToResponseBody(w, webResponse)
+	return helper.WriteToResponseBody(w, webResponse)
 }
 
-func (ctrl *CategoryControllerImpl) Delete(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
-	categoryId, err := strconv.Atoi(params.ByName("category"))
-	helper.PanicIfError(err)
+func (ctrl *CategoryControllerImpl) FindChildren(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	return ctrl.findRelated(w, r, params, ctrl.CategoryService.FindChildren)
+}
+
+func (ctrl *CategoryControllerImpl) FindAncestors(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	return ctrl.findRelated(w, r, params, ctrl.CategoryService.FindAncestors)
+}
+
+func (ctrl *CategoryControllerImpl) findRelated(w http.ResponseWriter, r *http.Request, params httprouter.Params, find func(ctx context.Context, categoryId int) ([]web.CategoryResponse, error)) error {
+	categoryId, err := categoryIdParam(params)
+	if err != nil {
+		return err
+	}
+
+	categoryResponses, err := find(r.Context(), categoryId)
+	if err != nil {
+		return err
+	}
 
-	ctrl.CategoryService.Delete(r.Context(), categoryId)
 	webResponse := web.WebResponse{
 		Code:   200,
 		Status: "OK",
+		Data:   categoryResponses,
+	}
+
+	return helper.WriteToResponseBody(w, webResponse)
+}
+
+func (ctrl *CategoryControllerImpl) FindTree(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	categoryTree, err := ctrl.CategoryService.FindTree(r.Context())
+	if err != nil {
+		return err
+	}
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   categoryTree,
+	}
+
+	return helper.WriteToResponseBody(w, webResponse)
+}
+
+// Export streams categories as CSV or JSON Lines. Once the first row is
+// written the status can no longer change, so a later failure only cuts
+// the response short.
+func (ctrl *CategoryControllerImpl) Export(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	query := r.URL.Query()
+	categoryExportRequest := web.CategoryExportRequest{
+		Format: query.Get("format"),
+		Sort:   query.Get("sort"),
+		Q:      query.Get("q"),
+	}
+	if categoryExportRequest.Format == "" {
+		categoryExportRequest.Format = web.FormatCSV
+	}
+
+	begin, writeRow, flush := categoryExportWriter(w, categoryExportRequest.Format)
+
+	started := false
+	start := func() error {
+		started = true
+		return begin()
+	}
+
+	err := ctrl.CategoryService.Export(r.Context(), categoryExportRequest, func(category web.CategoryResponse) error {
+		if !started {
+			err := start()
+			if err != nil {
+				return err
+			}
+		}
+		return writeRow(category)
+	})
+	if err != nil && started {
+		ctrl.Logger.Error(r.Context(), "export categories failed", "error", err)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if !started {
+		err = start()
+		if err != nil {
+			return err
+		}
+	}
+
+	return flush()
+}
+
+// categoryExportWriter returns functions that set the headers and write
+// the CSV header row, write one category, and flush what is buffered.
+func categoryExportWriter(w http.ResponseWriter, format string) (func() error, func(category web.CategoryResponse) error, func() error) {
+	if format == web.FormatJSONL {
+		encoder := json.NewEncoder(w)
+		begin := func() error {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			w.Header().Set("Content-Disposition", `attachment; filename="categories.jsonl"`)
+			return nil
+		}
+		flush := func() error {
+			return nil
+		}
+
+		return begin, func(category web.CategoryResponse) error { return encoder.Encode(category) }, flush
+	}
+
+	writer := csv.NewWriter(w)
+	begin := func() error {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="categories.csv"`)
+		return writer.Write([]string{"id", "name", "slug", "parent_id", "version"})
+	}
+	writeRow := func(category web.CategoryResponse) error {
+		parentId := ""
+		if category.ParentId != nil {
+			parentId = strconv.Itoa(*category.ParentId)
+		}
+		return writer.Write([]string{strconv.Itoa(category.Id), category.Name, category.Slug, parentId, strconv.Itoa(category.Version)})
+	}
+	flush := func() error {
+		writer.Flush()
+		return writer.Error()
+	}
+
+	return begin, writeRow, flush
+}
+
+// Import reads the file part of a multipart upload. The format comes from
+// the format query parameter or else the file extension, and dry_run=true
+// only validates the rows. Row errors answer 400 with the whole report.
+func (ctrl *CategoryControllerImpl) Import(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	query := r.URL.Query()
+	dryRun, err := queryBool(query, "dry_run")
+	if err != nil {
+		return err
+	}
+
+	err = r.ParseMultipartForm(importMemoryLimit)
+	if err == http.ErrNotMultipart {
+		return exception.NewUnsupportedMediaTypeError("import expects a multipart/form-data upload")
+	}
+	if err != nil {
+		return exception.NewValidationError("invalid request body")
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, fileHeader, err := r.FormFile("file")
+	if err != nil {
+		return exception.NewFieldValidationError("file", "file is required")
+	}
+	defer file.Close()
+
+	format := query.Get("format")
+	if format == "" {
+		format = importFormat(fileHeader.Filename)
+	}
+
+	response, err := ctrl.CategoryService.Import(r.Context(), web.CategoryImportRequest{
+		Format: format,
+		DryRun: dryRun,
+		File:   file,
+	})
+	if err != nil {
+		return err
+	}
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   response,
+	}
+
+	if response.Failed > 0 {
+		translator := exception.Translator(r.Header.Get("Accept-Language"))
+		for i := range response.Errors {
+			response.Errors[i].Errors = exception.ToWebResponse(response.Errors[i].Err, translator).Data
+		}
+
+		webResponse.Code = http.StatusBadRequest
+		webResponse.Status = http.StatusText(http.StatusBadRequest)
 	}
 
-	helper.WriteToResponseBody(w, webResponse)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(webResponse.Code)
+
+	return helper.WriteToResponseBody(w, webResponse)
+}
+
+// importMemoryLimit is how much of an upload is kept in memory; the rest
+// is buffered in temporary files.
+const importMemoryLimit = 10 << 20
+
+func importFormat(filename string) string {
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".jsonl", ".ndjson":
+		return web.FormatJSONL
+	case ".csv":
+		return web.FormatCSV
+	default:
+		return ""
+	}
 }
 
-func (ctrl *CategoryControllerImpl) FindById(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
-	categoryId, err := strconv.Atoi(params.ByName("category"))
-	helper.PanicIfError(err)
+func (ctrl *CategoryControllerImpl) FindBySlug(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	categoryResponse, err := ctrl.CategoryService.FindBySlug(r.Context(), params.ByName("slug"))
+	if err != nil {
+		return err
+	}
+
+	etag := helper.ETag(categoryResponse.Version)
+	w.Header().Set("ETag", etag)
+	if notModified(w, r, etag) {
+		return nil
+	}
 
-	categoryResponse := ctrl.CategoryService.FindById(r.Context(), categoryId)
 	webResponse := web.WebResponse{
 		Code:   200,
 		Status: "OK",
 		Data:   categoryResponse,
 	}
 
-	helper.WriteToResponseBody(w, webResponse)
+	return helper.WriteToResponseBody(w, webResponse)
+}
+
+func (ctrl *CategoryControllerImpl) FindAll(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	return ctrl.findAll(w, r, ctrl.CategoryService.FindAll)
+}
+
+func (ctrl *CategoryControllerImpl) FindTrashed(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	return ctrl.findAll(w, r, ctrl.CategoryService.FindTrashed)
 }
 
-func (ctrl *CategoryControllerImpl) FindAll(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
-	categoryResponses := ctrl.CategoryService.FindAll(r.Context())
+func (ctrl *CategoryControllerImpl) findAll(w http.ResponseWriter, r *http.Request, find func(ctx context.Context, request web.CategoryFindAllRequest) ([]web.CategoryResponse, web.PageMeta, error)) error {
+	query := r.URL.Query()
+	page, err := queryInt(query, "page")
+	if err != nil {
+		return err
+	}
+
+	perPage, err := queryInt(query, "per_page")
+	if err != nil {
+		return err
+	}
+
+	categoryFindAllRequest := web.CategoryFindAllRequest{
+		Page:    page,
+		PerPage: perPage,
+		After:   query.Get("after"),
+		Sort:    query.Get("sort"),
+		Q:       query.Get("q"),
+	}
+
+	categoryResponses, pageMeta, err := find(r.Context(), categoryFindAllRequest)
+	if err != nil {
+		return err
+	}
+
+	pageMeta.Links = helper.ToPageLinks(r, pageMeta, categoryFindAllRequest.After != "")
+
 	webResponse := web.WebResponse{
 		Code:   200,
 		Status: "OK",
 		Data:   categoryResponses,
+		Meta:   pageMeta,
 	}
 
-	helper.WriteToResponseBody(w, webResponse)
+	return helper.WriteToResponseBody(w, webResponse)
+}
+
+func categoryIdParam(params httprouter.Params) (int, error) {
+	return idParam(params, "category", "category not found")
 }
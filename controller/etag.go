@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"net/http"
+	"strings"
+
+	"sudutkampus/gorestfulapi/exception"
+	"sudutkampus/gorestfulapi/helper"
+)
+
+// ifMatchVersion returns the version a request expects to modify, read from
+// If-Match. A missing header or "*" returns zero, which skips the check,
+// unless the header is required.
+func ifMatchVersion(r *http.Request, required bool) (int, error) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" {
+		if required {
+			return 0, exception.NewPreconditionRequiredError("If-Match header is required")
+		}
+		return 0, nil
+	}
+
+	if header == "*" {
+		return 0, nil
+	}
+
+	if strings.Contains(header, ",") {
+		return 0, exception.NewFieldValidationError("If-Match", "If-Match must hold a single entity tag")
+	}
+
+	version, ok := helper.ETagVersion(header)
+	if !ok {
+		return 0, exception.NewPreconditionFailedError("category has been modified")
+	}
+
+	return version, nil
+}
+
+// notModified answers 304 when If-None-Match already lists etag.
+func notModified(w http.ResponseWriter, r *http.Request, etag string) bool {
+	if !helper.MatchETag(r.Header.Get("If-None-Match"), etag) {
+		return false
+	}
+
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+
+	"sudutkampus/gorestfulapi/exception"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// idParam reads a numeric path parameter; anything else cannot match a
+// record, so it is reported as not found.
+func idParam(params httprouter.Params, name string, notFoundMessage string) (int, error) {
+	id, err := strconv.Atoi(params.ByName(name))
+	if err != nil {
+		return 0, exception.NewNotFoundError(notFoundMessage)
+	}
+
+	return id, nil
+}
+
+func queryInt(query url.Values, key string) (int, error) {
+	value := query.Get(key)
+	if value == "" {
+		return 0, nil
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, exception.NewFieldValidationError(key, key+" must be a number")
+	}
+
+	return number, nil
+}
+
+func queryBool(query url.Values, key string) (bool, error) {
+	value := query.Get(key)
+	if value == "" {
+		return false, nil
+	}
+
+	flag, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, exception.NewFieldValidationError(key, key+" must be true or false")
+	}
+
+	return flag, nil
+}
+
+// queryTime parses an RFC 3339 timestamp such as 2022-10-15T08:00:00Z.
+func queryTime(query url.Values, key string) (*time.Time, error) {
+	value := query.Get(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, exception.NewFieldValidationError(key, key+" must be an RFC 3339 timestamp")
+	}
+
+	return &t, nil
+}
@@ -0,0 +1,16 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type ProductController interface {
+	Create(w http.ResponseWriter, r *http.Request, params httprouter.Params) error
+	Update(w http.ResponseWriter, r *http.Request, params httprouter.Params) error
+	Delete(w http.ResponseWriter, r *http.Request, params httprouter.Params) error
+	FindById(w http.ResponseWriter, r *http.Request, params httprouter.Params) error
+	FindAll(w http.ResponseWriter, r *http.Request, params httprouter.Params) error
+	FindByCategory(w http.ResponseWriter, r *http.Request, params httprouter.Params) error
+}
@@ -0,0 +1,172 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	"sudutkampus/gorestfulapi/exception"
+	"sudutkampus/gorestfulapi/helper"
+	"sudutkampus/gorestfulapi/model/web"
+	"sudutkampus/gorestfulapi/service"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type ProductControllerImpl struct {
+	ProductService service.ProductService
+}
+
+func NewProductController(productService service.ProductService) ProductController {
+	return &ProductControllerImpl{
+		ProductService: productService,
+	}
+}
+
+func (ctrl *ProductControllerImpl) Create(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	productCreateRequest := web.ProductCreateRequest{}
+	err := helper.ReadFromRequestBody(r, &productCreateRequest)
+	if err != nil {
+		return exception.NewValidationError("invalid request body")
+	}
+
+	productResponse, err := ctrl.ProductService.Create(r.Context(), productCreateRequest)
+	if err != nil {
+		return err
+	}
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   productResponse,
+	}
+
+	return helper.WriteToResponseBody(w, webResponse)
+}
+
+func (ctrl *ProductControllerImpl) Update(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	productUpdateRequest := web.ProductUpdateRequest{}
+	err := helper.ReadFromRequestBody(r, &productUpdateRequest)
+	if err != nil {
+		return exception.NewValidationError("invalid request body")
+	}
+
+	productUpdateRequest.Id, err = productIdParam(params)
+	if err != nil {
+		return err
+	}
+
+	productResponse, err := ctrl.ProductService.Update(r.Context(), productUpdateRequest)
+	if err != nil {
+		return err
+	}
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   productResponse,
+	}
+
+	return helper.WriteToResponseBody(w, webResponse)
+}
+
+func (ctrl *ProductControllerImpl) Delete(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	productId, err := productIdParam(params)
+	if err != nil {
+		return err
+	}
+
+	err = ctrl.ProductService.Delete(r.Context(), productId)
+	if err != nil {
+		return err
+	}
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+	}
+
+	return helper.WriteToResponseBody(w, webResponse)
+}
+
+func (ctrl *ProductControllerImpl) FindById(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	productId, err := productIdParam(params)
+	if err != nil {
+		return err
+	}
+
+	productResponse, err := ctrl.ProductService.FindById(r.Context(), productId)
+	if err != nil {
+		return err
+	}
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   productResponse,
+	}
+
+	return helper.WriteToResponseBody(w, webResponse)
+}
+
+// FindAll lists products, optionally of one category given as category_id.
+func (ctrl *ProductControllerImpl) FindAll(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	categoryId, err := queryInt(r.URL.Query(), "category_id")
+	if err != nil {
+		return err
+	}
+
+	return ctrl.findAll(w, r, func(ctx context.Context, request web.ProductFindAllRequest) ([]web.ProductResponse, web.PageMeta, error) {
+		request.CategoryId = categoryId
+		return ctrl.ProductService.FindAll(ctx, request)
+	})
+}
+
+func (ctrl *ProductControllerImpl) FindByCategory(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	categoryId, err := categoryIdParam(params)
+	if err != nil {
+		return err
+	}
+
+	return ctrl.findAll(w, r, func(ctx context.Context, request web.ProductFindAllRequest) ([]web.ProductResponse, web.PageMeta, error) {
+		return ctrl.ProductService.FindByCategory(ctx, categoryId, request)
+	})
+}
+
+func (ctrl *ProductControllerImpl) findAll(w http.ResponseWriter, r *http.Request, find func(ctx context.Context, request web.ProductFindAllRequest) ([]web.ProductResponse, web.PageMeta, error)) error {
+	query := r.URL.Query()
+	page, err := queryInt(query, "page")
+	if err != nil {
+		return err
+	}
+
+	perPage, err := queryInt(query, "per_page")
+	if err != nil {
+		return err
+	}
+
+	productFindAllRequest := web.ProductFindAllRequest{
+		Page:    page,
+		PerPage: perPage,
+		Q:       query.Get("q"),
+	}
+
+	productResponses, pageMeta, err := find(r.Context(), productFindAllRequest)
+	if err != nil {
+		return err
+	}
+
+	pageMeta.Links = helper.ToPageLinks(r, pageMeta, false)
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   productResponses,
+		Meta:   pageMeta,
+	}
+
+	return helper.WriteToResponseBody(w, webResponse)
+}
+
+func productIdParam(params httprouter.Params) (int, error) {
+	return idParam(params, "product", "product not found")
+}
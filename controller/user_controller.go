@@ -0,0 +1,18 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type UserController interface {
+	Register(w http.ResponseWriter, r *http.Request, params httprouter.Params) error
+	Login(w http.ResponseWriter, r *http.Request, params httprouter.Params) error
+	Refresh(w http.ResponseWriter, r *http.Request, params httprouter.Params) error
+	Logout(w http.ResponseWriter, r *http.Request, params httprouter.Params) error
+	ChangePassword(w http.ResponseWriter, r *http.Request, params httprouter.Params) error
+	Me(w http.ResponseWriter, r *http.Request, params httprouter.Params) error
+	FindAll(w http.ResponseWriter, r *http.Request, params httprouter.Params) error
+	AssignRoles(w http.ResponseWriter, r *http.Request, params httprouter.Params) error
+}
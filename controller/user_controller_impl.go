@@ -0,0 +1,216 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"sudutkampus/gorestfulapi/auth"
+	"sudutkampus/gorestfulapi/exception"
+	"sudutkampus/gorestfulapi/helper"
+	"sudutkampus/gorestfulapi/model/web"
+	"sudutkampus/gorestfulapi/service"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type UserControllerImpl struct {
+	UserService service.UserService
+}
+
+func NewUserController(userService service.UserService) UserController {
+	return &UserControllerImpl{
+		UserService: userService,
+	}
+}
+
+func (ctrl *UserControllerImpl) Register(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	userRegisterRequest := web.UserRegisterRequest{}
+	err := helper.ReadFromRequestBody(r, &userRegisterRequest)
+	if err != nil {
+		return exception.NewValidationError("invalid request body")
+	}
+
+	userResponse, err := ctrl.UserService.Register(r.Context(), userRegisterRequest)
+	if err != nil {
+		return err
+	}
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   userResponse,
+	}
+
+	return helper.WriteToResponseBody(w, webResponse)
+}
+
+func (ctrl *UserControllerImpl) Login(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	userLoginRequest := web.UserLoginRequest{}
+	err := helper.ReadFromRequestBody(r, &userLoginRequest)
+	if err != nil {
+		return exception.NewValidationError("invalid request body")
+	}
+
+	tokenResponse, err := ctrl.UserService.Login(r.Context(), userLoginRequest)
+	if err != nil {
+		return err
+	}
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   tokenResponse,
+	}
+
+	return helper.WriteToResponseBody(w, webResponse)
+}
+
+func (ctrl *UserControllerImpl) Refresh(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	userRefreshRequest := web.UserRefreshRequest{}
+	err := helper.ReadFromRequestBody(r, &userRefreshRequest)
+	if err != nil {
+		return exception.NewValidationError("invalid request body")
+	}
+
+	tokenResponse, err := ctrl.UserService.Refresh(r.Context(), userRefreshRequest)
+	if err != nil {
+		return err
+	}
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   tokenResponse,
+	}
+
+	return helper.WriteToResponseBody(w, webResponse)
+}
+
+func (ctrl *UserControllerImpl) Logout(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	_, sessionId, err := userSession(r)
+	if err != nil {
+		return err
+	}
+
+	err = ctrl.UserService.Logout(r.Context(), sessionId)
+	if err != nil {
+		return err
+	}
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+	}
+
+	return helper.WriteToResponseBody(w, webResponse)
+}
+
+func (ctrl *UserControllerImpl) ChangePassword(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	userId, sessionId, err := userSession(r)
+	if err != nil {
+		return err
+	}
+
+	userChangePasswordRequest := web.UserChangePasswordRequest{}
+	err = helper.ReadFromRequestBody(r, &userChangePasswordRequest)
+	if err != nil {
+		return exception.NewValidationError("invalid request body")
+	}
+
+	err = ctrl.UserService.ChangePassword(r.Context(), userId, sessionId, userChangePasswordRequest)
+	if err != nil {
+		return err
+	}
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+	}
+
+	return helper.WriteToResponseBody(w, webResponse)
+}
+
+func (ctrl *UserControllerImpl) Me(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	userId, _, err := userSession(r)
+	if err != nil {
+		return err
+	}
+
+	userResponse, err := ctrl.UserService.Me(r.Context(), userId)
+	if err != nil {
+		return err
+	}
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   userResponse,
+	}
+
+	return helper.WriteToResponseBody(w, webResponse)
+}
+
+func (ctrl *UserControllerImpl) FindAll(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	userResponses, err := ctrl.UserService.FindAll(r.Context())
+	if err != nil {
+		return err
+	}
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   userResponses,
+	}
+
+	return helper.WriteToResponseBody(w, webResponse)
+}
+
+func (ctrl *UserControllerImpl) AssignRoles(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	userId, err := idParam(params, "user", "user not found")
+	if err != nil {
+		return err
+	}
+
+	userRolesRequest := web.UserRolesRequest{}
+	err = helper.ReadFromRequestBody(r, &userRolesRequest)
+	if err != nil {
+		return exception.NewValidationError("invalid request body")
+	}
+
+	userResponse, err := ctrl.UserService.AssignRoles(r.Context(), userId, userRolesRequest)
+	if err != nil {
+		return err
+	}
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   userResponse,
+	}
+
+	return helper.WriteToResponseBody(w, webResponse)
+}
+
+// userSession returns the user and session ids of a request authenticated
+// with a session access token.
+func userSession(r *http.Request) (int, int, error) {
+	principal, ok := auth.FromContext(r.Context())
+	if !ok {
+		return 0, 0, exception.NewUnauthorizedError("not authenticated")
+	}
+	if principal.Type != auth.PrincipalUser {
+		return 0, 0, exception.NewForbiddenError("only available to logged-in users")
+	}
+
+	userId, err := strconv.Atoi(principal.Id)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	sessionId, err := strconv.Atoi(principal.SessionId)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return userId, sessionId, nil
+}
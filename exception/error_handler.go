@@ -1,73 +1,143 @@
 package exception
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"reflect"
 
 	"sudutkampus/gorestfulapi/helper"
 	"sudutkampus/gorestfulapi/model/web"
 
-	"github.com/go-playground/validator/v10"
+	ut "github.com/go-playground/universal-translator"
 )
 
+// ErrorHandler is installed as the router PanicHandler and only deals with
+// unexpected panics; regular failures are returned as errors and go through WriteError.
 func ErrorHandler(w http.ResponseWriter, r *http.Request, err interface{}) {
-	if notFoundError(w, r, err) {
-		return
-	}
+	WriteError(w, r, NewInternalError(fmt.Errorf("%v", err)))
+}
+
+// errorRecorder is a response writer of a middleware that wants to know
+// the error a response reports, such as to count errors by type.
+type errorRecorder interface {
+	SetError(err error)
+}
 
-	if validationError(w, r, err) {
-		return
+// WriteError answers with the response err maps to and returns it, so that
+// callers can act on its status without mapping err again.
+func WriteError(w http.ResponseWriter, r *http.Request, err error) web.WebResponse {
+	if recorder, ok := w.(errorRecorder); ok {
+		recorder.SetError(err)
 	}
 
-	internalServerError(w, r, err)
-}
+	webResponse := ToWebResponse(err, Translator(r.Header.Get("Accept-Language")))
 
-func internalServerError(w http.ResponseWriter, r *http.Request, err interface{}) {
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusInternalServerError)
+	w.WriteHeader(webResponse.Code)
+
+	helper.WriteToResponseBody(w, webResponse)
+
+	return webResponse
+}
 
-	webResponse := web.WebResponse{
-		Code:   http.StatusInternalServerError,
-		Status: "Internal Status Error",
-		Data:   err,
+func ToWebResponse(err error, translator ut.Translator) web.WebResponse {
+	var notFoundError NotFoundError
+	var validationError ValidationError
+	var conflictError ConflictError
+	var unauthorizedError UnauthorizedError
+	var forbiddenError ForbiddenError
+	var preconditionFailedError PreconditionFailedError
+	var preconditionRequiredError PreconditionRequiredError
+	var unsupportedMediaTypeError UnsupportedMediaTypeError
+	var failedDependencyError FailedDependencyError
+	var tooManyRequestsError TooManyRequestsError
+
+	switch {
+	case errors.As(err, &notFoundError):
+		return errorResponse(http.StatusNotFound, notFoundError.Message)
+	case errors.As(err, &validationError):
+		return errorResponse(http.StatusBadRequest, toFieldErrorResponses(validationError, translator))
+	case errors.As(err, &conflictError):
+		return errorResponse(http.StatusConflict, conflictError.Message)
+	case errors.As(err, &unauthorizedError):
+		return errorResponse(http.StatusUnauthorized, unauthorizedError.Message)
+	case errors.As(err, &forbiddenError):
+		return errorResponse(http.StatusForbidden, forbiddenError.Message)
+	case errors.As(err, &preconditionFailedError):
+		return errorResponse(http.StatusPreconditionFailed, preconditionFailedError.Message)
+	case errors.As(err, &preconditionRequiredError):
+		return errorResponse(http.StatusPreconditionRequired, preconditionRequiredError.Message)
+	case errors.As(err, &unsupportedMediaTypeError):
+		return errorResponse(http.StatusUnsupportedMediaType, unsupportedMediaTypeError.Message)
+	case errors.As(err, &failedDependencyError):
+		return errorResponse(http.StatusFailedDependency, failedDependencyError.Message)
+	case errors.As(err, &tooManyRequestsError):
+		return errorResponse(http.StatusTooManyRequests, tooManyRequestsError.Message)
+	default:
+		// the error itself may name tables, queries or hosts; callers log it
+		return errorResponse(http.StatusInternalServerError, "internal server error")
 	}
+}
 
-	helper.WriteToResponseBody(w, webResponse)
+// TypeOf names the exception type err is mapped by, such as NotFoundError,
+// or InternalError for errors that are not one.
+func TypeOf(err error) string {
+	target := clientError(err)
+	if target == nil {
+		return "InternalError"
+	}
+
+	return reflect.TypeOf(target).Elem().Name()
+}
+
+// IsInternal reports whether err is none of the exceptions a client causes,
+// so that it is answered with 500 Internal Server Error.
+func IsInternal(err error) bool {
+	return clientError(err) == nil
 }
 
-func notFoundError(w http.ResponseWriter, r *http.Request, err interface{}) bool {
-	exception, ok := err.(NotFoundError)
-	if ok {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotFound)
+// clientError returns the exception err is mapped by, or nil.
+func clientError(err error) interface{} {
+	targets := []interface{}{
+		&NotFoundError{}, &ValidationError{}, &ConflictError{}, &UnauthorizedError{}, &ForbiddenError{},
+		&PreconditionFailedError{}, &PreconditionRequiredError{}, &UnsupportedMediaTypeError{}, &FailedDependencyError{},
+		&TooManyRequestsError{},
+	}
 
-		webResponse := web.WebResponse{
-			Code:   http.StatusNotFound,
-			Status: "Not Found",
-			Data:   exception.Error,
+	for _, target := range targets {
+		if errors.As(err, target) {
+			return target
 		}
-
-		helper.WriteToResponseBody(w, webResponse)
-		return true
-	} else {
-		return false
 	}
+
+	return nil
 }
 
-func validationError(w http.ResponseWriter, r *http.Request, err interface{}) bool {
-	exception, ok := err.(validator.ValidationErrors)
-	if ok {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
+func errorResponse(code int, data interface{}) web.WebResponse {
+	return web.WebResponse{
+		Code:   code,
+		Status: http.StatusText(code),
+		Data:   data,
+	}
+}
 
-		webResponse := web.WebResponse{
-			Code:   http.StatusBadRequest,
-			Status: "Bad Request",
-			Data:   exception.Error(),
+func toFieldErrorResponses(validationError ValidationError, translator ut.Translator) []web.FieldErrorResponse {
+	if len(validationError.Fields) == 0 {
+		return []web.FieldErrorResponse{
+			{Field: validationError.Field, Message: validationError.Message},
 		}
+	}
 
-		helper.WriteToResponseBody(w, webResponse)
-		return true
-	} else {
-		return false
+	var fieldErrorResponses []web.FieldErrorResponse
+	for _, fieldError := range validationError.Fields {
+		fieldErrorResponses = append(fieldErrorResponses, web.FieldErrorResponse{
+			Field:   fieldError.Field(),
+			Tag:     fieldError.Tag(),
+			Param:   fieldError.Param(),
+			Message: fieldError.Translate(translator),
+		})
 	}
+
+	return fieldErrorResponses
 }
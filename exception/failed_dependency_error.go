@@ -0,0 +1,13 @@
+package exception
+
+type FailedDependencyError struct {
+	Message string
+}
+
+func NewFailedDependencyError(message string) FailedDependencyError {
+	return FailedDependencyError{Message: message}
+}
+
+func (e FailedDependencyError) Error() string {
+	return e.Message
+}
@@ -0,0 +1,17 @@
+package exception
+
+type InternalError struct {
+	Err error
+}
+
+func NewInternalError(err error) InternalError {
+	return InternalError{Err: err}
+}
+
+func (e InternalError) Error() string {
+	return e.Err.Error()
+}
+
+func (e InternalError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,13 @@
+package exception
+
+type PreconditionRequiredError struct {
+	Message string
+}
+
+func NewPreconditionRequiredError(message string) PreconditionRequiredError {
+	return PreconditionRequiredError{Message: message}
+}
+
+func (e PreconditionRequiredError) Error() string {
+	return e.Message
+}
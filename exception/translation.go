@@ -0,0 +1,105 @@
+package exception
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/id"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	enTranslations "github.com/go-playground/validator/v10/translations/en"
+	idTranslations "github.com/go-playground/validator/v10/translations/id"
+)
+
+var universalTranslator = ut.New(en.New(), en.New(), id.New())
+
+// RegisterTranslations registers the validation messages of every supported
+// locale on validate, so its field errors can be rendered by WriteError.
+func RegisterTranslations(validate *validator.Validate) error {
+	enTranslator, _ := universalTranslator.GetTranslator("en")
+	err := enTranslations.RegisterDefaultTranslations(validate, enTranslator)
+	if err != nil {
+		return err
+	}
+
+	idTranslator, _ := universalTranslator.GetTranslator("id")
+	return idTranslations.RegisterDefaultTranslations(validate, idTranslator)
+}
+
+// RegisterTagTranslation registers the message of a custom validation tag
+// in every supported locale, messages being keyed by locale. {0} in a
+// message stands for the field.
+func RegisterTagTranslation(validate *validator.Validate, tag string, messages map[string]string) error {
+	for locale, message := range messages {
+		translator, found := universalTranslator.GetTranslator(locale)
+		if !found {
+			continue
+		}
+
+		message := message
+		err := validate.RegisterTranslation(tag, translator, func(translator ut.Translator) error {
+			return translator.Add(tag, message, true)
+		}, func(translator ut.Translator, fieldError validator.FieldError) string {
+			translated, _ := translator.T(tag, fieldError.Field())
+			return translated
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Translator picks the best supported translator for an Accept-Language
+// header, falling back to English.
+func Translator(acceptLanguage string) ut.Translator {
+	translator, _ := universalTranslator.FindTranslator(parseAcceptLanguage(acceptLanguage)...)
+	return translator
+}
+
+func parseAcceptLanguage(header string) []string {
+	type language struct {
+		tag     string
+		quality float64
+	}
+
+	var languages []language
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.TrimSpace(fields[0])
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				value, err := strconv.ParseFloat(param[2:], 64)
+				if err == nil {
+					quality = value
+				}
+			}
+		}
+
+		languages = append(languages, language{tag: tag, quality: quality})
+	}
+
+	sort.SliceStable(languages, func(i, j int) bool {
+		return languages[i].quality > languages[j].quality
+	})
+
+	var locales []string
+	for _, language := range languages {
+		tag := strings.ReplaceAll(language.tag, "-", "_")
+		locales = append(locales, tag)
+		if base := strings.Split(tag, "_")[0]; base != tag {
+			locales = append(locales, base)
+		}
+	}
+
+	return locales
+}
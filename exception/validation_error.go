@@ -0,0 +1,36 @@
+package exception
+
+import (
+	"errors"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type ValidationError struct {
+	Message string
+	Field   string
+	Fields  validator.ValidationErrors
+}
+
+func NewValidationError(message string) ValidationError {
+	return ValidationError{Message: message}
+}
+
+func NewFieldValidationError(field string, message string) ValidationError {
+	return ValidationError{Message: message, Field: field}
+}
+
+// FromValidator converts the result of validator.Struct into a ValidationError,
+// leaving any other error untouched.
+func FromValidator(err error) error {
+	var fields validator.ValidationErrors
+	if errors.As(err, &fields) {
+		return ValidationError{Message: fields.Error(), Fields: fields}
+	}
+
+	return err
+}
+
+func (e ValidationError) Error() string {
+	return e.Message
+}
@@ -0,0 +1,137 @@
+// Package health serves the liveness, readiness and detailed health
+// endpoints probed by orchestrators.
+package health
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"sudutkampus/gorestfulapi/helper"
+	"sudutkampus/gorestfulapi/logging"
+	"sudutkampus/gorestfulapi/migration"
+	"sudutkampus/gorestfulapi/model/web"
+)
+
+// Check probes one dependency and returns why it is unavailable.
+type Check struct {
+	Name  string
+	Probe func(ctx context.Context) error
+}
+
+// Database pings the connection pool.
+func Database(db *sql.DB) Check {
+	return Check{Name: "database", Probe: db.PingContext}
+}
+
+// Migrations fails while any migration has not been applied.
+func Migrations(migrator *migration.Migrator) Check {
+	return Check{Name: "migrations", Probe: func(ctx context.Context) error {
+		pending, err := migrator.Pending(ctx)
+		if err != nil {
+			return err
+		}
+		if pending > 0 {
+			return fmt.Errorf("%d migrations pending", pending)
+		}
+
+		return nil
+	}}
+}
+
+// Draining fails once the application has started shutting down, so that
+// no new traffic is routed to it while in-flight requests finish.
+func Draining(stopping func() bool) Check {
+	return Check{Name: "lifecycle", Probe: func(ctx context.Context) error {
+		if stopping() {
+			return errors.New("shutting down")
+		}
+
+		return nil
+	}}
+}
+
+// unavailable is all a failed check reports, since /health is served
+// without authentication; why it failed is logged instead.
+const unavailable = "unavailable"
+
+// Checker runs its checks one after another, each within Timeout.
+type Checker struct {
+	Checks  []Check
+	Timeout time.Duration
+	Logger  *logging.Logger
+}
+
+func NewChecker(timeout time.Duration, logger *logging.Logger, checks ...Check) *Checker {
+	return &Checker{Checks: checks, Timeout: timeout, Logger: logger}
+}
+
+// Run reports the outcome and latency of every check.
+func (checker *Checker) Run(ctx context.Context) web.HealthResponse {
+	response := web.HealthResponse{Status: web.HealthUp}
+	for _, check := range checker.Checks {
+		result := checker.run(ctx, check)
+		if result.Status != web.HealthUp {
+			response.Status = web.HealthDown
+		}
+		response.Checks = append(response.Checks, result)
+	}
+
+	return response
+}
+
+func (checker *Checker) run(ctx context.Context, check Check) web.HealthCheckResponse {
+	ctx, cancel := context.WithTimeout(ctx, checker.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check.Probe(ctx)
+	result := web.HealthCheckResponse{
+		Name:      check.Name,
+		Status:    web.HealthUp,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		checker.Logger.Warn(ctx, "health check failed", "check", check.Name, "error", err)
+		result.Status = web.HealthDown
+		result.Error = unavailable
+	}
+
+	return result
+}
+
+// Endpoints returns /healthz, which answers as long as the process serves
+// requests, /readyz, which answers 503 Service Unavailable while any check
+// fails, and /health, which reports every check.
+func (checker *Checker) Endpoints() map[string]http.Handler {
+	return map[string]http.Handler{
+		"/healthz": http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			writeHealth(w, web.HealthResponse{Status: web.HealthUp})
+		}),
+		"/readyz": http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			writeHealth(w, web.HealthResponse{Status: checker.Run(r.Context()).Status})
+		}),
+		"/health": http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			writeHealth(w, checker.Run(r.Context()))
+		}),
+	}
+}
+
+func writeHealth(w http.ResponseWriter, health web.HealthResponse) {
+	code := http.StatusOK
+	if health.Status != web.HealthUp {
+		code = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	helper.WriteToResponseBody(w, web.WebResponse{
+		Code:   code,
+		Status: http.StatusText(code),
+		Data:   health,
+	})
+}
@@ -0,0 +1,40 @@
+package helper
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ETag returns the strong entity tag of a resource version.
+func ETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// ETagVersion parses an entity tag created by ETag. Weak tags never match
+// in If-Match, so they are rejected.
+func ETagVersion(etag string) (int, bool) {
+	etag = strings.TrimSpace(etag)
+	if len(etag) < 2 || etag[0] != '"' || etag[len(etag)-1] != '"' {
+		return 0, false
+	}
+
+	version, err := strconv.Atoi(etag[1 : len(etag)-1])
+	if err != nil || version < 1 {
+		return 0, false
+	}
+
+	return version, true
+}
+
+// MatchETag reports whether an If-None-Match header lists etag or "*",
+// comparing weakly as RFC 7232 requires for If-None-Match.
+func MatchETag(header string, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -5,15 +5,13 @@ import (
 	"net/http"
 )
 
-func ReadFromRequestBody(r *http.Request, result interface{}) {
+func ReadFromRequestBody(r *http.Request, result interface{}) error {
 	decoder := json.NewDecoder(r.Body)
-	err := decoder.Decode(result)
-	PanicIfError(err)
+	return decoder.Decode(result)
 }
 
-func WriteToResponseBody(w http.ResponseWriter, response interface{}) {
+func WriteToResponseBody(w http.ResponseWriter, response interface{}) error {
 	w.Header().Add("Content-Type", "application/json")
 	encoder := json.NewEncoder(w)
-	err := encoder.Encode(response)
-	PanicIfError(err)
+	return encoder.Encode(response)
 }
@@ -1,14 +1,20 @@
 package helper
 
 import (
+	"encoding/json"
+
 	"sudutkampus/gorestfulapi/model/domain"
 	"sudutkampus/gorestfulapi/model/web"
 )
 
 func ToCategoryResponse(category domain.Category) web.CategoryResponse {
 	return web.CategoryResponse{
-		Id:   category.Id,
-		Name: category.Name,
+		Id:        category.Id,
+		Name:      category.Name,
+		Slug:      category.Slug,
+		ParentId:  category.ParentId,
+		Version:   category.Version,
+		DeletedAt: category.DeletedAt,
 	}
 }
 
@@ -20,3 +26,98 @@ func ToCategoryResponses(categories []domain.Category) []web.CategoryResponse {
 
 	return CategoryResponses
 }
+
+func ToProductResponse(product domain.Product) web.ProductResponse {
+	return web.ProductResponse{
+		Id:         product.Id,
+		CategoryId: product.CategoryId,
+		Name:       product.Name,
+		Sku:        product.Sku,
+		Price:      product.Price.StringFixed(2),
+		Stock:      product.Stock,
+		CreatedAt:  product.CreatedAt,
+		UpdatedAt:  product.UpdatedAt,
+	}
+}
+
+func ToProductResponses(products []domain.Product) []web.ProductResponse {
+	var productResponses []web.ProductResponse
+	for _, product := range products {
+		productResponses = append(productResponses, ToProductResponse(product))
+	}
+
+	return productResponses
+}
+
+func ToAuditLogResponse(auditLog domain.AuditLog) web.AuditLogResponse {
+	return web.AuditLogResponse{
+		Id:        auditLog.Id,
+		Actor:     auditLog.Actor,
+		Action:    auditLog.Action,
+		Entity:    auditLog.Entity,
+		EntityId:  auditLog.EntityId,
+		Before:    rawJSON(auditLog.Before),
+		After:     rawJSON(auditLog.After),
+		RequestId: auditLog.RequestId,
+		CreatedAt: auditLog.CreatedAt,
+	}
+}
+
+func ToAuditLogResponses(auditLogs []domain.AuditLog) []web.AuditLogResponse {
+	var auditLogResponses []web.AuditLogResponse
+	for _, auditLog := range auditLogs {
+		auditLogResponses = append(auditLogResponses, ToAuditLogResponse(auditLog))
+	}
+
+	return auditLogResponses
+}
+
+// rawJSON turns a missing snapshot into null.
+func rawJSON(snapshot string) json.RawMessage {
+	if snapshot == "" {
+		return json.RawMessage("null")
+	}
+
+	return json.RawMessage(snapshot)
+}
+
+func ToApiKeyResponse(apiKey domain.ApiKey) web.ApiKeyResponse {
+	return web.ApiKeyResponse{
+		Id:         apiKey.Id,
+		Name:       apiKey.Name,
+		Prefix:     apiKey.Prefix,
+		Scopes:     apiKey.Scopes,
+		ExpiresAt:  apiKey.ExpiresAt,
+		LastUsedAt: apiKey.LastUsedAt,
+		RevokedAt:  apiKey.RevokedAt,
+		CreatedAt:  apiKey.CreatedAt,
+	}
+}
+
+func ToApiKeyResponses(apiKeys []domain.ApiKey) []web.ApiKeyResponse {
+	var apiKeyResponses []web.ApiKeyResponse
+	for _, apiKey := range apiKeys {
+		apiKeyResponses = append(apiKeyResponses, ToApiKeyResponse(apiKey))
+	}
+
+	return apiKeyResponses
+}
+
+func ToUserResponse(user domain.User) web.UserResponse {
+	return web.UserResponse{
+		Id:        user.Id,
+		Email:     user.Email,
+		Name:      user.Name,
+		Roles:     user.Roles,
+		CreatedAt: user.CreatedAt,
+	}
+}
+
+func ToUserResponses(users []domain.User) []web.UserResponse {
+	var userResponses []web.UserResponse
+	for _, user := range users {
+		userResponses = append(userResponses, ToUserResponse(user))
+	}
+
+	return userResponses
+}
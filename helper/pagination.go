@@ -0,0 +1,70 @@
+package helper
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"sudutkampus/gorestfulapi/model/domain"
+	"sudutkampus/gorestfulapi/model/web"
+)
+
+func EncodeCursor(cursor domain.CategoryCursor) (string, error) {
+	raw, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func DecodeCursor(value string) (*domain.CategoryCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor := domain.CategoryCursor{}
+	err = json.Unmarshal(raw, &cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cursor, nil
+}
+
+func ToPageLinks(r *http.Request, meta web.PageMeta, cursorMode bool) web.PageLinks {
+	links := web.PageLinks{}
+
+	if cursorMode {
+		if meta.NextCursor != "" {
+			links.Next = pageLink(r, map[string]string{"after": meta.NextCursor, "page": ""})
+		}
+		return links
+	}
+
+	if meta.Page*meta.PerPage < meta.Total {
+		links.Next = pageLink(r, map[string]string{"page": strconv.Itoa(meta.Page + 1)})
+	}
+	if meta.Page > 1 {
+		links.Prev = pageLink(r, map[string]string{"page": strconv.Itoa(meta.Page - 1)})
+	}
+
+	return links
+}
+
+func pageLink(r *http.Request, values map[string]string) string {
+	link := *r.URL
+	query := link.Query()
+	for key, value := range values {
+		if value == "" {
+			query.Del(key)
+		} else {
+			query.Set(key, value)
+		}
+	}
+	link.RawQuery = query.Encode()
+
+	return link.RequestURI()
+}
@@ -0,0 +1,69 @@
+package helper
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxSlugLength leaves room within the 191 characters of the slug column
+// for a numeric suffix that makes a slug unique.
+const MaxSlugLength = 180
+
+// transliterations covers letters that do not decompose into an ASCII
+// letter and a combining mark, and the Cyrillic alphabet.
+var transliterations = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'ø': "o", 'œ': "oe", 'ł': "l", 'đ': "d", 'ð': "d", 'þ': "th", 'ı': "i",
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh", 'з': "z",
+	'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r",
+	'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+	'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya", 'є': "ye", 'і': "i", 'ї': "yi", 'ґ': "g",
+}
+
+// Slugify turns a name into lowercase ASCII letters and digits separated
+// by single hyphens, transliterating accented Latin and Cyrillic letters.
+// Anything else is treated as a separator, so the result may be empty.
+func Slugify(name string) string {
+	var slug strings.Builder
+	hyphen := false
+
+	for _, composed := range norm.NFC.String(strings.ToLower(name)) {
+		// letters such as й decompose into a base letter and a mark, so
+		// they are looked up before decomposing
+		decomposed := norm.NFKD.String(string(composed))
+		if _, ok := transliterations[composed]; ok {
+			decomposed = string(composed)
+		}
+
+		for _, r := range decomposed {
+			if unicode.Is(unicode.Mn, r) {
+				continue
+			}
+
+			text := string(r)
+			if transliteration, ok := transliterations[r]; ok {
+				text = transliteration
+			} else if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9') {
+				hyphen = slug.Len() > 0
+				continue
+			}
+			if text == "" {
+				continue
+			}
+
+			if hyphen {
+				slug.WriteByte('-')
+				hyphen = false
+			}
+			slug.WriteString(text)
+		}
+	}
+
+	result := slug.String()
+	if len(result) > MaxSlugLength {
+		result = strings.TrimRight(result[:MaxSlugLength], "-")
+	}
+
+	return result
+}
@@ -0,0 +1,160 @@
+// Package logging writes leveled log entries as JSON lines.
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"sudutkampus/gorestfulapi/requestid"
+)
+
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+var levelNames = []string{"debug", "info", "warn", "error"}
+
+func (level Level) String() string {
+	if level < LevelDebug || level > LevelError {
+		return fmt.Sprintf("level(%d)", int(level))
+	}
+
+	return levelNames[level]
+}
+
+func ParseLevel(name string) (Level, error) {
+	for level, levelName := range levelNames {
+		if levelName == name {
+			return Level(level), nil
+		}
+	}
+
+	return LevelInfo, fmt.Errorf("unknown log level %q", name)
+}
+
+// Logger writes entries at or above its level to out, one JSON object per
+// line with the time, level, message, the request id and trace of the
+// context if it has them, and then the fields of With and the key-value
+// pairs of the call.
+type Logger struct {
+	mutex  *sync.Mutex
+	out    io.Writer
+	level  Level
+	fields []interface{}
+}
+
+func New(out io.Writer, level Level) *Logger {
+	return &Logger{
+		mutex: &sync.Mutex{},
+		out:   out,
+		level: level,
+	}
+}
+
+// Discard returns a logger that writes nothing, for tests and tools.
+func Discard() *Logger {
+	return New(io.Discard, LevelError+1)
+}
+
+// With returns a logger adding keyvals to every entry.
+func (logger *Logger) With(keyvals ...interface{}) *Logger {
+	with := *logger
+	with.fields = append(logger.fields[:len(logger.fields):len(logger.fields)], keyvals...)
+
+	return &with
+}
+
+func (logger *Logger) Enabled(level Level) bool {
+	return level >= logger.level
+}
+
+func (logger *Logger) Debug(ctx context.Context, msg string, keyvals ...interface{}) {
+	logger.Log(ctx, LevelDebug, msg, keyvals...)
+}
+
+func (logger *Logger) Info(ctx context.Context, msg string, keyvals ...interface{}) {
+	logger.Log(ctx, LevelInfo, msg, keyvals...)
+}
+
+func (logger *Logger) Warn(ctx context.Context, msg string, keyvals ...interface{}) {
+	logger.Log(ctx, LevelWarn, msg, keyvals...)
+}
+
+func (logger *Logger) Error(ctx context.Context, msg string, keyvals ...interface{}) {
+	logger.Log(ctx, LevelError, msg, keyvals...)
+}
+
+func (logger *Logger) Log(ctx context.Context, level Level, msg string, keyvals ...interface{}) {
+	if !logger.Enabled(level) {
+		return
+	}
+
+	var entry bytes.Buffer
+	entry.WriteByte('{')
+	writeField(&entry, "time", time.Now().UTC().Format(time.RFC3339Nano))
+	writeField(&entry, "level", level.String())
+	writeField(&entry, "msg", msg)
+	if id := requestid.FromContext(ctx); id != "" {
+		writeField(&entry, "request_id", id)
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		writeField(&entry, "trace_id", spanContext.TraceID().String())
+		writeField(&entry, "span_id", spanContext.SpanID().String())
+	}
+	writeFields(&entry, logger.fields)
+	writeFields(&entry, keyvals)
+	entry.WriteString("}\n")
+
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+
+	logger.out.Write(entry.Bytes())
+}
+
+// writeFields writes key-value pairs; a key without a value gets null.
+func writeFields(entry *bytes.Buffer, keyvals []interface{}) {
+	for i := 0; i < len(keyvals); i += 2 {
+		var value interface{}
+		if i+1 < len(keyvals) {
+			value = keyvals[i+1]
+		}
+		writeField(entry, fmt.Sprint(keyvals[i]), value)
+	}
+}
+
+func writeField(entry *bytes.Buffer, key string, value interface{}) {
+	switch v := value.(type) {
+	case error:
+		value = v.Error()
+	case time.Duration:
+		value = v.String()
+	case time.Time:
+	case fmt.Stringer:
+		value = v.String()
+	}
+
+	encodedValue, err := json.Marshal(value)
+	if err != nil {
+		encodedValue, _ = json.Marshal(fmt.Sprint(value))
+	}
+	encodedKey, _ := json.Marshal(key)
+
+	if entry.Len() > 1 {
+		entry.WriteByte(',')
+	}
+	entry.Write(encodedKey)
+	entry.WriteByte(':')
+	entry.Write(encodedValue)
+}
@@ -1,33 +1,160 @@
 package main
 
 import (
+	"context"
+	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 
 	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/mattn/go-sqlite3"
 
 	"sudutkampus/gorestfulapi/app"
+	"sudutkampus/gorestfulapi/auth"
+	"sudutkampus/gorestfulapi/config"
 	"sudutkampus/gorestfulapi/controller"
-	"sudutkampus/gorestfulapi/helper"
+	"sudutkampus/gorestfulapi/logging"
+	"sudutkampus/gorestfulapi/metrics"
 	"sudutkampus/gorestfulapi/middleware"
-	"sudutkampus/gorestfulapi/repository"
+	"sudutkampus/gorestfulapi/migration"
+	"sudutkampus/gorestfulapi/ratelimit"
 	"sudutkampus/gorestfulapi/service"
-
-	"github.com/go-playground/validator/v10"
 )
 
 func main() {
-	db := app.NewDB()
-	validate := validator.New()
-	categoryRepository := repository.NewCategoryRepository()
-	categoryService := service.NewCategoryService(categoryRepository, db, validate)
-	categoryController := controller.NewCategoryController(categoryService)
-	router := app.NewRouter(categoryController)
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(os.Args[2:])
+		return
+	}
+
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	level, err := logging.ParseLevel(cfg.Log.Level)
+	if err != nil {
+		log.Fatal(err)
+	}
+	logger := logging.New(os.Stdout, level)
+
+	lifecycle := app.NewLifecycle()
+
+	tracer, tracerHook, err := app.NewTracer(cfg.Tracing)
+	if err != nil {
+		log.Fatal(err)
+	}
+	lifecycle.Append(tracerHook)
+
+	storage := app.NewStorage(cfg.Database, logger, tracer)
+	lifecycle.Append(storage.Hook())
+
+	if cfg.Database.AutoMigrate && storage.DB != nil {
+		migrator, err := migration.NewMigrator(storage.DB, cfg.Database.Driver)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		_, err = migrator.Up(context.Background())
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		backfilled, err := backfillSlugs(context.Background(), cfg, storage)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if backfilled > 0 {
+			logger.Info(context.Background(), "generated slugs", "categories", backfilled)
+		}
+	}
+
+	validate := app.NewValidator()
+	categoryService := service.NewCategoryServiceTraced(service.NewCategoryService(storage.CategoryRepository, storage.ProductRepository, storage.AuditLogRepository, storage.UnitOfWork, validate, cfg.Hierarchy, logger), tracer)
+	categoryController := controller.NewCategoryController(categoryService, cfg.Concurrency, logger)
+	if cfg.Trash.Retention > 0 {
+		lifecycle.Append(app.TrashPurgeHook(categoryService, cfg.Trash, logger))
+	}
+	productService := service.NewProductService(storage.ProductRepository, storage.CategoryRepository, storage.UnitOfWork, validate, logger)
+	productController := controller.NewProductController(productService)
+	auditService := service.NewAuditService(storage.AuditLogRepository, storage.UnitOfWork, validate, logger)
+	auditController := controller.NewAuditController(auditService)
+	apiKeyService := service.NewApiKeyService(storage.ApiKeyRepository, storage.UnitOfWork, validate, cfg.Auth.APIKeyHashCost, logger)
+	apiKeyController := controller.NewApiKeyController(apiKeyService)
+	userService := service.NewUserService(storage.UserRepository, storage.SessionRepository, storage.UnitOfWork, validate, cfg.Auth.PasswordHashCost, cfg.Auth.Session, logger)
+	userController := controller.NewUserController(userService)
+	rateLimitStore := ratelimit.NewMemoryStore()
+	router := app.NewRouter(app.RouterDependencies{
+		Config:         cfg.Router,
+		RateLimit:      cfg.RateLimit,
+		RateLimitStore: rateLimitStore,
+		Logger:         logger,
+		Tracer:         tracer,
+	}, app.Controllers{
+		CategoryController: categoryController,
+		ProductController:  productController,
+		AuditController:    auditController,
+		ApiKeyController:   apiKeyController,
+		UserController:     userController,
+	})
 
-	server := http.Server{
-		Addr:    "localhost:3000",
-		Handler: middleware.NewAuthMiddleware(router),
+	jwtVerifier, err := auth.NewJWTVerifier(cfg.Auth.JWT)
+	if err != nil {
+		log.Fatal(err)
 	}
 
-	err := server.ListenAndServe()
-	helper.PanicIfError(err)
+	var handler http.Handler = middleware.NewAuthMiddleware(router, cfg.Auth, cfg.RateLimit, rateLimitStore, apiKeyService, userService, jwtVerifier, logger)
+	if cfg.Log.AccessLog {
+		handler = middleware.NewAccessLogMiddleware(handler, logger)
+	}
+
+	healthChecker, err := app.NewHealthChecker(storage, cfg.Database, cfg.Health, lifecycle, logger)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	endpoints := healthChecker.Endpoints()
+	if cfg.Metrics.Enabled {
+		appMetrics := metrics.New(storage.DB)
+		handler = middleware.NewMetricsMiddleware(handler, appMetrics)
+		endpoints[cfg.Metrics.Path] = appMetrics.Handler()
+	}
+
+	handler = middleware.NewTracingMiddleware(handler, tracer)
+
+	server := app.NewServer(cfg.Server, app.NewHandler(middleware.NewRequestIdMiddleware(handler), endpoints))
+	serverErrors := make(chan error, 1)
+	lifecycle.Append(app.ServerHook(server, cfg.Server.DrainDelay, serverErrors))
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	err = lifecycle.Start(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	logger.Info(ctx, "listening", "addr", cfg.Server.Addr)
+
+	// a server that died is still stopped cleanly, but the process then
+	// exits non-zero so that a supervisor does not take it for a shutdown
+	var serveErr error
+	select {
+	case <-ctx.Done():
+		logger.Info(context.Background(), "shutting down")
+	case serveErr = <-serverErrors:
+		logger.Error(context.Background(), "server failed", "error", serveErr)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.DrainDelay+cfg.Server.ShutdownTimeout)
+	defer cancel()
+
+	err = lifecycle.Stop(shutdownCtx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if serveErr != nil {
+		os.Exit(1)
+	}
 }
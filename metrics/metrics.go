@@ -0,0 +1,88 @@
+// Package metrics collects the request, error and database pool metrics
+// served in the Prometheus text format.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "gorestfulapi"
+
+// RouteUnmatched labels requests no route handled.
+const RouteUnmatched = "unmatched"
+
+type Metrics struct {
+	Registry *prometheus.Registry
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	errors   *prometheus.CounterVec
+}
+
+// New registers the HTTP metrics together with the Go runtime and process
+// collectors, and the connection pool statistics of db unless it is nil.
+func New(db *sql.DB) *Metrics {
+	metrics := &Metrics{
+		Registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by method, route pattern and status.",
+		}, []string{"method", "route", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by method and route pattern.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_errors_total",
+			Help:      "Error responses by exception type.",
+		}, []string{"type"}),
+	}
+
+	metrics.Registry.MustRegister(
+		metrics.requests,
+		metrics.duration,
+		metrics.errors,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	if db != nil {
+		metrics.Registry.MustRegister(collectors.NewDBStatsCollector(db, namespace))
+	}
+
+	return metrics
+}
+
+func (metrics *Metrics) ObserveRequest(method string, route string, status int, duration time.Duration) {
+	if route == "" {
+		route = RouteUnmatched
+	}
+
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions:
+	default:
+		// keep the label values bounded whatever clients send
+		method = "OTHER"
+	}
+
+	metrics.requests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	metrics.duration.WithLabelValues(method, route).Observe(duration.Seconds())
+}
+
+func (metrics *Metrics) ObserveError(errorType string) {
+	metrics.errors.WithLabelValues(errorType).Inc()
+}
+
+func (metrics *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{})
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"sudutkampus/gorestfulapi/logging"
+)
+
+type AccessLogMiddleware struct {
+	Handler http.Handler
+	Logger  *logging.Logger
+}
+
+func NewAccessLogMiddleware(handler http.Handler, logger *logging.Logger) *AccessLogMiddleware {
+	return &AccessLogMiddleware{
+		Handler: handler,
+		Logger:  logger,
+	}
+}
+
+// ServeHTTP logs every request once it has been handled. The route is the
+// pattern of the handler that served it, empty when none matched.
+func (middleware *AccessLogMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	recorder := &responseRecorder{ResponseWriter: w}
+
+	middleware.Handler.ServeHTTP(recorder, r)
+
+	middleware.Logger.Info(r.Context(), "request",
+		"method", r.Method,
+		"path", r.URL.Path,
+		"route", recorder.route,
+		"status", recorder.Status(),
+		"latency_ms", float64(time.Since(start).Microseconds())/1000,
+		"bytes", recorder.bytes,
+		"remote_addr", r.RemoteAddr,
+	)
+}
@@ -1,32 +1,145 @@
 package middleware
 
 import (
+	"crypto/subtle"
+	"errors"
+	"math"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
 
-	"sudutkampus/gorestfulapi/helper"
-	"sudutkampus/gorestfulapi/model/web"
+	"sudutkampus/gorestfulapi/auth"
+	"sudutkampus/gorestfulapi/config"
+	"sudutkampus/gorestfulapi/exception"
+	"sudutkampus/gorestfulapi/logging"
+	"sudutkampus/gorestfulapi/ratelimit"
+	"sudutkampus/gorestfulapi/service"
 )
 
+// AuthMiddleware authenticates requests. When FailureLimit is set, each IP
+// address has a bucket that every rejected credential takes a token from,
+// and once it is empty requests with credentials are refused with 429 Too
+// Many Requests before they are checked, so guessing keys costs no bcrypt
+// comparisons.
 type AuthMiddleware struct {
-	Handler http.Handler
+	Handler        http.Handler
+	BootstrapKey   string
+	ApiKeyService  service.ApiKeyService
+	UserService    service.UserService
+	JWTVerifier    *auth.JWTVerifier
+	Logger         *logging.Logger
+	RateLimitStore ratelimit.Store
+	FailureLimit   *ratelimit.Limit
 }
 
-func NewAuthMiddleware(handler http.Handler) *AuthMiddleware {
-	return &AuthMiddleware{Handler: handler}
+func NewAuthMiddleware(handler http.Handler, authConfig config.AuthConfig, rateLimitConfig config.RateLimitConfig, rateLimitStore ratelimit.Store, apiKeyService service.ApiKeyService, userService service.UserService, jwtVerifier *auth.JWTVerifier, logger *logging.Logger) *AuthMiddleware {
+	var failureLimit *ratelimit.Limit
+	if rateLimitConfig.Enabled {
+		failureLimit = &ratelimit.Limit{Rate: rateLimitConfig.AuthFailures.Rate, Burst: rateLimitConfig.AuthFailures.Burst}
+	}
+
+	return &AuthMiddleware{
+		Handler:        handler,
+		BootstrapKey:   authConfig.APIKey,
+		ApiKeyService:  apiKeyService,
+		UserService:    userService,
+		JWTVerifier:    jwtVerifier,
+		Logger:         logger,
+		RateLimitStore: rateLimitStore,
+		FailureLimit:   failureLimit,
+	}
 }
 
+// ServeHTTP rejects invalid credentials. Requests without any are passed on
+// anonymously, routes that need a principal check for one themselves.
 func (middleware *AuthMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	if "RAHASIA" == r.Header.Get("X-API-Key") {
+	if r.Header.Get("Authorization") == "" && r.Header.Get("X-API-Key") == "" {
 		middleware.Handler.ServeHTTP(w, r)
-	} else {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	failureKey := "auth_failures|ip:" + clientIP(r)
+	if middleware.FailureLimit != nil {
+		result, err := middleware.RateLimitStore.Peek(r.Context(), failureKey, *middleware.FailureLimit)
+		if err != nil {
+			middleware.Logger.Error(r.Context(), "rate limit store failed", "error", err)
+		} else if !result.Allowed {
+			retryAfter := int(math.Ceil(result.RetryAfter.Seconds()))
+			if retryAfter < 1 {
+				retryAfter = 1
+			}
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			exception.WriteError(w, r, exception.NewTooManyRequestsError("too many failed authentications, retry in "+strconv.Itoa(retryAfter)+"s"))
+			return
+		}
+	}
+
+	principal, err := middleware.authenticate(r)
+	if err != nil {
+		if exception.IsInternal(err) {
+			middleware.Logger.Error(r.Context(), "authentication failed", "error", err)
+		}
+
+		var unauthorizedError exception.UnauthorizedError
+		if middleware.FailureLimit != nil && errors.As(err, &unauthorizedError) {
+			_, err := middleware.RateLimitStore.Take(r.Context(), failureKey, *middleware.FailureLimit)
+			if err != nil {
+				middleware.Logger.Error(r.Context(), "rate limit store failed", "error", err)
+			}
+		}
+
+		exception.WriteError(w, r, err)
+		return
+	}
+
+	middleware.Handler.ServeHTTP(w, r.WithContext(auth.NewContext(r.Context(), principal)))
+}
+
+// authenticate accepts either an Authorization: Bearer token, which is a
+// session access token or a JWT, or an X-API-Key.
+func (middleware *AuthMiddleware) authenticate(r *http.Request) (auth.Principal, error) {
+	authorization := r.Header.Get("Authorization")
+	if len(authorization) > 7 && strings.EqualFold(authorization[:7], "Bearer ") {
+		token := strings.TrimSpace(authorization[7:])
+		if strings.HasPrefix(token, service.AccessTokenPrefix) {
+			return middleware.UserService.Authenticate(r.Context(), token)
+		}
 
-		webResponse := web.WebResponse{
-			Code:   http.StatusUnauthorized,
-			Status: "Unauthorized",
+		if middleware.JWTVerifier == nil {
+			return auth.Principal{}, exception.NewUnauthorizedError("bearer tokens are not enabled")
 		}
 
-		helper.WriteToResponseBody(w, webResponse)
+		principal, err := middleware.JWTVerifier.Verify(token)
+		if err != nil {
+			return auth.Principal{}, exception.NewUnauthorizedError(err.Error())
+		}
+
+		return principal, nil
+	}
+
+	apiKey := r.Header.Get("X-API-Key")
+	if apiKey == "" {
+		return auth.Principal{}, exception.NewUnauthorizedError("missing api key")
 	}
+
+	if middleware.BootstrapKey != "" && subtle.ConstantTimeCompare([]byte(middleware.BootstrapKey), []byte(apiKey)) == 1 {
+		return auth.Principal{
+			Type:   auth.PrincipalBootstrap,
+			Name:   "bootstrap",
+			Scopes: []string{auth.ScopeAll},
+		}, nil
+	}
+
+	return middleware.ApiKeyService.Authenticate(r.Context(), apiKey)
+}
+
+// clientIP is the address the request came from, without its port.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
 }
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"sudutkampus/gorestfulapi/exception"
+	"sudutkampus/gorestfulapi/metrics"
+)
+
+type MetricsMiddleware struct {
+	Handler http.Handler
+	Metrics *metrics.Metrics
+}
+
+func NewMetricsMiddleware(handler http.Handler, metrics *metrics.Metrics) *MetricsMiddleware {
+	return &MetricsMiddleware{
+		Handler: handler,
+		Metrics: metrics,
+	}
+}
+
+// ServeHTTP counts and times every request by its route pattern, and counts
+// error responses by the type of exception they report.
+func (middleware *MetricsMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	recorder := &responseRecorder{ResponseWriter: w}
+
+	middleware.Handler.ServeHTTP(recorder, r)
+
+	middleware.Metrics.ObserveRequest(r.Method, recorder.route, recorder.Status(), time.Since(start))
+	if recorder.err != nil {
+		middleware.Metrics.ObserveError(exception.TypeOf(recorder.err))
+	}
+}
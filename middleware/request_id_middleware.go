@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+
+	"sudutkampus/gorestfulapi/requestid"
+)
+
+type RequestIdMiddleware struct {
+	Handler http.Handler
+}
+
+func NewRequestIdMiddleware(handler http.Handler) *RequestIdMiddleware {
+	return &RequestIdMiddleware{
+		Handler: handler,
+	}
+}
+
+// ServeHTTP keeps a valid X-Request-ID sent by the client, generates one
+// otherwise, and echoes it in the response.
+func (middleware *RequestIdMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id := r.Header.Get(requestid.Header)
+	if !requestid.Valid(id) {
+		id = requestid.New()
+	}
+
+	w.Header().Set(requestid.Header, id)
+
+	middleware.Handler.ServeHTTP(w, r.WithContext(requestid.NewContext(r.Context(), id)))
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import "net/http"
+
+// responseRecorder keeps the status and size of a response, the route
+// pattern the router reports through SetRoute and the error reported by
+// exception.WriteError through SetError. Both are passed on to the
+// recorder of a middleware further out.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+	route  string
+	err    error
+}
+
+type routeSetter interface {
+	SetRoute(pattern string)
+}
+
+type errorSetter interface {
+	SetError(err error)
+}
+
+func (recorder *responseRecorder) WriteHeader(status int) {
+	if recorder.status == 0 {
+		recorder.status = status
+	}
+	recorder.ResponseWriter.WriteHeader(status)
+}
+
+func (recorder *responseRecorder) Write(b []byte) (int, error) {
+	if recorder.status == 0 {
+		recorder.status = http.StatusOK
+	}
+	n, err := recorder.ResponseWriter.Write(b)
+	recorder.bytes += n
+
+	return n, err
+}
+
+// Status is 200 for a handler that wrote nothing, as net/http sends then.
+func (recorder *responseRecorder) Status() int {
+	if recorder.status == 0 {
+		return http.StatusOK
+	}
+
+	return recorder.status
+}
+
+func (recorder *responseRecorder) SetRoute(pattern string) {
+	recorder.route = pattern
+	if setter, ok := recorder.ResponseWriter.(routeSetter); ok {
+		setter.SetRoute(pattern)
+	}
+}
+
+func (recorder *responseRecorder) SetError(err error) {
+	recorder.err = err
+	if setter, ok := recorder.ResponseWriter.(errorSetter); ok {
+		setter.SetError(err)
+	}
+}
+
+func (recorder *responseRecorder) Flush() {
+	if flusher, ok := recorder.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"sudutkampus/gorestfulapi/requestid"
+)
+
+type TracingMiddleware struct {
+	Handler    http.Handler
+	Tracer     trace.Tracer
+	Propagator propagation.TextMapPropagator
+}
+
+func NewTracingMiddleware(handler http.Handler, tracer trace.Tracer) *TracingMiddleware {
+	return &TracingMiddleware{
+		Handler:    handler,
+		Tracer:     tracer,
+		Propagator: propagation.TraceContext{},
+	}
+}
+
+// ServeHTTP records a server span for every request, continuing the trace
+// of a W3C traceparent header. The span is named after the route pattern
+// once the router has matched one, and fails for 5xx responses only.
+func (middleware *TracingMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := middleware.Propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+	ctx, span := middleware.Tracer.Start(ctx, r.Method, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(
+		semconv.HTTPMethodKey.String(r.Method),
+		semconv.HTTPTargetKey.String(r.URL.RequestURI()),
+	))
+	defer span.End()
+
+	if id := requestid.FromContext(ctx); id != "" {
+		span.SetAttributes(attribute.String("request_id", id))
+	}
+
+	recorder := &responseRecorder{ResponseWriter: w}
+
+	middleware.Handler.ServeHTTP(recorder, r.WithContext(ctx))
+
+	if recorder.route != "" {
+		span.SetName(r.Method + " " + recorder.route)
+		span.SetAttributes(semconv.HTTPRouteKey.String(recorder.route))
+	}
+	span.SetAttributes(semconv.HTTPStatusCodeKey.Int(recorder.Status()))
+	span.SetStatus(semconv.SpanStatusFromHTTPStatusCodeAndSpanKind(recorder.Status(), trace.SpanKindServer))
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"sudutkampus/gorestfulapi/app"
+	"sudutkampus/gorestfulapi/config"
+	"sudutkampus/gorestfulapi/logging"
+	"sudutkampus/gorestfulapi/migration"
+	"sudutkampus/gorestfulapi/service"
+	"sudutkampus/gorestfulapi/tracing"
+)
+
+const migrateUsage = `usage: gorestfulapi migrate <command> [flags]
+
+commands:
+  up             apply all pending migrations and generate missing slugs
+  down [steps]   revert the last applied migrations (default 1)
+  status         list migrations and when they were applied
+  create <name>  write empty up/down files for every driver to ` + migration.Dir
+
+// runMigrate implements the "migrate" subcommand.
+func runMigrate(args []string) {
+	if len(args) == 0 {
+		log.Fatal(migrateUsage)
+	}
+
+	command, args := args[0], args[1:]
+
+	if command == "create" {
+		if len(args) == 0 {
+			log.Fatal(migrateUsage)
+		}
+
+		paths, err := migration.Create(migration.Dir, args[0], time.Now())
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, path := range paths {
+			fmt.Println(path)
+		}
+		return
+	}
+
+	if command != "up" && command != "down" && command != "status" {
+		log.Fatal(migrateUsage)
+	}
+
+	steps := 1
+	if command == "down" && len(args) > 0 {
+		if value, err := strconv.Atoi(args[0]); err == nil {
+			steps = value
+			args = args[1:]
+		}
+	}
+
+	cfg, err := config.Parse(args)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = cfg.Database.Validate()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if cfg.Database.Driver == config.DriverMemory {
+		log.Fatal("the memory driver has no schema to migrate")
+	}
+
+	storage := app.NewStorage(cfg.Database, logging.Discard(), tracing.Noop())
+	defer storage.DB.Close()
+
+	migrator, err := migration.NewMigrator(storage.DB, cfg.Database.Driver)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx := context.Background()
+
+	switch command {
+	case "up":
+		migrations, err := migrator.Up(ctx)
+		printMigrations("applied", migrations)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		backfilled, err := backfillSlugs(ctx, cfg, storage)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if backfilled > 0 {
+			fmt.Printf("generated slugs for %d categories\n", backfilled)
+		}
+	case "down":
+		migrations, err := migrator.Down(ctx, steps)
+		printMigrations("reverted", migrations)
+		if err != nil {
+			log.Fatal(err)
+		}
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, status := range statuses {
+			appliedAt := status.AppliedAt
+			if appliedAt == "" {
+				appliedAt = "pending"
+			}
+			fmt.Printf("%d_%s\t%s\n", status.Version, status.Name, appliedAt)
+		}
+	}
+}
+
+// backfillSlugs gives a slug to every category created before the
+// migration that added them. It is safe to run again and from several
+// processes at once.
+func backfillSlugs(ctx context.Context, cfg config.Config, storage *app.Storage) (int, error) {
+	categoryService := service.NewCategoryService(storage.CategoryRepository, storage.ProductRepository, storage.AuditLogRepository, storage.UnitOfWork, app.NewValidator(), cfg.Hierarchy, logging.Discard())
+
+	return categoryService.BackfillSlugs(ctx)
+}
+
+func printMigrations(action string, migrations []migration.Migration) {
+	for _, migration := range migrations {
+		fmt.Printf("%s %d_%s\n", action, migration.Version, migration.Name)
+	}
+	if len(migrations) == 0 {
+		fmt.Println("nothing to do")
+	}
+}
@@ -0,0 +1,152 @@
+package migration
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Dir is where the create command writes new migrations; it is the source
+// directory of the embedded files.
+const Dir = "migration/sql"
+
+//go:embed sql
+var files embed.FS
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type Status struct {
+	Version   int64
+	Name      string
+	AppliedAt string
+}
+
+// Load reads the embedded migrations of a driver, sorted by version. Files
+// are named <version>_<name>.up.sql and <version>_<name>.down.sql.
+func Load(driver string) ([]Migration, error) {
+	entries, err := fs.ReadDir(files, "sql/"+driver)
+	if err != nil {
+		return nil, fmt.Errorf("no migrations for driver %s: %w", driver, err)
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		name := entry.Name()
+		var direction string
+		switch {
+		case strings.HasSuffix(name, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(name, ".down.sql"):
+			direction = "down"
+		default:
+			continue
+		}
+
+		parts := strings.SplitN(strings.TrimSuffix(name, "."+direction+".sql"), "_", 2)
+		version, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil || len(parts) != 2 {
+			return nil, fmt.Errorf("invalid migration file name %s", name)
+		}
+
+		content, err := fs.ReadFile(files, "sql/"+driver+"/"+name)
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: parts[1]}
+			byVersion[version] = migration
+		}
+
+		if direction == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	var migrations []Migration
+	for _, migration := range byVersion {
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Create writes empty up and down files for every driver below dir and
+// returns their paths.
+func Create(dir string, name string, now time.Time) ([]string, error) {
+	name = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(name), " ", "_"))
+	if name == "" {
+		return nil, fmt.Errorf("migration name is required")
+	}
+
+	drivers, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	version := now.UTC().Format("20060102150405")
+
+	var paths []string
+	for _, driver := range drivers {
+		if !driver.IsDir() {
+			continue
+		}
+
+		for _, direction := range []string{"up", "down"} {
+			path := filepath.Join(dir, driver.Name(), version+"_"+name+"."+direction+".sql")
+			err := os.WriteFile(path, []byte("-- "+driver.Name()+" "+direction+" migration\n"), 0644)
+			if err != nil {
+				return paths, err
+			}
+			paths = append(paths, path)
+		}
+	}
+
+	return paths, nil
+}
+
+// statements splits a migration into statements terminated by a semicolon at
+// the end of a line, since not every driver accepts several statements per Exec.
+func statements(content string) []string {
+	var result []string
+	var current strings.Builder
+
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+
+		current.WriteString(line)
+		current.WriteString("\n")
+
+		if strings.HasSuffix(trimmed, ";") {
+			result = append(result, strings.TrimSpace(current.String()))
+			current.Reset()
+		}
+	}
+
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		result = append(result, rest)
+	}
+
+	return result
+}
@@ -0,0 +1,189 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+const createSchemaMigrations = `create table if not exists schema_migrations (
+    version    bigint       not null primary key,
+    name       varchar(255) not null,
+    applied_at timestamp    not null
+)`
+
+type Migrator struct {
+	DB         *sql.DB
+	Driver     string
+	Migrations []Migration
+}
+
+func NewMigrator(db *sql.DB, driver string) (*Migrator, error) {
+	migrations, err := Load(driver)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{DB: db, Driver: driver, Migrations: migrations}, nil
+}
+
+// Up applies every pending migration in order and returns the applied ones.
+func (migrator *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	_, err := migrator.DB.ExecContext(ctx, createSchemaMigrations)
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := migrator.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Migration
+	for _, migration := range migrator.Migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		err := migrator.run(ctx, migration.Up, func(tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, "insert into schema_migrations(version, name, applied_at) values (?, ?, ?)",
+				migration.Version, migration.Name, time.Now().UTC())
+			return err
+		})
+		if err != nil {
+			return result, fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+
+		result = append(result, migration)
+	}
+
+	return result, nil
+}
+
+// Down reverts the last steps applied migrations, newest first.
+func (migrator *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	applied, err := migrator.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Migration
+	for i := len(migrator.Migrations) - 1; i >= 0 && len(result) < steps; i-- {
+		migration := migrator.Migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+
+		err := migrator.run(ctx, migration.Down, func(tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, "delete from schema_migrations where version = ?", migration.Version)
+			return err
+		})
+		if err != nil {
+			return result, fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+
+		result = append(result, migration)
+	}
+
+	return result, nil
+}
+
+// Status lists every known migration; AppliedAt is empty for pending ones.
+func (migrator *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := migrator.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Status
+	for _, migration := range migrator.Migrations {
+		result = append(result, Status{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			AppliedAt: applied[migration.Version],
+		})
+	}
+
+	return result, nil
+}
+
+// Pending reports how many migrations have not been applied yet. Like
+// Status it only reads, so health checks can call it with a read-only
+// user; without a schema_migrations table every migration is pending.
+func (migrator *Migrator) Pending(ctx context.Context) (int, error) {
+	applied, err := migrator.applied(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	pending := 0
+	for _, migration := range migrator.Migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			pending++
+		}
+	}
+
+	return pending, nil
+}
+
+// applied maps the version of every applied migration to when it was
+// applied, and is empty before Up has created schema_migrations.
+func (migrator *Migrator) applied(ctx context.Context) (map[int64]string, error) {
+	applied := map[int64]string{}
+
+	var tables int
+	err := migrator.DB.QueryRowContext(ctx, migrator.tableExistsSQL(), "schema_migrations").Scan(&tables)
+	if err != nil || tables == 0 {
+		return applied, err
+	}
+
+	rows, err := migrator.DB.QueryContext(ctx, "select version, applied_at from schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var version int64
+		var appliedAt string
+		err := rows.Scan(&version, &appliedAt)
+		if err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+
+	return applied, rows.Err()
+}
+
+func (migrator *Migrator) tableExistsSQL() string {
+	if migrator.Driver == "sqlite3" {
+		return "select count(*) from sqlite_master where type = 'table' and name = ?"
+	}
+
+	return "select count(*) from information_schema.tables where table_schema = database() and table_name = ?"
+}
+
+func (migrator *Migrator) run(ctx context.Context, content string, record func(tx *sql.Tx) error) error {
+	tx, err := migrator.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, statement := range statements(content) {
+		_, err := tx.ExecContext(ctx, statement)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	err = record(tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
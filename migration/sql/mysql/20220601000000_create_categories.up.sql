@@ -0,0 +1,5 @@
+create table if not exists categories (
+    id   int          not null auto_increment,
+    name varchar(255) not null,
+    primary key (id)
+) engine = InnoDB;
@@ -0,0 +1,13 @@
+create table api_keys (
+    id           int           not null auto_increment,
+    name         varchar(255)  not null,
+    prefix       varchar(32)   not null,
+    key_hash     varchar(255)  not null,
+    scopes       varchar(1024) not null,
+    expires_at   datetime      null,
+    last_used_at datetime      null,
+    revoked_at   datetime      null,
+    created_at   datetime      not null,
+    primary key (id),
+    unique key api_keys_prefix_unique (prefix)
+) engine = InnoDB;
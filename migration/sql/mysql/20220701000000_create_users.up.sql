@@ -0,0 +1,26 @@
+create table users (
+    id            int          not null auto_increment,
+    email         varchar(255) not null,
+    name          varchar(200) not null,
+    password_hash varchar(255) not null,
+    created_at    datetime     not null,
+    updated_at    datetime     not null,
+    primary key (id),
+    unique key users_email_unique (email)
+) engine = InnoDB;
+
+create table sessions (
+    id                 int         not null auto_increment,
+    user_id            int         not null,
+    access_token_hash  char(64)    not null,
+    refresh_token_hash char(64)    not null,
+    access_expires_at  datetime    not null,
+    refresh_expires_at datetime    not null,
+    revoked_at         datetime    null,
+    created_at         datetime    not null,
+    primary key (id),
+    unique key sessions_access_token_hash_unique (access_token_hash),
+    unique key sessions_refresh_token_hash_unique (refresh_token_hash),
+    key sessions_user_id_index (user_id),
+    constraint sessions_user_id_foreign foreign key (user_id) references users (id) on delete cascade
+) engine = InnoDB;
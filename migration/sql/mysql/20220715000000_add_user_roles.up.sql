@@ -0,0 +1 @@
+alter table users add roles varchar(255) not null default 'viewer' after password_hash;
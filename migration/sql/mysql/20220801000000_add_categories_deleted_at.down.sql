@@ -0,0 +1,3 @@
+alter table categories
+    drop index categories_deleted_at_index,
+    drop deleted_at;
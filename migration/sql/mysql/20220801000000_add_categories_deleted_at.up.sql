@@ -0,0 +1,3 @@
+alter table categories
+    add deleted_at datetime null,
+    add index categories_deleted_at_index (deleted_at);
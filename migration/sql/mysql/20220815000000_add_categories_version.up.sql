@@ -0,0 +1 @@
+alter table categories add version int not null default 1 after name;
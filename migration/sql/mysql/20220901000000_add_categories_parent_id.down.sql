@@ -0,0 +1,3 @@
+alter table categories
+    drop index categories_parent_id_index,
+    drop parent_id;
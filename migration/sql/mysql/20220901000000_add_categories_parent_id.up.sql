@@ -0,0 +1,3 @@
+alter table categories
+    add parent_id int null after name,
+    add index categories_parent_id_index (parent_id);
@@ -0,0 +1,3 @@
+alter table categories
+    drop index categories_slug_unique,
+    drop slug;
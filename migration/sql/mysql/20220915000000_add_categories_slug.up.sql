@@ -0,0 +1,3 @@
+alter table categories
+    add slug varchar(191) null after name,
+    add unique index categories_slug_unique (slug);
@@ -0,0 +1,14 @@
+create table products (
+    id          int            not null auto_increment,
+    category_id int            not null,
+    name        varchar(255)   not null,
+    sku         varchar(64)    not null,
+    price       decimal(12, 2) not null,
+    stock       int            not null default 0,
+    created_at  datetime       not null,
+    updated_at  datetime       not null,
+    primary key (id),
+    unique key products_sku_unique (sku),
+    key products_category_id_index (category_id),
+    constraint products_category_id_foreign foreign key (category_id) references categories (id)
+) engine = InnoDB;
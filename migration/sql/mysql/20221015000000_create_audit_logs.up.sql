@@ -0,0 +1,13 @@
+create table audit_logs (
+    id              int          not null auto_increment,
+    actor           varchar(255) not null,
+    action          varchar(32)  not null,
+    entity          varchar(32)  not null,
+    entity_id       int          not null,
+    before_snapshot text         null,
+    after_snapshot  text         null,
+    request_id      varchar(64)  not null default '',
+    created_at      datetime     not null,
+    primary key (id),
+    key audit_logs_entity_index (entity, entity_id)
+) engine = InnoDB;
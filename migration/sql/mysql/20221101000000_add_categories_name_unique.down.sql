@@ -0,0 +1,3 @@
+alter table categories
+    drop index categories_name_unique,
+    drop name_key;
@@ -0,0 +1,3 @@
+alter table categories
+    add name_key varchar(255) generated always as (if(deleted_at is null, lower(name), null)) virtual,
+    add unique index categories_name_unique (name_key);
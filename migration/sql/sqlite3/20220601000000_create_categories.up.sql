@@ -0,0 +1,4 @@
+create table if not exists categories (
+    id   integer      primary key autoincrement,
+    name varchar(255) not null
+);
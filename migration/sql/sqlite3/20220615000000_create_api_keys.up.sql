@@ -0,0 +1,11 @@
+create table api_keys (
+    id           integer       primary key autoincrement,
+    name         varchar(255)  not null,
+    prefix       varchar(32)   not null unique,
+    key_hash     varchar(255)  not null,
+    scopes       varchar(1024) not null,
+    expires_at   datetime      null,
+    last_used_at datetime      null,
+    revoked_at   datetime      null,
+    created_at   datetime      not null
+);
@@ -0,0 +1,21 @@
+create table users (
+    id            integer      primary key autoincrement,
+    email         varchar(255) not null unique,
+    name          varchar(200) not null,
+    password_hash varchar(255) not null,
+    created_at    datetime     not null,
+    updated_at    datetime     not null
+);
+
+create table sessions (
+    id                 integer  primary key autoincrement,
+    user_id            integer  not null references users (id) on delete cascade,
+    access_token_hash  char(64) not null unique,
+    refresh_token_hash char(64) not null unique,
+    access_expires_at  datetime not null,
+    refresh_expires_at datetime not null,
+    revoked_at         datetime null,
+    created_at         datetime not null
+);
+
+create index sessions_user_id_index on sessions (user_id);
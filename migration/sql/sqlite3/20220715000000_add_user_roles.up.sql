@@ -0,0 +1 @@
+alter table users add column roles varchar(255) not null default 'viewer';
@@ -0,0 +1,3 @@
+drop index categories_deleted_at_index;
+
+alter table categories drop column deleted_at;
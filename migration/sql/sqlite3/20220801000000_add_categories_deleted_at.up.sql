@@ -0,0 +1,3 @@
+alter table categories add column deleted_at datetime null;
+
+create index categories_deleted_at_index on categories (deleted_at);
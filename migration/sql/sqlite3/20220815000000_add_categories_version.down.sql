@@ -0,0 +1 @@
+alter table categories drop column version;
@@ -0,0 +1,3 @@
+drop index categories_parent_id_index;
+
+alter table categories drop column parent_id;
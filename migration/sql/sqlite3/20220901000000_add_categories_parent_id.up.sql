@@ -0,0 +1,3 @@
+alter table categories add column parent_id integer null;
+
+create index categories_parent_id_index on categories (parent_id);
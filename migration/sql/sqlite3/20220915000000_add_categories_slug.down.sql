@@ -0,0 +1,3 @@
+drop index categories_slug_unique;
+
+alter table categories drop column slug;
@@ -0,0 +1,3 @@
+alter table categories add column slug varchar(191) null;
+
+create unique index categories_slug_unique on categories (slug);
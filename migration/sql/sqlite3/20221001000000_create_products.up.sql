@@ -0,0 +1,12 @@
+create table products (
+    id          integer        primary key autoincrement,
+    category_id integer        not null references categories (id),
+    name        varchar(255)   not null,
+    sku         varchar(64)    not null unique,
+    price       decimal(12, 2) not null,
+    stock       integer        not null default 0,
+    created_at  datetime       not null,
+    updated_at  datetime       not null
+);
+
+create index products_category_id_index on products (category_id);
@@ -0,0 +1,13 @@
+create table audit_logs (
+    id              integer      primary key autoincrement,
+    actor           varchar(255) not null,
+    action          varchar(32)  not null,
+    entity          varchar(32)  not null,
+    entity_id       integer      not null,
+    before_snapshot text         null,
+    after_snapshot  text         null,
+    request_id      varchar(64)  not null default '',
+    created_at      datetime     not null
+);
+
+create index audit_logs_entity_index on audit_logs (entity, entity_id);
@@ -0,0 +1 @@
+drop index categories_name_unique;
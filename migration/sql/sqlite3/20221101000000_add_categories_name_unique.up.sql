@@ -0,0 +1 @@
+create unique index categories_name_unique on categories (lower(name)) where deleted_at is null;
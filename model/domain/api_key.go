@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+type ApiKey struct {
+	Id         int
+	Name       string
+	Prefix     string
+	KeyHash    string
+	Scopes     []string
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
@@ -0,0 +1,46 @@
+package domain
+
+import "time"
+
+const (
+	AuditEntityCategory = "category"
+
+	AuditActionCreate  = "create"
+	AuditActionUpdate  = "update"
+	AuditActionMove    = "move"
+	AuditActionDelete  = "delete"
+	AuditActionRestore = "restore"
+	AuditActionPurge   = "purge"
+
+	// AuditActorSystem is recorded for changes made outside of a request,
+	// such as purging the trash on a schedule.
+	AuditActorSystem = "system"
+)
+
+// AuditLog records one change to an entity. Before and After hold JSON
+// snapshots of the entity and are empty when it did not exist before or
+// does not exist after, such as on create and purge.
+type AuditLog struct {
+	Id        int
+	Actor     string
+	Action    string
+	Entity    string
+	EntityId  int
+	Before    string
+	After     string
+	RequestId string
+	CreatedAt time.Time
+}
+
+// AuditLogFilter selects the entries matching every field that is set;
+// Since and Until bound CreatedAt, inclusive and exclusive respectively.
+type AuditLogFilter struct {
+	Entity   string
+	EntityId int
+	Actor    string
+	Action   string
+	Since    *time.Time
+	Until    *time.Time
+	Limit    int
+	Offset   int
+}
@@ -1,6 +1,15 @@
 package domain
 
+import "time"
+
+// Category.Version starts at 1 and is incremented by every update.
+// ParentId is nil for a root category. Slug is empty only for categories
+// created before slugs were introduced.
 type Category struct {
-	Id   int
-	Name string
+	Id        int
+	Name      string
+	Slug      string
+	ParentId  *int
+	Version   int
+	DeletedAt *time.Time
 }
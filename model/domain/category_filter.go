@@ -0,0 +1,17 @@
+package domain
+
+// CategoryFilter selects live categories, or only trashed ones when Trashed
+// is set.
+type CategoryFilter struct {
+	Name    string
+	Sort    string
+	Limit   int
+	Offset  int
+	After   *CategoryCursor
+	Trashed bool
+}
+
+type CategoryCursor struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+}
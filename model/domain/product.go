@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type Product struct {
+	Id         int
+	CategoryId int
+	Name       string
+	Sku        string
+	Price      decimal.Decimal
+	Stock      int
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// ProductFilter selects products by category when CategoryId is set and by
+// a part of the name or SKU when Query is set.
+type ProductFilter struct {
+	CategoryId int
+	Query      string
+	Limit      int
+	Offset     int
+}
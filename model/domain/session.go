@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// Session is a login of a user. Only SHA-256 hashes of its access and
+// refresh tokens are stored.
+type Session struct {
+	Id               int
+	UserId           int
+	AccessTokenHash  string
+	RefreshTokenHash string
+	AccessExpiresAt  time.Time
+	RefreshExpiresAt time.Time
+	RevokedAt        *time.Time
+	CreatedAt        time.Time
+}
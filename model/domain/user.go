@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+type User struct {
+	Id           int
+	Email        string
+	Name         string
+	PasswordHash string
+	Roles        []string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
@@ -0,0 +1,9 @@
+package web
+
+import "time"
+
+type ApiKeyCreateRequest struct {
+	Name      string     `validate:"required,max=255,min=1" json:"name"`
+	Scopes    []string   `validate:"required,min=1,dive,scope" json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
@@ -0,0 +1,21 @@
+package web
+
+import "time"
+
+type ApiKeyResponse struct {
+	Id         int        `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// ApiKeyIssueResponse is only returned when a key is issued or rotated; the
+// plain key cannot be recovered afterwards.
+type ApiKeyIssueResponse struct {
+	ApiKeyResponse
+	Key string `json:"key"`
+}
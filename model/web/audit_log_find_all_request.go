@@ -0,0 +1,14 @@
+package web
+
+import "time"
+
+type AuditLogFindAllRequest struct {
+	Page    int        `validate:"omitempty,min=1" json:"page"`
+	PerPage int        `validate:"omitempty,min=1,max=100" json:"per_page"`
+	Entity  string     `validate:"omitempty,oneof=category" json:"entity"`
+	Id      int        `validate:"omitempty,min=1" json:"id"`
+	Actor   string     `validate:"omitempty,max=255" json:"actor"`
+	Action  string     `validate:"omitempty,oneof=create update move delete restore purge" json:"action"`
+	Since   *time.Time `json:"since"`
+	Until   *time.Time `json:"until"`
+}
@@ -0,0 +1,20 @@
+package web
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditLogResponse.Before and After are the entity as the API returned it
+// before and after the change, or null.
+type AuditLogResponse struct {
+	Id        int             `json:"id"`
+	Actor     string          `json:"actor"`
+	Action    string          `json:"action"`
+	Entity    string          `json:"entity"`
+	EntityId  int             `json:"entity_id"`
+	Before    json.RawMessage `json:"before"`
+	After     json.RawMessage `json:"after"`
+	RequestId string          `json:"request_id,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
@@ -0,0 +1,29 @@
+package web
+
+const (
+	BulkModeAtomic  = "atomic"
+	BulkModePartial = "partial"
+
+	BulkOpCreate = "create"
+	BulkOpUpdate = "update"
+	BulkOpDelete = "delete"
+)
+
+// CategoryBulkRequest runs its operations in one transaction in atomic mode,
+// the default, and each on its own in partial mode. RequireVersion makes
+// update and delete operations without a version fail with 428.
+type CategoryBulkRequest struct {
+	Mode           string                  `validate:"omitempty,oneof=atomic partial" json:"mode"`
+	Operations     []CategoryBulkOperation `validate:"required,min=1,max=1000,dive" json:"operations"`
+	RequireVersion bool                    `json:"-"`
+}
+
+// CategoryBulkOperation.Version plays the role of If-Match for update and
+// delete; zero skips the check.
+type CategoryBulkOperation struct {
+	Op       string `validate:"required,oneof=create update delete" json:"op"`
+	Id       int    `json:"id,omitempty"`
+	Name     string `json:"name,omitempty"`
+	ParentId *int   `json:"parent_id,omitempty"`
+	Version  int    `json:"version,omitempty"`
+}
@@ -0,0 +1,13 @@
+package web
+
+// CategoryBulkResult reports one bulk operation. The service sets Data or
+// Err and the controller turns Err into Code, Status and Error.
+type CategoryBulkResult struct {
+	Index  int               `json:"index"`
+	Op     string            `json:"op"`
+	Code   int               `json:"code"`
+	Status string            `json:"status"`
+	Data   *CategoryResponse `json:"data,omitempty"`
+	Error  interface{}       `json:"error,omitempty"`
+	Err    error             `json:"-"`
+}
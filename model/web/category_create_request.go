@@ -1,5 +1,6 @@
 package web
 
 type CategoryCreateRequest struct {
-	Name string `validate:"required,max=255,min=1" json:"name"`
+	Name     string `validate:"required,max=255,min=1" json:"name"`
+	ParentId *int   `validate:"omitempty,min=1" json:"parent_id"`
 }
@@ -0,0 +1,12 @@
+package web
+
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+)
+
+type CategoryExportRequest struct {
+	Format string `validate:"required,oneof=csv jsonl" json:"format"`
+	Sort   string `validate:"omitempty,oneof=id -id name -name" json:"sort"`
+	Q      string `validate:"omitempty,max=255" json:"q"`
+}
@@ -0,0 +1,9 @@
+package web
+
+type CategoryFindAllRequest struct {
+	Page    int    `validate:"omitempty,min=1" json:"page"`
+	PerPage int    `validate:"omitempty,min=1,max=100" json:"per_page"`
+	After   string `validate:"omitempty,max=512" json:"after"`
+	Sort    string `validate:"omitempty,oneof=id -id name -name" json:"sort"`
+	Q       string `validate:"omitempty,max=255" json:"q"`
+}
@@ -0,0 +1,11 @@
+package web
+
+import "io"
+
+// CategoryImportRequest reads the rows to import from File, in the format
+// the export writes. DryRun validates them without importing anything.
+type CategoryImportRequest struct {
+	Format string    `validate:"required,oneof=csv jsonl" json:"format"`
+	DryRun bool      `json:"dry_run"`
+	File   io.Reader `json:"-"`
+}
@@ -0,0 +1,20 @@
+package web
+
+// CategoryImportResponse reports an import. Rows are numbered from 1, not
+// counting the CSV header, and nothing is imported unless all of them are
+// valid.
+type CategoryImportResponse struct {
+	DryRun   bool                     `json:"dry_run"`
+	Total    int                      `json:"total"`
+	Imported int                      `json:"imported"`
+	Failed   int                      `json:"failed"`
+	Errors   []CategoryImportRowError `json:"errors,omitempty"`
+}
+
+// CategoryImportRowError carries the error of a row in Err, which the
+// controller turns into Errors.
+type CategoryImportRowError struct {
+	Row    int         `json:"row"`
+	Errors interface{} `json:"errors"`
+	Err    error       `json:"-"`
+}
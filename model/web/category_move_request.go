@@ -0,0 +1,10 @@
+package web
+
+// CategoryMoveRequest moves a category and its subtree under ParentId, or
+// to the root when it is null. Version is taken from If-Match as for
+// updates.
+type CategoryMoveRequest struct {
+	Id       int  `validate:"required" json:"id"`
+	ParentId *int `validate:"omitempty,min=1" json:"parent_id"`
+	Version  int  `json:"-"`
+}
@@ -0,0 +1,16 @@
+package web
+
+const (
+	MergePatchMediaType = "application/merge-patch+json"
+	JSONPatchMediaType  = "application/json-patch+json"
+)
+
+// CategoryPatchRequest carries a patch document of MediaType, either a JSON
+// Merge Patch (RFC 7396) or a JSON Patch (RFC 6902), for category Id.
+// Version is the expected version from If-Match; zero skips the check.
+type CategoryPatchRequest struct {
+	Id        int
+	Version   int
+	MediaType string
+	Patch     []byte
+}
@@ -1,6 +1,12 @@
 package web
 
+import "time"
+
 type CategoryResponse struct {
-	Id   int    `json:"id"`
-	Name string `json:"name"`
+	Id        int        `json:"id"`
+	Name      string     `json:"name"`
+	Slug      string     `json:"slug"`
+	ParentId  *int       `json:"parent_id"`
+	Version   int        `json:"version"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
@@ -0,0 +1,9 @@
+package web
+
+type CategoryTreeResponse struct {
+	Id       int                    `json:"id"`
+	Name     string                 `json:"name"`
+	Slug     string                 `json:"slug"`
+	Version  int                    `json:"version"`
+	Children []CategoryTreeResponse `json:"children"`
+}
@@ -1,6 +1,9 @@
 package web
 
+// CategoryUpdateRequest.Version is the version the client expects to
+// replace, taken from If-Match; zero skips the check.
 type CategoryUpdateRequest struct {
-	Id   int    `validate:"required"`
-	Name string `validate:"required,max=255,min=1" json:"name"`
+	Id      int    `validate:"required" json:"id"`
+	Name    string `validate:"required,max=255,min=1" json:"name"`
+	Version int    `json:"-"`
 }
@@ -0,0 +1,8 @@
+package web
+
+type FieldErrorResponse struct {
+	Field   string `json:"field"`
+	Tag     string `json:"tag,omitempty"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,20 @@
+package web
+
+const (
+	HealthUp   = "up"
+	HealthDown = "down"
+)
+
+// HealthResponse is up when every check is. The liveness and readiness
+// probes leave out the checks.
+type HealthResponse struct {
+	Status string                `json:"status"`
+	Checks []HealthCheckResponse `json:"checks,omitempty"`
+}
+
+type HealthCheckResponse struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
@@ -0,0 +1,14 @@
+package web
+
+type PageMeta struct {
+	Total      int       `json:"total"`
+	Page       int       `json:"page,omitempty"`
+	PerPage    int       `json:"per_page"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+	Links      PageLinks `json:"links"`
+}
+
+type PageLinks struct {
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
+}
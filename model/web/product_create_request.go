@@ -0,0 +1,13 @@
+package web
+
+import "github.com/shopspring/decimal"
+
+// ProductCreateRequest.Price accepts a JSON string or number; the service
+// checks it has at most two decimal places and is not negative.
+type ProductCreateRequest struct {
+	CategoryId int              `validate:"required,min=1" json:"category_id"`
+	Name       string           `validate:"required,max=255,min=1" json:"name"`
+	Sku        string           `validate:"required,max=64,min=1,printascii" json:"sku"`
+	Price      *decimal.Decimal `validate:"required" json:"price"`
+	Stock      int              `validate:"min=0" json:"stock"`
+}
@@ -0,0 +1,8 @@
+package web
+
+type ProductFindAllRequest struct {
+	Page       int    `validate:"omitempty,min=1" json:"page"`
+	PerPage    int    `validate:"omitempty,min=1,max=100" json:"per_page"`
+	Q          string `validate:"omitempty,max=255" json:"q"`
+	CategoryId int    `validate:"omitempty,min=1" json:"category_id"`
+}
@@ -0,0 +1,16 @@
+package web
+
+import "time"
+
+// ProductResponse.Price always has two decimal places, such as "19.90", and
+// is a string so that clients do not read it as a float.
+type ProductResponse struct {
+	Id         int       `json:"id"`
+	CategoryId int       `json:"category_id"`
+	Name       string    `json:"name"`
+	Sku        string    `json:"sku"`
+	Price      string    `json:"price"`
+	Stock      int       `json:"stock"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
@@ -0,0 +1,12 @@
+package web
+
+import "github.com/shopspring/decimal"
+
+type ProductUpdateRequest struct {
+	Id         int              `validate:"required" json:"id"`
+	CategoryId int              `validate:"required,min=1" json:"category_id"`
+	Name       string           `validate:"required,max=255,min=1" json:"name"`
+	Sku        string           `validate:"required,max=64,min=1,printascii" json:"sku"`
+	Price      *decimal.Decimal `validate:"required" json:"price"`
+	Stock      int              `validate:"min=0" json:"stock"`
+}
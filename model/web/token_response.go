@@ -0,0 +1,11 @@
+package web
+
+// TokenResponse is returned by login and refresh. ExpiresIn and
+// RefreshExpiresIn are in seconds.
+type TokenResponse struct {
+	TokenType        string `json:"token_type"`
+	AccessToken      string `json:"access_token"`
+	ExpiresIn        int    `json:"expires_in"`
+	RefreshToken     string `json:"refresh_token"`
+	RefreshExpiresIn int    `json:"refresh_expires_in"`
+}
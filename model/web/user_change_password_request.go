@@ -0,0 +1,6 @@
+package web
+
+type UserChangePasswordRequest struct {
+	CurrentPassword string `validate:"required" json:"current_password"`
+	NewPassword     string `validate:"required,min=8,max=72,nefield=CurrentPassword" json:"new_password"`
+}
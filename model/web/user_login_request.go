@@ -0,0 +1,6 @@
+package web
+
+type UserLoginRequest struct {
+	Email    string `validate:"required" json:"email"`
+	Password string `validate:"required" json:"password"`
+}
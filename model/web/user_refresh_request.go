@@ -0,0 +1,5 @@
+package web
+
+type UserRefreshRequest struct {
+	RefreshToken string `validate:"required" json:"refresh_token"`
+}
@@ -0,0 +1,7 @@
+package web
+
+type UserRegisterRequest struct {
+	Email    string `validate:"required,email,max=255" json:"email"`
+	Name     string `validate:"required,max=200,min=1" json:"name"`
+	Password string `validate:"required,min=8,max=72" json:"password"`
+}
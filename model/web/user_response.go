@@ -0,0 +1,11 @@
+package web
+
+import "time"
+
+type UserResponse struct {
+	Id        int       `json:"id"`
+	Email     string    `json:"email"`
+	Name      string    `json:"name"`
+	Roles     []string  `json:"roles"`
+	CreatedAt time.Time `json:"created_at"`
+}
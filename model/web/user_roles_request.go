@@ -0,0 +1,5 @@
+package web
+
+type UserRolesRequest struct {
+	Roles []string `validate:"required,min=1,dive,oneof=viewer editor admin" json:"roles"`
+}
@@ -4,4 +4,5 @@ type WebResponse struct {
 	Code   int         `json:"code"`
 	Status string      `json:"status"`
 	Data   interface{} `json:"data"`
+	Meta   interface{} `json:"meta,omitempty"`
 }
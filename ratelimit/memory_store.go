@@ -0,0 +1,101 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often MemoryStore drops the buckets that have
+// filled up again, which behave like new ones.
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	full    time.Time
+}
+
+// MemoryStore keeps the buckets in this process, so every instance of the
+// API limits its clients on its own.
+type MemoryStore struct {
+	mutex     sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	Now       func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: map[string]*bucket{},
+		Now:     time.Now,
+	}
+}
+
+func (store *MemoryStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	now := store.Now()
+	store.sweep(now)
+
+	b, ok := store.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updated: now}
+		store.buckets[key] = b
+	}
+
+	b.tokens = b.refilled(now, limit)
+	b.updated = now
+
+	var result Result
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = refillTime(1-b.tokens, limit)
+	}
+
+	result.Remaining = int(b.tokens)
+	result.Reset = refillTime(float64(limit.Burst)-b.tokens, limit)
+	b.full = now.Add(result.Reset)
+
+	return result, nil
+}
+
+func (store *MemoryStore) Peek(ctx context.Context, key string, limit Limit) (Result, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	tokens := float64(limit.Burst)
+	if b, ok := store.buckets[key]; ok {
+		tokens = b.refilled(store.Now(), limit)
+	}
+
+	result := Result{Allowed: tokens >= 1, Remaining: int(tokens)}
+	if !result.Allowed {
+		result.RetryAfter = refillTime(1-tokens, limit)
+	}
+	result.Reset = refillTime(float64(limit.Burst)-tokens, limit)
+
+	return result, nil
+}
+
+// refilled is the number of tokens in the bucket at now.
+func (b *bucket) refilled(now time.Time, limit Limit) float64 {
+	return math.Min(float64(limit.Burst), b.tokens+now.Sub(b.updated).Seconds()*limit.Rate)
+}
+
+func (store *MemoryStore) sweep(now time.Time) {
+	if now.Sub(store.lastSweep) < sweepInterval {
+		return
+	}
+	store.lastSweep = now
+
+	for key, b := range store.buckets {
+		if !now.Before(b.full) {
+			delete(store.buckets, key)
+		}
+	}
+}
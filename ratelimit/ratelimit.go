@@ -0,0 +1,39 @@
+// Package ratelimit limits how often each client may call the API with
+// token buckets.
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Limit refills a bucket with Rate tokens per second up to Burst tokens.
+// Every request takes one token and is refused when none is left.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Result is the state of a bucket once a request has tried to take a
+// token from it. RetryAfter is the wait for the next token of a refused
+// request and Reset the time until the bucket is full again.
+type Result struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration
+	Reset      time.Duration
+}
+
+// Store keeps the buckets by key. MemoryStore keeps them in this process;
+// a store shared by several instances of the API implements the same
+// interface, taking the token atomically. Peek reports whether Take would
+// be allowed without taking a token, for limits that only count some
+// outcomes, such as failed authentications.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+	Peek(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+func refillTime(tokens float64, limit Limit) time.Duration {
+	return time.Duration(tokens / limit.Rate * float64(time.Second))
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"sudutkampus/gorestfulapi/model/domain"
+)
+
+var ErrApiKeyNotFound = errors.New("api key not found")
+
+type ApiKeyRepository interface {
+	Save(ctx context.Context, tx Tx, apiKey domain.ApiKey) (domain.ApiKey, error)
+	Update(ctx context.Context, tx Tx, apiKey domain.ApiKey) (domain.ApiKey, error)
+	TouchLastUsed(ctx context.Context, tx Tx, apiKeyId int, lastUsedAt time.Time) error
+	FindById(ctx context.Context, tx Tx, apiKeyId int) (domain.ApiKey, error)
+	FindByPrefix(ctx context.Context, tx Tx, prefix string) (domain.ApiKey, error)
+	FindAll(ctx context.Context, tx Tx) ([]domain.ApiKey, error)
+}
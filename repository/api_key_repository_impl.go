@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"sudutkampus/gorestfulapi/model/domain"
+)
+
+type ApiKeyRepositoryImpl struct {
+}
+
+func NewApiKeyRepository() ApiKeyRepository {
+	return &ApiKeyRepositoryImpl{}
+}
+
+const apiKeyColumns = "id, name, prefix, key_hash, scopes, expires_at, last_used_at, revoked_at, created_at"
+
+func (repository *ApiKeyRepositoryImpl) Save(ctx context.Context, tx Tx, apiKey domain.ApiKey) (domain.ApiKey, error) {
+	SQL := "insert into api_keys(name, prefix, key_hash, scopes, expires_at, created_at) values (?, ?, ?, ?, ?, ?)"
+
+	result, err := sqlTx(tx).ExecContext(ctx, SQL, apiKey.Name, apiKey.Prefix, apiKey.KeyHash,
+		strings.Join(apiKey.Scopes, ","), nullTime(apiKey.ExpiresAt), apiKey.CreatedAt)
+	if err != nil {
+		return apiKey, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return apiKey, err
+	}
+
+	apiKey.Id = int(id)
+
+	return apiKey, nil
+}
+
+func (repository *ApiKeyRepositoryImpl) Update(ctx context.Context, tx Tx, apiKey domain.ApiKey) (domain.ApiKey, error) {
+	SQL := "update api_keys set name = ?, prefix = ?, key_hash = ?, scopes = ?, expires_at = ?, revoked_at = ? where id = ?"
+
+	_, err := sqlTx(tx).ExecContext(ctx, SQL, apiKey.Name, apiKey.Prefix, apiKey.KeyHash,
+		strings.Join(apiKey.Scopes, ","), nullTime(apiKey.ExpiresAt), nullTime(apiKey.RevokedAt), apiKey.Id)
+	if err != nil {
+		return apiKey, err
+	}
+
+	return apiKey, nil
+}
+
+func (repository *ApiKeyRepositoryImpl) TouchLastUsed(ctx context.Context, tx Tx, apiKeyId int, lastUsedAt time.Time) error {
+	SQL := "update api_keys set last_used_at = ? where id = ?"
+
+	_, err := sqlTx(tx).ExecContext(ctx, SQL, lastUsedAt, apiKeyId)
+	return err
+}
+
+func (repository *ApiKeyRepositoryImpl) FindById(ctx context.Context, tx Tx, apiKeyId int) (domain.ApiKey, error) {
+	SQL := "select " + apiKeyColumns + " from api_keys where id = ?"
+
+	return scanApiKey(sqlTx(tx).QueryRowContext(ctx, SQL, apiKeyId))
+}
+
+func (repository *ApiKeyRepositoryImpl) FindByPrefix(ctx context.Context, tx Tx, prefix string) (domain.ApiKey, error) {
+	SQL := "select " + apiKeyColumns + " from api_keys where prefix = ?"
+
+	return scanApiKey(sqlTx(tx).QueryRowContext(ctx, SQL, prefix))
+}
+
+func (repository *ApiKeyRepositoryImpl) FindAll(ctx context.Context, tx Tx) ([]domain.ApiKey, error) {
+	SQL := "select " + apiKeyColumns + " from api_keys order by id"
+
+	rows, err := sqlTx(tx).QueryContext(ctx, SQL)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var apiKeys []domain.ApiKey
+	for rows.Next() {
+		apiKey, err := scanApiKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		apiKeys = append(apiKeys, apiKey)
+	}
+
+	return apiKeys, rows.Err()
+}
+
+func scanApiKey(row scanner) (domain.ApiKey, error) {
+	apiKey := domain.ApiKey{}
+	var scopes string
+	var expiresAt, lastUsedAt, revokedAt sql.NullTime
+
+	err := row.Scan(&apiKey.Id, &apiKey.Name, &apiKey.Prefix, &apiKey.KeyHash, &scopes,
+		&expiresAt, &lastUsedAt, &revokedAt, &apiKey.CreatedAt)
+	if err == sql.ErrNoRows {
+		return apiKey, ErrApiKeyNotFound
+	}
+	if err != nil {
+		return apiKey, err
+	}
+
+	if scopes != "" {
+		apiKey.Scopes = strings.Split(scopes, ",")
+	}
+	apiKey.ExpiresAt = timePointer(expiresAt)
+	apiKey.LastUsedAt = timePointer(lastUsedAt)
+	apiKey.RevokedAt = timePointer(revokedAt)
+
+	return apiKey, nil
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"sudutkampus/gorestfulapi/model/domain"
+)
+
+type ApiKeyRepositoryMemory struct {
+}
+
+func NewApiKeyRepositoryMemory() ApiKeyRepository {
+	return &ApiKeyRepositoryMemory{}
+}
+
+func (repository *ApiKeyRepositoryMemory) Save(ctx context.Context, tx Tx, apiKey domain.ApiKey) (domain.ApiKey, error) {
+	db := memoryTx(tx)
+
+	apiKey.Id = db.NextApiKeyId
+	db.NextApiKeyId++
+	db.ApiKeys[apiKey.Id] = apiKey
+
+	return apiKey, nil
+}
+
+func (repository *ApiKeyRepositoryMemory) Update(ctx context.Context, tx Tx, apiKey domain.ApiKey) (domain.ApiKey, error) {
+	db := memoryTx(tx)
+
+	if stored, ok := db.ApiKeys[apiKey.Id]; ok {
+		apiKey.LastUsedAt = stored.LastUsedAt
+		apiKey.CreatedAt = stored.CreatedAt
+		db.ApiKeys[apiKey.Id] = apiKey
+	}
+
+	return apiKey, nil
+}
+
+func (repository *ApiKeyRepositoryMemory) TouchLastUsed(ctx context.Context, tx Tx, apiKeyId int, lastUsedAt time.Time) error {
+	db := memoryTx(tx)
+
+	if apiKey, ok := db.ApiKeys[apiKeyId]; ok {
+		apiKey.LastUsedAt = &lastUsedAt
+		db.ApiKeys[apiKeyId] = apiKey
+	}
+
+	return nil
+}
+
+func (repository *ApiKeyRepositoryMemory) FindById(ctx context.Context, tx Tx, apiKeyId int) (domain.ApiKey, error) {
+	apiKey, ok := memoryTx(tx).ApiKeys[apiKeyId]
+	if !ok {
+		return domain.ApiKey{}, ErrApiKeyNotFound
+	}
+
+	return apiKey, nil
+}
+
+func (repository *ApiKeyRepositoryMemory) FindByPrefix(ctx context.Context, tx Tx, prefix string) (domain.ApiKey, error) {
+	for _, apiKey := range memoryTx(tx).ApiKeys {
+		if apiKey.Prefix == prefix {
+			return apiKey, nil
+		}
+	}
+
+	return domain.ApiKey{}, ErrApiKeyNotFound
+}
+
+func (repository *ApiKeyRepositoryMemory) FindAll(ctx context.Context, tx Tx) ([]domain.ApiKey, error) {
+	var apiKeys []domain.ApiKey
+	for _, apiKey := range memoryTx(tx).ApiKeys {
+		apiKeys = append(apiKeys, apiKey)
+	}
+
+	sort.Slice(apiKeys, func(i, j int) bool {
+		return apiKeys[i].Id < apiKeys[j].Id
+	})
+
+	return apiKeys, nil
+}
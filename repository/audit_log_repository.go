@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"sudutkampus/gorestfulapi/model/domain"
+)
+
+// AuditLogRepository is append-only and lists the newest entries first.
+type AuditLogRepository interface {
+	Save(ctx context.Context, tx Tx, auditLog domain.AuditLog) (domain.AuditLog, error)
+	FindAll(ctx context.Context, tx Tx, filter domain.AuditLogFilter) ([]domain.AuditLog, error)
+	Count(ctx context.Context, tx Tx, filter domain.AuditLogFilter) (int, error)
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"sudutkampus/gorestfulapi/model/domain"
+)
+
+type AuditLogRepositoryImpl struct {
+}
+
+func NewAuditLogRepository() AuditLogRepository {
+	return &AuditLogRepositoryImpl{}
+}
+
+const auditLogColumns = "id, actor, action, entity, entity_id, before_snapshot, after_snapshot, request_id, created_at"
+
+func (repository *AuditLogRepositoryImpl) Save(ctx context.Context, tx Tx, auditLog domain.AuditLog) (domain.AuditLog, error) {
+	SQL := "insert into audit_logs(actor, action, entity, entity_id, before_snapshot, after_snapshot, request_id, created_at) values (?, ?, ?, ?, ?, ?, ?, ?)"
+
+	result, err := sqlTx(tx).ExecContext(ctx, SQL, auditLog.Actor, auditLog.Action, auditLog.Entity, auditLog.EntityId,
+		nullString(auditLog.Before), nullString(auditLog.After), auditLog.RequestId, auditLog.CreatedAt)
+	if err != nil {
+		return auditLog, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return auditLog, err
+	}
+
+	auditLog.Id = int(id)
+
+	return auditLog, nil
+}
+
+func (repository *AuditLogRepositoryImpl) FindAll(ctx context.Context, tx Tx, filter domain.AuditLogFilter) ([]domain.AuditLog, error) {
+	where, args := auditLogWhere(filter)
+	SQL := "select " + auditLogColumns + " from audit_logs" + where + " order by id desc"
+
+	if filter.Limit > 0 {
+		SQL += " limit ? offset ?"
+		args = append(args, filter.Limit, filter.Offset)
+	}
+
+	rows, err := sqlTx(tx).QueryContext(ctx, SQL, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var auditLogs []domain.AuditLog
+	for rows.Next() {
+		auditLog, err := scanAuditLog(rows)
+		if err != nil {
+			return nil, err
+		}
+		auditLogs = append(auditLogs, auditLog)
+	}
+
+	return auditLogs, rows.Err()
+}
+
+func (repository *AuditLogRepositoryImpl) Count(ctx context.Context, tx Tx, filter domain.AuditLogFilter) (int, error) {
+	where, args := auditLogWhere(filter)
+	SQL := "select count(*) from audit_logs" + where
+
+	var total int
+	err := sqlTx(tx).QueryRowContext(ctx, SQL, args...).Scan(&total)
+
+	return total, err
+}
+
+func auditLogWhere(filter domain.AuditLogFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if filter.Entity != "" {
+		conditions = append(conditions, "entity = ?")
+		args = append(args, filter.Entity)
+	}
+
+	if filter.EntityId != 0 {
+		conditions = append(conditions, "entity_id = ?")
+		args = append(args, filter.EntityId)
+	}
+
+	if filter.Actor != "" {
+		conditions = append(conditions, "actor = ?")
+		args = append(args, filter.Actor)
+	}
+
+	if filter.Action != "" {
+		conditions = append(conditions, "action = ?")
+		args = append(args, filter.Action)
+	}
+
+	if filter.Since != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, *filter.Since)
+	}
+
+	if filter.Until != nil {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, *filter.Until)
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+
+	return " where " + strings.Join(conditions, " and "), args
+}
+
+func scanAuditLog(row scanner) (domain.AuditLog, error) {
+	auditLog := domain.AuditLog{}
+	var before, after sql.NullString
+
+	err := row.Scan(&auditLog.Id, &auditLog.Actor, &auditLog.Action, &auditLog.Entity, &auditLog.EntityId,
+		&before, &after, &auditLog.RequestId, &auditLog.CreatedAt)
+	auditLog.Before = before.String
+	auditLog.After = after.String
+
+	return auditLog, err
+}
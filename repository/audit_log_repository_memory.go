@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+
+	"sudutkampus/gorestfulapi/model/domain"
+)
+
+type AuditLogRepositoryMemory struct {
+}
+
+func NewAuditLogRepositoryMemory() AuditLogRepository {
+	return &AuditLogRepositoryMemory{}
+}
+
+func (repository *AuditLogRepositoryMemory) Save(ctx context.Context, tx Tx, auditLog domain.AuditLog) (domain.AuditLog, error) {
+	db := memoryTx(tx)
+
+	auditLog.Id = len(db.AuditLogs) + 1
+	db.AuditLogs = append(db.AuditLogs, auditLog)
+
+	return auditLog, nil
+}
+
+func (repository *AuditLogRepositoryMemory) FindAll(ctx context.Context, tx Tx, filter domain.AuditLogFilter) ([]domain.AuditLog, error) {
+	auditLogs := filterAuditLogs(memoryTx(tx), filter)
+
+	if filter.Offset > 0 {
+		if filter.Offset >= len(auditLogs) {
+			return nil, nil
+		}
+		auditLogs = auditLogs[filter.Offset:]
+	}
+
+	if filter.Limit > 0 && len(auditLogs) > filter.Limit {
+		auditLogs = auditLogs[:filter.Limit]
+	}
+
+	return auditLogs, nil
+}
+
+func (repository *AuditLogRepositoryMemory) Count(ctx context.Context, tx Tx, filter domain.AuditLogFilter) (int, error) {
+	return len(filterAuditLogs(memoryTx(tx), filter)), nil
+}
+
+// filterAuditLogs returns the matching entries newest first.
+func filterAuditLogs(db *MemoryDB, filter domain.AuditLogFilter) []domain.AuditLog {
+	var auditLogs []domain.AuditLog
+	for i := len(db.AuditLogs) - 1; i >= 0; i-- {
+		auditLog := db.AuditLogs[i]
+		switch {
+		case filter.Entity != "" && auditLog.Entity != filter.Entity,
+			filter.EntityId != 0 && auditLog.EntityId != filter.EntityId,
+			filter.Actor != "" && auditLog.Actor != filter.Actor,
+			filter.Action != "" && auditLog.Action != filter.Action,
+			filter.Since != nil && auditLog.CreatedAt.Before(*filter.Since),
+			filter.Until != nil && !auditLog.CreatedAt.Before(*filter.Until):
+			continue
+		}
+		auditLogs = append(auditLogs, auditLog)
+	}
+
+	return auditLogs
+}
@@ -2,15 +2,60 @@ package repository
 
 import (
 	"context"
-	"database/sql"
+	"errors"
+	"time"
 
 	"sudutkampus/gorestfulapi/model/domain"
 )
 
+var (
+	ErrCategoryNotFound        = errors.New("category not found")
+	ErrCategoryVersionConflict = errors.New("category has been modified")
+	ErrCategoryNameConflict    = errors.New("category name already exists")
+	ErrCategorySlugConflict    = errors.New("category slug already exists")
+)
+
+// CategoryRepository soft deletes: Delete moves a category to the trash by
+// setting its DeletedAt, and only Purge and PurgeTrashed remove rows.
+// FindById ignores trashed categories, FindByIdWithTrashed does not.
+//
+// Update and Delete only apply when the stored version still equals
+// category.Version and return ErrCategoryVersionConflict otherwise; Update
+// returns the category with its incremented version.
+//
+// SaveAll inserts categories with multi-row inserts and returns them with
+// their ids in the order given. It relies on slugs being unique to find
+// the ids of the inserted rows.
+//
+// Slugs are unique across live and trashed categories, which SlugExists
+// checks; FindByName compares names case-insensitively among live ones.
+// The SQL backend also enforces unique names among live categories and
+// returns ErrCategoryNameConflict from Save, SaveAll, Update and Restore,
+// and ErrCategorySlugConflict when a concurrent writer took the slug.
+// FindWithoutSlug returns the categories created before slugs existed.
+//
+// FindChildren returns the live children of a category and Reparent moves
+// them all to another parent, or to the root when it is nil.
+//
+// Each calls fn for the categories FindAll would return, one row at a
+// time, and stops at the first error fn returns.
 type CategoryRepository interface {
-	Save(ctx context.Context, tx *sql.Tx, category domain.Category) domain.Category
-	Update(ctx context.Context, tx *sql.Tx, category domain.Category) domain.Category
-	Delete(ctx context.Context, tx *sql.Tx, category domain.Category)
-	FindById(ctx context.Context, tx *sql.Tx, categoryId int) (domain.Category, error)
-	FindAll(ctx context.Context, tx *sql.Tx) []domain.Category
+	Save(ctx context.Context, tx Tx, category domain.Category) (domain.Category, error)
+	SaveAll(ctx context.Context, tx Tx, categories []domain.Category) ([]domain.Category, error)
+	Update(ctx context.Context, tx Tx, category domain.Category) (domain.Category, error)
+	Delete(ctx context.Context, tx Tx, category domain.Category) error
+	Restore(ctx context.Context, tx Tx, category domain.Category) error
+	Purge(ctx context.Context, tx Tx, category domain.Category) error
+	PurgeTrashed(ctx context.Context, tx Tx, deletedBefore time.Time) (int, error)
+	FindById(ctx context.Context, tx Tx, categoryId int) (domain.Category, error)
+	FindByIdWithTrashed(ctx context.Context, tx Tx, categoryId int) (domain.Category, error)
+	FindBySlug(ctx context.Context, tx Tx, slug string) (domain.Category, error)
+	FindByName(ctx context.Context, tx Tx, name string) (domain.Category, error)
+	SlugExists(ctx context.Context, tx Tx, slug string) (bool, error)
+	FindWithoutSlug(ctx context.Context, tx Tx) ([]domain.Category, error)
+	FindChildren(ctx context.Context, tx Tx, parentId int) ([]domain.Category, error)
+	Reparent(ctx context.Context, tx Tx, parentId int, newParentId *int) error
+	FindAll(ctx context.Context, tx Tx, filter domain.CategoryFilter) ([]domain.Category, error)
+	Each(ctx context.Context, tx Tx, filter domain.CategoryFilter, fn func(category domain.Category) error) error
+	Count(ctx context.Context, tx Tx, filter domain.CategoryFilter) (int, error)
 }
@@ -3,80 +3,399 @@ package repository
 import (
 	"context"
 	"database/sql"
-	"errors"
+	"strings"
+	"time"
 
-	"sudutkampus/gorestfulapi/helper"
 	"sudutkampus/gorestfulapi/model/domain"
 )
 
 type CategoryRepositoryImpl struct {
+	Driver string
 }
 
-func NewCategoryRepository() CategoryRepository {
-	return &CategoryRepositoryImpl{}
+const categoryColumns = "id, name, slug, parent_id, version, deleted_at"
+
+// categoryInsertBatchSize keeps multi-row inserts well below the
+// placeholder limits of both MySQL and SQLite.
+const categoryInsertBatchSize = 500
+
+func NewCategoryRepository(driver string) CategoryRepository {
+	return &CategoryRepositoryImpl{
+		Driver: driver,
+	}
 }
 
-func (repository *CategoryRepositoryImpl) Save(ctx context.Context, tx *sql.Tx, category domain.Category) domain.Category {
-	SQL := "insert into categories(name) values (?)"
+func (repository *CategoryRepositoryImpl) Save(ctx context.Context, tx Tx, category domain.Category) (domain.Category, error) {
+	SQL := "insert into categories(name, slug, parent_id, version) values (?, ?, ?, 1)"
 
-	result, err := tx.ExecContext(ctx, SQL, category.Name)
-	helper.PanicIfError(err)
+	result, err := sqlTx(tx).ExecContext(ctx, SQL, category.Name, nullString(category.Slug), nullInt(category.ParentId))
+	if err != nil {
+		return category, uniqueConflict(err)
+	}
 
 	id, err := result.LastInsertId()
-	helper.PanicIfError(err)
+	if err != nil {
+		return category, err
+	}
 
 	category.Id = int(id)
+	category.Version = 1
 
-	return category
+	return category, nil
 }
 
-func (repository *CategoryRepositoryImpl) Update(ctx context.Context, tx *sql.Tx, category domain.Category) domain.Category {
-	SQL := "update categories set name = ? where id = ?"
+func (repository *CategoryRepositoryImpl) SaveAll(ctx context.Context, tx Tx, categories []domain.Category) ([]domain.Category, error) {
+	for start := 0; start < len(categories); start += categoryInsertBatchSize {
+		end := start + categoryInsertBatchSize
+		if end > len(categories) {
+			end = len(categories)
+		}
 
-	_, err := tx.ExecContext(ctx, SQL, category.Name, category.Id)
-	helper.PanicIfError(err)
+		err := repository.insertBatch(ctx, tx, categories[start:end])
+		if err != nil {
+			return categories, err
+		}
+	}
 
-	return category
+	return categories, nil
 }
 
-func (repository *CategoryRepositoryImpl) Delete(ctx context.Context, tx *sql.Tx, category domain.Category) {
-	SQL := "delete from categories where id = ?"
+// insertBatch inserts categories with one statement and then reads their
+// ids back by slug, which is unique. Ids are not derived from
+// LastInsertId, since concurrent inserts or an auto_increment_increment
+// above 1 can leave gaps between the ids of one statement. Categories
+// without a slug are inserted one at a time.
+func (repository *CategoryRepositoryImpl) insertBatch(ctx context.Context, tx Tx, categories []domain.Category) error {
+	var values []string
+	var slugs []interface{}
+	args := make([]interface{}, 0, 3*len(categories))
+	for i, category := range categories {
+		if category.Slug == "" {
+			saved, err := repository.Save(ctx, tx, category)
+			if err != nil {
+				return err
+			}
+			categories[i] = saved
+			continue
+		}
 
-	_, err := tx.ExecContext(ctx, SQL, category.Id)
-	helper.PanicIfError(err)
-}
+		values = append(values, "(?, ?, ?, 1)")
+		args = append(args, category.Name, category.Slug, nullInt(category.ParentId))
+		slugs = append(slugs, category.Slug)
+	}
+
+	if len(values) == 0 {
+		return nil
+	}
 
-func (repository *CategoryRepositoryImpl) FindById(ctx context.Context, tx *sql.Tx, categoryId int) (domain.Category, error) {
-	SQL := "select id, name from categories where id = ?"
+	SQL := "insert into categories(name, slug, parent_id, version) values " + strings.Join(values, ", ")
 
-	rows, err := tx.QueryContext(ctx, SQL, categoryId)
-	helper.PanicIfError(err)
+	_, err := sqlTx(tx).ExecContext(ctx, SQL, args...)
+	if err != nil {
+		return uniqueConflict(err)
+	}
+
+	SQL = "select id, slug from categories where slug in (?" + strings.Repeat(", ?", len(slugs)-1) + ")"
+
+	rows, err := sqlTx(tx).QueryContext(ctx, SQL, slugs...)
+	if err != nil {
+		return err
+	}
 	defer rows.Close()
 
-	category := domain.Category{}
-	if rows.Next() {
-		err := rows.Scan(&category.Id, &category.Name)
-		helper.PanicIfError(err)
-		return category, nil
-	} else {
-		return category, errors.New("category not found")
+	ids := make(map[string]int, len(slugs))
+	for rows.Next() {
+		var id int
+		var slug string
+		err = rows.Scan(&id, &slug)
+		if err != nil {
+			return err
+		}
+		ids[slug] = id
+	}
+	err = rows.Err()
+	if err != nil {
+		return err
+	}
+
+	for i := range categories {
+		if categories[i].Slug == "" {
+			continue
+		}
+
+		categories[i].Id = ids[categories[i].Slug]
+		categories[i].Version = 1
+	}
+
+	return nil
+}
+
+func (repository *CategoryRepositoryImpl) Update(ctx context.Context, tx Tx, category domain.Category) (domain.Category, error) {
+	SQL := "update categories set name = ?, slug = ?, parent_id = ?, version = version + 1 where id = ? and version = ?"
+
+	result, err := sqlTx(tx).ExecContext(ctx, SQL, category.Name, nullString(category.Slug), nullInt(category.ParentId), category.Id, category.Version)
+	if err != nil {
+		return category, uniqueConflict(err)
+	}
+
+	err = expectAffected(result, ErrCategoryVersionConflict)
+	if err != nil {
+		return category, err
+	}
+
+	category.Version++
+
+	return category, nil
+}
+
+func (repository *CategoryRepositoryImpl) Delete(ctx context.Context, tx Tx, category domain.Category) error {
+	SQL := "update categories set deleted_at = ?, version = version + 1 where id = ? and version = ?"
+
+	result, err := sqlTx(tx).ExecContext(ctx, SQL, nullTime(category.DeletedAt), category.Id, category.Version)
+	if err != nil {
+		return err
+	}
+
+	return expectAffected(result, ErrCategoryVersionConflict)
+}
+
+func (repository *CategoryRepositoryImpl) Restore(ctx context.Context, tx Tx, category domain.Category) error {
+	SQL := "update categories set deleted_at = null, version = version + 1 where id = ?"
+
+	_, err := sqlTx(tx).ExecContext(ctx, SQL, category.Id)
+	return uniqueConflict(err)
+}
+
+func (repository *CategoryRepositoryImpl) Purge(ctx context.Context, tx Tx, category domain.Category) error {
+	SQL := "delete from categories where id = ?"
+
+	_, err := sqlTx(tx).ExecContext(ctx, SQL, category.Id)
+	return err
+}
+
+func (repository *CategoryRepositoryImpl) PurgeTrashed(ctx context.Context, tx Tx, deletedBefore time.Time) (int, error) {
+	SQL := "delete from categories where deleted_at is not null and deleted_at < ?"
+
+	result, err := sqlTx(tx).ExecContext(ctx, SQL, deletedBefore)
+	if err != nil {
+		return 0, err
 	}
+
+	purged, err := result.RowsAffected()
+	return int(purged), err
+}
+
+func (repository *CategoryRepositoryImpl) FindById(ctx context.Context, tx Tx, categoryId int) (domain.Category, error) {
+	SQL := "select " + categoryColumns + " from categories where id = ? and deleted_at is null"
+
+	return scanCategory(sqlTx(tx).QueryRowContext(ctx, SQL, categoryId))
+}
+
+func (repository *CategoryRepositoryImpl) FindByIdWithTrashed(ctx context.Context, tx Tx, categoryId int) (domain.Category, error) {
+	SQL := "select " + categoryColumns + " from categories where id = ?"
+
+	return scanCategory(sqlTx(tx).QueryRowContext(ctx, SQL, categoryId))
+}
+
+func (repository *CategoryRepositoryImpl) FindBySlug(ctx context.Context, tx Tx, slug string) (domain.Category, error) {
+	SQL := "select " + categoryColumns + " from categories where slug = ? and deleted_at is null"
+
+	return scanCategory(sqlTx(tx).QueryRowContext(ctx, SQL, slug))
+}
+
+func (repository *CategoryRepositoryImpl) FindByName(ctx context.Context, tx Tx, name string) (domain.Category, error) {
+	SQL := "select " + categoryColumns + " from categories where lower(name) = lower(?) and deleted_at is null limit 1"
+
+	return scanCategory(sqlTx(tx).QueryRowContext(ctx, SQL, name))
+}
+
+func (repository *CategoryRepositoryImpl) SlugExists(ctx context.Context, tx Tx, slug string) (bool, error) {
+	SQL := "select count(*) from categories where slug = ?"
+
+	var count int
+	err := sqlTx(tx).QueryRowContext(ctx, SQL, slug).Scan(&count)
+
+	return count > 0, err
+}
+
+func (repository *CategoryRepositoryImpl) FindWithoutSlug(ctx context.Context, tx Tx) ([]domain.Category, error) {
+	SQL := "select " + categoryColumns + " from categories where slug is null order by id asc"
+
+	return repository.query(ctx, tx, SQL)
 }
 
-func (repository *CategoryRepositoryImpl) FindAll(ctx context.Context, tx *sql.Tx) []domain.Category {
-	SQL := "select id, name from categories"
+func (repository *CategoryRepositoryImpl) FindChildren(ctx context.Context, tx Tx, parentId int) ([]domain.Category, error) {
+	SQL := "select " + categoryColumns + " from categories where parent_id = ? and deleted_at is null order by id asc"
+
+	return repository.query(ctx, tx, SQL, parentId)
+}
 
-	rows, err := tx.QueryContext(ctx, SQL)
-	helper.PanicIfError(err)
+func (repository *CategoryRepositoryImpl) query(ctx context.Context, tx Tx, SQL string, args ...interface{}) ([]domain.Category, error) {
+	rows, err := sqlTx(tx).QueryContext(ctx, SQL, args...)
+	if err != nil {
+		return nil, err
+	}
 	defer rows.Close()
 
 	var categories []domain.Category
 	for rows.Next() {
-		category := domain.Category{}
-		err := rows.Scan(&category.Id, &category.Name)
-		helper.PanicIfError(err)
+		category, err := scanCategory(rows)
+		if err != nil {
+			return nil, err
+		}
+		categories = append(categories, category)
+	}
+
+	return categories, rows.Err()
+}
+
+func (repository *CategoryRepositoryImpl) Reparent(ctx context.Context, tx Tx, parentId int, newParentId *int) error {
+	SQL := "update categories set parent_id = ?, version = version + 1 where parent_id = ? and deleted_at is null"
+
+	_, err := sqlTx(tx).ExecContext(ctx, SQL, nullInt(newParentId), parentId)
+	return err
+}
+
+func (repository *CategoryRepositoryImpl) FindAll(ctx context.Context, tx Tx, filter domain.CategoryFilter) ([]domain.Category, error) {
+	var categories []domain.Category
+	err := repository.Each(ctx, tx, filter, func(category domain.Category) error {
 		categories = append(categories, category)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+func (repository *CategoryRepositoryImpl) Each(ctx context.Context, tx Tx, filter domain.CategoryFilter, fn func(category domain.Category) error) error {
+	where, args := categoryWhere(filter, true)
+	SQL := "select " + categoryColumns + " from categories" + where + " order by " + categoryOrder(filter.Sort)
+
+	if filter.Limit > 0 {
+		SQL += " limit ?"
+		args = append(args, filter.Limit)
+		if filter.After == nil && filter.Offset > 0 {
+			SQL += " offset ?"
+			args = append(args, filter.Offset)
+		}
+	}
+
+	rows, err := sqlTx(tx).QueryContext(ctx, SQL, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			return err
+		}
+
+		err = fn(category)
+		if err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (repository *CategoryRepositoryImpl) Count(ctx context.Context, tx Tx, filter domain.CategoryFilter) (int, error) {
+	where, args := categoryWhere(filter, false)
+	SQL := "select count(*) from categories" + where
+
+	var total int
+	err := sqlTx(tx).QueryRowContext(ctx, SQL, args...).Scan(&total)
+
+	return total, err
+}
+
+func categoryWhere(filter domain.CategoryFilter, withCursor bool) (string, []interface{}) {
+	conditions := []string{"deleted_at is null"}
+	if filter.Trashed {
+		conditions[0] = "deleted_at is not null"
+	}
+	var args []interface{}
+
+	if filter.Name != "" {
+		conditions = append(conditions, "name like ? escape '!'")
+		args = append(args, "%"+likeEscaper.Replace(filter.Name)+"%")
+	}
+
+	if withCursor && filter.After != nil {
+		switch filter.Sort {
+		case "name":
+			conditions = append(conditions, "(name > ? or (name = ? and id > ?))")
+			args = append(args, filter.After.Name, filter.After.Name, filter.After.Id)
+		case "-name":
+			conditions = append(conditions, "(name < ? or (name = ? and id < ?))")
+			args = append(args, filter.After.Name, filter.After.Name, filter.After.Id)
+		case "-id":
+			conditions = append(conditions, "id < ?")
+			args = append(args, filter.After.Id)
+		default:
+			conditions = append(conditions, "id > ?")
+			args = append(args, filter.After.Id)
+		}
+	}
+
+	return " where " + strings.Join(conditions, " and "), args
+}
+
+func scanCategory(row scanner) (domain.Category, error) {
+	category := domain.Category{}
+	var slug sql.NullString
+	var parentId sql.NullInt64
+	var deletedAt sql.NullTime
+
+	err := row.Scan(&category.Id, &category.Name, &slug, &parentId, &category.Version, &deletedAt)
+	if err == sql.ErrNoRows {
+		return category, ErrCategoryNotFound
+	}
+	if err != nil {
+		return category, err
+	}
+
+	category.Slug = slug.String
+	category.ParentId = intPointer(parentId)
+	category.DeletedAt = timePointer(deletedAt)
+
+	return category, nil
+}
+
+func categoryOrder(sort string) string {
+	switch sort {
+	case "name":
+		return "name asc, id asc"
+	case "-name":
+		return "name desc, id desc"
+	case "-id":
+		return "id desc"
+	default:
+		return "id asc"
+	}
+}
+
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// uniqueConflict reports a violation of the unique index on the
+// lowercased names of live categories as ErrCategoryNameConflict and one
+// of the unique index on slugs as ErrCategorySlugConflict.
+func uniqueConflict(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "categories_name_unique"):
+		return ErrCategoryNameConflict
+	case strings.Contains(message, "categories_slug_unique"), strings.Contains(message, "categories.slug"):
+		return ErrCategorySlugConflict
 	}
 
-	return categories
+	return err
 }
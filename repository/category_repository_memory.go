@@ -0,0 +1,264 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"sudutkampus/gorestfulapi/model/domain"
+)
+
+type CategoryRepositoryMemory struct {
+}
+
+func NewCategoryRepositoryMemory() CategoryRepository {
+	return &CategoryRepositoryMemory{}
+}
+
+func (repository *CategoryRepositoryMemory) Save(ctx context.Context, tx Tx, category domain.Category) (domain.Category, error) {
+	db := memoryTx(tx)
+
+	category.Id = db.NextCategoryId
+	category.Version = 1
+	db.NextCategoryId++
+	db.Categories[category.Id] = category
+
+	return category, nil
+}
+
+func (repository *CategoryRepositoryMemory) SaveAll(ctx context.Context, tx Tx, categories []domain.Category) ([]domain.Category, error) {
+	for i, category := range categories {
+		categories[i], _ = repository.Save(ctx, tx, category)
+	}
+
+	return categories, nil
+}
+
+func (repository *CategoryRepositoryMemory) Update(ctx context.Context, tx Tx, category domain.Category) (domain.Category, error) {
+	db := memoryTx(tx)
+
+	stored, ok := db.Categories[category.Id]
+	if !ok || stored.Version != category.Version {
+		return category, ErrCategoryVersionConflict
+	}
+
+	category.Version++
+	category.DeletedAt = stored.DeletedAt
+	db.Categories[category.Id] = category
+
+	return category, nil
+}
+
+func (repository *CategoryRepositoryMemory) Delete(ctx context.Context, tx Tx, category domain.Category) error {
+	db := memoryTx(tx)
+
+	stored, ok := db.Categories[category.Id]
+	if !ok || stored.Version != category.Version {
+		return ErrCategoryVersionConflict
+	}
+
+	stored.DeletedAt = category.DeletedAt
+	stored.Version++
+	db.Categories[category.Id] = stored
+
+	return nil
+}
+
+func (repository *CategoryRepositoryMemory) Restore(ctx context.Context, tx Tx, category domain.Category) error {
+	db := memoryTx(tx)
+
+	if stored, ok := db.Categories[category.Id]; ok {
+		stored.DeletedAt = nil
+		stored.Version++
+		db.Categories[category.Id] = stored
+	}
+
+	return nil
+}
+
+func (repository *CategoryRepositoryMemory) Purge(ctx context.Context, tx Tx, category domain.Category) error {
+	delete(memoryTx(tx).Categories, category.Id)
+	return nil
+}
+
+func (repository *CategoryRepositoryMemory) PurgeTrashed(ctx context.Context, tx Tx, deletedBefore time.Time) (int, error) {
+	db := memoryTx(tx)
+
+	purged := 0
+	for id, category := range db.Categories {
+		if category.DeletedAt != nil && category.DeletedAt.Before(deletedBefore) {
+			delete(db.Categories, id)
+			purged++
+		}
+	}
+
+	return purged, nil
+}
+
+func (repository *CategoryRepositoryMemory) FindById(ctx context.Context, tx Tx, categoryId int) (domain.Category, error) {
+	category, ok := memoryTx(tx).Categories[categoryId]
+	if !ok || category.DeletedAt != nil {
+		return domain.Category{}, ErrCategoryNotFound
+	}
+
+	return category, nil
+}
+
+func (repository *CategoryRepositoryMemory) FindByIdWithTrashed(ctx context.Context, tx Tx, categoryId int) (domain.Category, error) {
+	category, ok := memoryTx(tx).Categories[categoryId]
+	if !ok {
+		return domain.Category{}, ErrCategoryNotFound
+	}
+
+	return category, nil
+}
+
+func (repository *CategoryRepositoryMemory) FindBySlug(ctx context.Context, tx Tx, slug string) (domain.Category, error) {
+	for _, category := range memoryTx(tx).Categories {
+		if category.DeletedAt == nil && category.Slug == slug {
+			return category, nil
+		}
+	}
+
+	return domain.Category{}, ErrCategoryNotFound
+}
+
+func (repository *CategoryRepositoryMemory) FindByName(ctx context.Context, tx Tx, name string) (domain.Category, error) {
+	for _, category := range memoryTx(tx).Categories {
+		if category.DeletedAt == nil && strings.EqualFold(category.Name, name) {
+			return category, nil
+		}
+	}
+
+	return domain.Category{}, ErrCategoryNotFound
+}
+
+func (repository *CategoryRepositoryMemory) SlugExists(ctx context.Context, tx Tx, slug string) (bool, error) {
+	for _, category := range memoryTx(tx).Categories {
+		if category.Slug == slug {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (repository *CategoryRepositoryMemory) FindWithoutSlug(ctx context.Context, tx Tx) ([]domain.Category, error) {
+	var categories []domain.Category
+	for _, category := range memoryTx(tx).Categories {
+		if category.Slug == "" {
+			categories = append(categories, category)
+		}
+	}
+
+	sort.Slice(categories, func(i, j int) bool {
+		return categories[i].Id < categories[j].Id
+	})
+
+	return categories, nil
+}
+
+func (repository *CategoryRepositoryMemory) FindChildren(ctx context.Context, tx Tx, parentId int) ([]domain.Category, error) {
+	var categories []domain.Category
+	for _, category := range memoryTx(tx).Categories {
+		if category.DeletedAt == nil && category.ParentId != nil && *category.ParentId == parentId {
+			categories = append(categories, category)
+		}
+	}
+
+	sort.Slice(categories, func(i, j int) bool {
+		return categories[i].Id < categories[j].Id
+	})
+
+	return categories, nil
+}
+
+func (repository *CategoryRepositoryMemory) Reparent(ctx context.Context, tx Tx, parentId int, newParentId *int) error {
+	db := memoryTx(tx)
+
+	for id, category := range db.Categories {
+		if category.DeletedAt == nil && category.ParentId != nil && *category.ParentId == parentId {
+			category.ParentId = newParentId
+			category.Version++
+			db.Categories[id] = category
+		}
+	}
+
+	return nil
+}
+
+func (repository *CategoryRepositoryMemory) FindAll(ctx context.Context, tx Tx, filter domain.CategoryFilter) ([]domain.Category, error) {
+	categories := filterCategories(memoryTx(tx), filter, true)
+
+	sort.Slice(categories, func(i, j int) bool {
+		return categoryLess(categories[i], categories[j], filter.Sort)
+	})
+
+	if filter.After == nil && filter.Offset > 0 {
+		if filter.Offset >= len(categories) {
+			return nil, nil
+		}
+		categories = categories[filter.Offset:]
+	}
+
+	if filter.Limit > 0 && len(categories) > filter.Limit {
+		categories = categories[:filter.Limit]
+	}
+
+	return categories, nil
+}
+
+func (repository *CategoryRepositoryMemory) Each(ctx context.Context, tx Tx, filter domain.CategoryFilter, fn func(category domain.Category) error) error {
+	categories, _ := repository.FindAll(ctx, tx, filter)
+	for _, category := range categories {
+		err := fn(category)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (repository *CategoryRepositoryMemory) Count(ctx context.Context, tx Tx, filter domain.CategoryFilter) (int, error) {
+	return len(filterCategories(memoryTx(tx), filter, false)), nil
+}
+
+func filterCategories(db *MemoryDB, filter domain.CategoryFilter, withCursor bool) []domain.Category {
+	name := strings.ToLower(filter.Name)
+	cursor := domain.Category{}
+	if filter.After != nil {
+		cursor = domain.Category{Id: filter.After.Id, Name: filter.After.Name}
+	}
+
+	var categories []domain.Category
+	for _, category := range db.Categories {
+		if (category.DeletedAt != nil) != filter.Trashed {
+			continue
+		}
+		if name != "" && !strings.Contains(strings.ToLower(category.Name), name) {
+			continue
+		}
+		if withCursor && filter.After != nil && !categoryLess(cursor, category, filter.Sort) {
+			continue
+		}
+		categories = append(categories, category)
+	}
+
+	return categories
+}
+
+// categoryLess mirrors the order by clauses of categoryOrder.
+func categoryLess(a domain.Category, b domain.Category, sort string) bool {
+	switch sort {
+	case "name":
+		return a.Name < b.Name || (a.Name == b.Name && a.Id < b.Id)
+	case "-name":
+		return a.Name > b.Name || (a.Name == b.Name && a.Id > b.Id)
+	case "-id":
+		return a.Id > b.Id
+	default:
+		return a.Id < b.Id
+	}
+}
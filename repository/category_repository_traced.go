@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"sudutkampus/gorestfulapi/model/domain"
+	"sudutkampus/gorestfulapi/tracing"
+)
+
+// CategoryRepositoryTraced records a span for every call to the category
+// repository it wraps, whichever backend that is.
+type CategoryRepositoryTraced struct {
+	Repository CategoryRepository
+	Tracer     trace.Tracer
+}
+
+func NewCategoryRepositoryTraced(repository CategoryRepository, tracer trace.Tracer) CategoryRepository {
+	return &CategoryRepositoryTraced{
+		Repository: repository,
+		Tracer:     tracer,
+	}
+}
+
+func (repository *CategoryRepositoryTraced) Save(ctx context.Context, tx Tx, category domain.Category) (domain.Category, error) {
+	ctx, span := repository.Tracer.Start(ctx, "CategoryRepository.Save")
+	saved, err := repository.Repository.Save(ctx, tx, category)
+	endCategorySpan(span, err)
+
+	return saved, err
+}
+
+func (repository *CategoryRepositoryTraced) SaveAll(ctx context.Context, tx Tx, categories []domain.Category) ([]domain.Category, error) {
+	ctx, span := repository.Tracer.Start(ctx, "CategoryRepository.SaveAll")
+	saved, err := repository.Repository.SaveAll(ctx, tx, categories)
+	endCategorySpan(span, err)
+
+	return saved, err
+}
+
+func (repository *CategoryRepositoryTraced) Update(ctx context.Context, tx Tx, category domain.Category) (domain.Category, error) {
+	ctx, span := repository.Tracer.Start(ctx, "CategoryRepository.Update")
+	updated, err := repository.Repository.Update(ctx, tx, category)
+	endCategorySpan(span, err)
+
+	return updated, err
+}
+
+func (repository *CategoryRepositoryTraced) Delete(ctx context.Context, tx Tx, category domain.Category) error {
+	ctx, span := repository.Tracer.Start(ctx, "CategoryRepository.Delete")
+	err := repository.Repository.Delete(ctx, tx, category)
+	endCategorySpan(span, err)
+
+	return err
+}
+
+func (repository *CategoryRepositoryTraced) Restore(ctx context.Context, tx Tx, category domain.Category) error {
+	ctx, span := repository.Tracer.Start(ctx, "CategoryRepository.Restore")
+	err := repository.Repository.Restore(ctx, tx, category)
+	endCategorySpan(span, err)
+
+	return err
+}
+
+func (repository *CategoryRepositoryTraced) Purge(ctx context.Context, tx Tx, category domain.Category) error {
+	ctx, span := repository.Tracer.Start(ctx, "CategoryRepository.Purge")
+	err := repository.Repository.Purge(ctx, tx, category)
+	endCategorySpan(span, err)
+
+	return err
+}
+
+func (repository *CategoryRepositoryTraced) PurgeTrashed(ctx context.Context, tx Tx, deletedBefore time.Time) (int, error) {
+	ctx, span := repository.Tracer.Start(ctx, "CategoryRepository.PurgeTrashed")
+	count, err := repository.Repository.PurgeTrashed(ctx, tx, deletedBefore)
+	endCategorySpan(span, err)
+
+	return count, err
+}
+
+func (repository *CategoryRepositoryTraced) FindById(ctx context.Context, tx Tx, categoryId int) (domain.Category, error) {
+	ctx, span := repository.Tracer.Start(ctx, "CategoryRepository.FindById")
+	category, err := repository.Repository.FindById(ctx, tx, categoryId)
+	endCategorySpan(span, err)
+
+	return category, err
+}
+
+func (repository *CategoryRepositoryTraced) FindByIdWithTrashed(ctx context.Context, tx Tx, categoryId int) (domain.Category, error) {
+	ctx, span := repository.Tracer.Start(ctx, "CategoryRepository.FindByIdWithTrashed")
+	category, err := repository.Repository.FindByIdWithTrashed(ctx, tx, categoryId)
+	endCategorySpan(span, err)
+
+	return category, err
+}
+
+func (repository *CategoryRepositoryTraced) FindBySlug(ctx context.Context, tx Tx, slug string) (domain.Category, error) {
+	ctx, span := repository.Tracer.Start(ctx, "CategoryRepository.FindBySlug")
+	category, err := repository.Repository.FindBySlug(ctx, tx, slug)
+	endCategorySpan(span, err)
+
+	return category, err
+}
+
+func (repository *CategoryRepositoryTraced) FindByName(ctx context.Context, tx Tx, name string) (domain.Category, error) {
+	ctx, span := repository.Tracer.Start(ctx, "CategoryRepository.FindByName")
+	category, err := repository.Repository.FindByName(ctx, tx, name)
+	endCategorySpan(span, err)
+
+	return category, err
+}
+
+func (repository *CategoryRepositoryTraced) SlugExists(ctx context.Context, tx Tx, slug string) (bool, error) {
+	ctx, span := repository.Tracer.Start(ctx, "CategoryRepository.SlugExists")
+	exists, err := repository.Repository.SlugExists(ctx, tx, slug)
+	endCategorySpan(span, err)
+
+	return exists, err
+}
+
+func (repository *CategoryRepositoryTraced) FindWithoutSlug(ctx context.Context, tx Tx) ([]domain.Category, error) {
+	ctx, span := repository.Tracer.Start(ctx, "CategoryRepository.FindWithoutSlug")
+	categories, err := repository.Repository.FindWithoutSlug(ctx, tx)
+	endCategorySpan(span, err)
+
+	return categories, err
+}
+
+func (repository *CategoryRepositoryTraced) FindChildren(ctx context.Context, tx Tx, parentId int) ([]domain.Category, error) {
+	ctx, span := repository.Tracer.Start(ctx, "CategoryRepository.FindChildren")
+	categories, err := repository.Repository.FindChildren(ctx, tx, parentId)
+	endCategorySpan(span, err)
+
+	return categories, err
+}
+
+func (repository *CategoryRepositoryTraced) Reparent(ctx context.Context, tx Tx, parentId int, newParentId *int) error {
+	ctx, span := repository.Tracer.Start(ctx, "CategoryRepository.Reparent")
+	err := repository.Repository.Reparent(ctx, tx, parentId, newParentId)
+	endCategorySpan(span, err)
+
+	return err
+}
+
+func (repository *CategoryRepositoryTraced) FindAll(ctx context.Context, tx Tx, filter domain.CategoryFilter) ([]domain.Category, error) {
+	ctx, span := repository.Tracer.Start(ctx, "CategoryRepository.FindAll")
+	categories, err := repository.Repository.FindAll(ctx, tx, filter)
+	endCategorySpan(span, err)
+
+	return categories, err
+}
+
+func (repository *CategoryRepositoryTraced) Each(ctx context.Context, tx Tx, filter domain.CategoryFilter, fn func(category domain.Category) error) error {
+	ctx, span := repository.Tracer.Start(ctx, "CategoryRepository.Each")
+	err := repository.Repository.Each(ctx, tx, filter, fn)
+	endCategorySpan(span, err)
+
+	return err
+}
+
+func (repository *CategoryRepositoryTraced) Count(ctx context.Context, tx Tx, filter domain.CategoryFilter) (int, error) {
+	ctx, span := repository.Tracer.Start(ctx, "CategoryRepository.Count")
+	count, err := repository.Repository.Count(ctx, tx, filter)
+	endCategorySpan(span, err)
+
+	return count, err
+}
+
+// endCategorySpan does not mark lookups that found no category as failed,
+// since services use them to check that a name or slug is free.
+func endCategorySpan(span trace.Span, err error) {
+	if errors.Is(err, ErrCategoryNotFound) {
+		err = nil
+	}
+
+	tracing.End(span, err)
+}
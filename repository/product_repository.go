@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"sudutkampus/gorestfulapi/model/domain"
+)
+
+var ErrProductNotFound = errors.New("product not found")
+
+// ProductRepository lists products ordered by id. CountByCategory counts
+// the products of a category, which keep it from being deleted.
+type ProductRepository interface {
+	Save(ctx context.Context, tx Tx, product domain.Product) (domain.Product, error)
+	Update(ctx context.Context, tx Tx, product domain.Product) (domain.Product, error)
+	Delete(ctx context.Context, tx Tx, product domain.Product) error
+	FindById(ctx context.Context, tx Tx, productId int) (domain.Product, error)
+	FindBySku(ctx context.Context, tx Tx, sku string) (domain.Product, error)
+	FindAll(ctx context.Context, tx Tx, filter domain.ProductFilter) ([]domain.Product, error)
+	Count(ctx context.Context, tx Tx, filter domain.ProductFilter) (int, error)
+	CountByCategory(ctx context.Context, tx Tx, categoryId int) (int, error)
+}
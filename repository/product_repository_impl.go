@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"sudutkampus/gorestfulapi/model/domain"
+)
+
+type ProductRepositoryImpl struct {
+}
+
+func NewProductRepository() ProductRepository {
+	return &ProductRepositoryImpl{}
+}
+
+const productColumns = "id, category_id, name, sku, price, stock, created_at, updated_at"
+
+func (repository *ProductRepositoryImpl) Save(ctx context.Context, tx Tx, product domain.Product) (domain.Product, error) {
+	SQL := "insert into products(category_id, name, sku, price, stock, created_at, updated_at) values (?, ?, ?, ?, ?, ?, ?)"
+
+	result, err := sqlTx(tx).ExecContext(ctx, SQL, product.CategoryId, product.Name, product.Sku,
+		product.Price.StringFixed(2), product.Stock, product.CreatedAt, product.UpdatedAt)
+	if err != nil {
+		return product, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return product, err
+	}
+
+	product.Id = int(id)
+
+	return product, nil
+}
+
+func (repository *ProductRepositoryImpl) Update(ctx context.Context, tx Tx, product domain.Product) (domain.Product, error) {
+	SQL := "update products set category_id = ?, name = ?, sku = ?, price = ?, stock = ?, updated_at = ? where id = ?"
+
+	_, err := sqlTx(tx).ExecContext(ctx, SQL, product.CategoryId, product.Name, product.Sku,
+		product.Price.StringFixed(2), product.Stock, product.UpdatedAt, product.Id)
+	if err != nil {
+		return product, err
+	}
+
+	return product, nil
+}
+
+func (repository *ProductRepositoryImpl) Delete(ctx context.Context, tx Tx, product domain.Product) error {
+	SQL := "delete from products where id = ?"
+
+	_, err := sqlTx(tx).ExecContext(ctx, SQL, product.Id)
+	return err
+}
+
+func (repository *ProductRepositoryImpl) FindById(ctx context.Context, tx Tx, productId int) (domain.Product, error) {
+	SQL := "select " + productColumns + " from products where id = ?"
+
+	return scanProduct(sqlTx(tx).QueryRowContext(ctx, SQL, productId))
+}
+
+func (repository *ProductRepositoryImpl) FindBySku(ctx context.Context, tx Tx, sku string) (domain.Product, error) {
+	SQL := "select " + productColumns + " from products where sku = ?"
+
+	return scanProduct(sqlTx(tx).QueryRowContext(ctx, SQL, sku))
+}
+
+func (repository *ProductRepositoryImpl) FindAll(ctx context.Context, tx Tx, filter domain.ProductFilter) ([]domain.Product, error) {
+	where, args := productWhere(filter)
+	SQL := "select " + productColumns + " from products" + where + " order by id asc"
+
+	if filter.Limit > 0 {
+		SQL += " limit ? offset ?"
+		args = append(args, filter.Limit, filter.Offset)
+	}
+
+	rows, err := sqlTx(tx).QueryContext(ctx, SQL, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var products []domain.Product
+	for rows.Next() {
+		product, err := scanProduct(rows)
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, product)
+	}
+
+	return products, rows.Err()
+}
+
+func (repository *ProductRepositoryImpl) Count(ctx context.Context, tx Tx, filter domain.ProductFilter) (int, error) {
+	where, args := productWhere(filter)
+	SQL := "select count(*) from products" + where
+
+	var total int
+	err := sqlTx(tx).QueryRowContext(ctx, SQL, args...).Scan(&total)
+
+	return total, err
+}
+
+func (repository *ProductRepositoryImpl) CountByCategory(ctx context.Context, tx Tx, categoryId int) (int, error) {
+	return repository.Count(ctx, tx, domain.ProductFilter{CategoryId: categoryId})
+}
+
+func productWhere(filter domain.ProductFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if filter.CategoryId != 0 {
+		conditions = append(conditions, "category_id = ?")
+		args = append(args, filter.CategoryId)
+	}
+
+	if filter.Query != "" {
+		pattern := "%" + likeEscaper.Replace(filter.Query) + "%"
+		conditions = append(conditions, "(name like ? escape '!' or sku like ? escape '!')")
+		args = append(args, pattern, pattern)
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+
+	return " where " + strings.Join(conditions, " and "), args
+}
+
+func scanProduct(row scanner) (domain.Product, error) {
+	product := domain.Product{}
+
+	err := row.Scan(&product.Id, &product.CategoryId, &product.Name, &product.Sku,
+		&product.Price, &product.Stock, &product.CreatedAt, &product.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return product, ErrProductNotFound
+	}
+
+	return product, err
+}
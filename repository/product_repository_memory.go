@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"sudutkampus/gorestfulapi/model/domain"
+)
+
+type ProductRepositoryMemory struct {
+}
+
+func NewProductRepositoryMemory() ProductRepository {
+	return &ProductRepositoryMemory{}
+}
+
+func (repository *ProductRepositoryMemory) Save(ctx context.Context, tx Tx, product domain.Product) (domain.Product, error) {
+	db := memoryTx(tx)
+
+	product.Id = db.NextProductId
+	db.NextProductId++
+	db.Products[product.Id] = product
+
+	return product, nil
+}
+
+func (repository *ProductRepositoryMemory) Update(ctx context.Context, tx Tx, product domain.Product) (domain.Product, error) {
+	memoryTx(tx).Products[product.Id] = product
+
+	return product, nil
+}
+
+func (repository *ProductRepositoryMemory) Delete(ctx context.Context, tx Tx, product domain.Product) error {
+	delete(memoryTx(tx).Products, product.Id)
+
+	return nil
+}
+
+func (repository *ProductRepositoryMemory) FindById(ctx context.Context, tx Tx, productId int) (domain.Product, error) {
+	product, ok := memoryTx(tx).Products[productId]
+	if !ok {
+		return domain.Product{}, ErrProductNotFound
+	}
+
+	return product, nil
+}
+
+func (repository *ProductRepositoryMemory) FindBySku(ctx context.Context, tx Tx, sku string) (domain.Product, error) {
+	for _, product := range memoryTx(tx).Products {
+		if product.Sku == sku {
+			return product, nil
+		}
+	}
+
+	return domain.Product{}, ErrProductNotFound
+}
+
+func (repository *ProductRepositoryMemory) FindAll(ctx context.Context, tx Tx, filter domain.ProductFilter) ([]domain.Product, error) {
+	products := filterProducts(memoryTx(tx), filter)
+
+	sort.Slice(products, func(i, j int) bool {
+		return products[i].Id < products[j].Id
+	})
+
+	if filter.Offset > 0 {
+		if filter.Offset >= len(products) {
+			return nil, nil
+		}
+		products = products[filter.Offset:]
+	}
+
+	if filter.Limit > 0 && len(products) > filter.Limit {
+		products = products[:filter.Limit]
+	}
+
+	return products, nil
+}
+
+func (repository *ProductRepositoryMemory) Count(ctx context.Context, tx Tx, filter domain.ProductFilter) (int, error) {
+	return len(filterProducts(memoryTx(tx), filter)), nil
+}
+
+func (repository *ProductRepositoryMemory) CountByCategory(ctx context.Context, tx Tx, categoryId int) (int, error) {
+	return repository.Count(ctx, tx, domain.ProductFilter{CategoryId: categoryId})
+}
+
+func filterProducts(db *MemoryDB, filter domain.ProductFilter) []domain.Product {
+	query := strings.ToLower(filter.Query)
+
+	var products []domain.Product
+	for _, product := range db.Products {
+		if filter.CategoryId != 0 && product.CategoryId != filter.CategoryId {
+			continue
+		}
+		if query != "" && !strings.Contains(strings.ToLower(product.Name), query) && !strings.Contains(strings.ToLower(product.Sku), query) {
+			continue
+		}
+		products = append(products, product)
+	}
+
+	return products
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"sudutkampus/gorestfulapi/model/domain"
+)
+
+var ErrSessionNotFound = errors.New("session not found")
+
+type SessionRepository interface {
+	Save(ctx context.Context, tx Tx, session domain.Session) (domain.Session, error)
+	Update(ctx context.Context, tx Tx, session domain.Session) (domain.Session, error)
+	FindById(ctx context.Context, tx Tx, sessionId int) (domain.Session, error)
+	FindByAccessTokenHash(ctx context.Context, tx Tx, accessTokenHash string) (domain.Session, error)
+	FindByRefreshTokenHash(ctx context.Context, tx Tx, refreshTokenHash string) (domain.Session, error)
+	// RevokeByUser revokes every active session of a user except keepId.
+	RevokeByUser(ctx context.Context, tx Tx, userId int, keepId int, revokedAt time.Time) error
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"sudutkampus/gorestfulapi/model/domain"
+)
+
+type SessionRepositoryImpl struct {
+}
+
+func NewSessionRepository() SessionRepository {
+	return &SessionRepositoryImpl{}
+}
+
+const sessionColumns = "id, user_id, access_token_hash, refresh_token_hash, access_expires_at, refresh_expires_at, revoked_at, created_at"
+
+func (repository *SessionRepositoryImpl) Save(ctx context.Context, tx Tx, session domain.Session) (domain.Session, error) {
+	SQL := "insert into sessions(user_id, access_token_hash, refresh_token_hash, access_expires_at, refresh_expires_at, created_at) values (?, ?, ?, ?, ?, ?)"
+
+	result, err := sqlTx(tx).ExecContext(ctx, SQL, session.UserId, session.AccessTokenHash, session.RefreshTokenHash,
+		session.AccessExpiresAt, session.RefreshExpiresAt, session.CreatedAt)
+	if err != nil {
+		return session, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return session, err
+	}
+
+	session.Id = int(id)
+
+	return session, nil
+}
+
+func (repository *SessionRepositoryImpl) Update(ctx context.Context, tx Tx, session domain.Session) (domain.Session, error) {
+	SQL := "update sessions set access_token_hash = ?, refresh_token_hash = ?, access_expires_at = ?, refresh_expires_at = ?, revoked_at = ? where id = ?"
+
+	_, err := sqlTx(tx).ExecContext(ctx, SQL, session.AccessTokenHash, session.RefreshTokenHash,
+		session.AccessExpiresAt, session.RefreshExpiresAt, nullTime(session.RevokedAt), session.Id)
+	if err != nil {
+		return session, err
+	}
+
+	return session, nil
+}
+
+func (repository *SessionRepositoryImpl) FindById(ctx context.Context, tx Tx, sessionId int) (domain.Session, error) {
+	SQL := "select " + sessionColumns + " from sessions where id = ?"
+
+	return scanSession(sqlTx(tx).QueryRowContext(ctx, SQL, sessionId))
+}
+
+func (repository *SessionRepositoryImpl) FindByAccessTokenHash(ctx context.Context, tx Tx, accessTokenHash string) (domain.Session, error) {
+	SQL := "select " + sessionColumns + " from sessions where access_token_hash = ?"
+
+	return scanSession(sqlTx(tx).QueryRowContext(ctx, SQL, accessTokenHash))
+}
+
+func (repository *SessionRepositoryImpl) FindByRefreshTokenHash(ctx context.Context, tx Tx, refreshTokenHash string) (domain.Session, error) {
+	SQL := "select " + sessionColumns + " from sessions where refresh_token_hash = ?"
+
+	return scanSession(sqlTx(tx).QueryRowContext(ctx, SQL, refreshTokenHash))
+}
+
+func (repository *SessionRepositoryImpl) RevokeByUser(ctx context.Context, tx Tx, userId int, keepId int, revokedAt time.Time) error {
+	SQL := "update sessions set revoked_at = ? where user_id = ? and id <> ? and revoked_at is null"
+
+	_, err := sqlTx(tx).ExecContext(ctx, SQL, revokedAt, userId, keepId)
+	return err
+}
+
+func scanSession(row scanner) (domain.Session, error) {
+	session := domain.Session{}
+	var revokedAt sql.NullTime
+
+	err := row.Scan(&session.Id, &session.UserId, &session.AccessTokenHash, &session.RefreshTokenHash,
+		&session.AccessExpiresAt, &session.RefreshExpiresAt, &revokedAt, &session.CreatedAt)
+	if err == sql.ErrNoRows {
+		return session, ErrSessionNotFound
+	}
+	if err != nil {
+		return session, err
+	}
+
+	session.RevokedAt = timePointer(revokedAt)
+
+	return session, nil
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"sudutkampus/gorestfulapi/model/domain"
+)
+
+type SessionRepositoryMemory struct {
+}
+
+func NewSessionRepositoryMemory() SessionRepository {
+	return &SessionRepositoryMemory{}
+}
+
+func (repository *SessionRepositoryMemory) Save(ctx context.Context, tx Tx, session domain.Session) (domain.Session, error) {
+	db := memoryTx(tx)
+
+	session.Id = db.NextSessionId
+	db.NextSessionId++
+	db.Sessions[session.Id] = session
+
+	return session, nil
+}
+
+func (repository *SessionRepositoryMemory) Update(ctx context.Context, tx Tx, session domain.Session) (domain.Session, error) {
+	db := memoryTx(tx)
+
+	if stored, ok := db.Sessions[session.Id]; ok {
+		session.UserId = stored.UserId
+		session.CreatedAt = stored.CreatedAt
+		db.Sessions[session.Id] = session
+	}
+
+	return session, nil
+}
+
+func (repository *SessionRepositoryMemory) FindById(ctx context.Context, tx Tx, sessionId int) (domain.Session, error) {
+	session, ok := memoryTx(tx).Sessions[sessionId]
+	if !ok {
+		return domain.Session{}, ErrSessionNotFound
+	}
+
+	return session, nil
+}
+
+func (repository *SessionRepositoryMemory) FindByAccessTokenHash(ctx context.Context, tx Tx, accessTokenHash string) (domain.Session, error) {
+	for _, session := range memoryTx(tx).Sessions {
+		if session.AccessTokenHash == accessTokenHash {
+			return session, nil
+		}
+	}
+
+	return domain.Session{}, ErrSessionNotFound
+}
+
+func (repository *SessionRepositoryMemory) FindByRefreshTokenHash(ctx context.Context, tx Tx, refreshTokenHash string) (domain.Session, error) {
+	for _, session := range memoryTx(tx).Sessions {
+		if session.RefreshTokenHash == refreshTokenHash {
+			return session, nil
+		}
+	}
+
+	return domain.Session{}, ErrSessionNotFound
+}
+
+func (repository *SessionRepositoryMemory) RevokeByUser(ctx context.Context, tx Tx, userId int, keepId int, revokedAt time.Time) error {
+	db := memoryTx(tx)
+
+	for id, session := range db.Sessions {
+		if session.UserId == userId && id != keepId && session.RevokedAt == nil {
+			session.RevokedAt = &revokedAt
+			db.Sessions[id] = session
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+)
+
+// scanner is implemented by both *sql.Row and *sql.Rows.
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// expectAffected returns err when result changed no rows, which for a
+// conditional update means its condition no longer held.
+func expectAffected(result sql.Result, err error) error {
+	affected, errAffected := result.RowsAffected()
+	if errAffected != nil {
+		return errAffected
+	}
+	if affected == 0 {
+		return err
+	}
+
+	return nil
+}
+
+func nullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+
+	return sql.NullTime{Time: *t, Valid: true}
+}
+
+func timePointer(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+
+	value := t.Time
+	return &value
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func nullInt(i *int) sql.NullInt64 {
+	if i == nil {
+		return sql.NullInt64{}
+	}
+
+	return sql.NullInt64{Int64: int64(*i), Valid: true}
+}
+
+func intPointer(i sql.NullInt64) *int {
+	if !i.Valid {
+		return nil
+	}
+
+	value := int(i.Int64)
+	return &value
+}
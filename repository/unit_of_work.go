@@ -0,0 +1,15 @@
+package repository
+
+import "context"
+
+// Tx is the handle of an open unit of work. It is opaque to services; each
+// repository implementation only accepts the handles of its own backend
+// (a transaction of SQLUnitOfWork for SQL, *MemoryDB for the in-memory
+// store).
+type Tx interface{}
+
+type UnitOfWork interface {
+	// Do runs fn in a transaction, committing when fn returns nil and rolling
+	// back otherwise.
+	Do(ctx context.Context, fn func(tx Tx) error) error
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"sudutkampus/gorestfulapi/logging"
+	"sudutkampus/gorestfulapi/model/domain"
+)
+
+// MemoryDB holds the tables of the in-memory backend. It is only touched
+// inside MemoryUnitOfWork.Do, which serializes access to it.
+type MemoryDB struct {
+	Categories     map[int]domain.Category
+	NextCategoryId int
+	ApiKeys        map[int]domain.ApiKey
+	NextApiKeyId   int
+	Users          map[int]domain.User
+	NextUserId     int
+	Sessions       map[int]domain.Session
+	NextSessionId  int
+	Products       map[int]domain.Product
+	NextProductId  int
+	AuditLogs      []domain.AuditLog
+}
+
+func NewMemoryDB() *MemoryDB {
+	return &MemoryDB{
+		Categories:     map[int]domain.Category{},
+		NextCategoryId: 1,
+		ApiKeys:        map[int]domain.ApiKey{},
+		NextApiKeyId:   1,
+		Users:          map[int]domain.User{},
+		NextUserId:     1,
+		Sessions:       map[int]domain.Session{},
+		NextSessionId:  1,
+		Products:       map[int]domain.Product{},
+		NextProductId:  1,
+	}
+}
+
+// clone copies the tables; rows are values and are always replaced as a
+// whole, so a shallow copy of each map is enough.
+func (db *MemoryDB) clone() *MemoryDB {
+	clone := *db
+
+	clone.Categories = make(map[int]domain.Category, len(db.Categories))
+	for id, category := range db.Categories {
+		clone.Categories[id] = category
+	}
+
+	clone.ApiKeys = make(map[int]domain.ApiKey, len(db.ApiKeys))
+	for id, apiKey := range db.ApiKeys {
+		clone.ApiKeys[id] = apiKey
+	}
+
+	clone.Users = make(map[int]domain.User, len(db.Users))
+	for id, user := range db.Users {
+		clone.Users[id] = user
+	}
+
+	clone.Sessions = make(map[int]domain.Session, len(db.Sessions))
+	for id, session := range db.Sessions {
+		clone.Sessions[id] = session
+	}
+
+	clone.Products = make(map[int]domain.Product, len(db.Products))
+	for id, product := range db.Products {
+		clone.Products[id] = product
+	}
+
+	// audit logs are only appended to, capping the capacity makes the
+	// first append copy them instead of writing past the original
+	clone.AuditLogs = db.AuditLogs[:len(db.AuditLogs):len(db.AuditLogs)]
+
+	return &clone
+}
+
+// MemoryUnitOfWork logs every transaction at debug level like
+// SQLUnitOfWork, so both backends leave the same trail.
+type MemoryUnitOfWork struct {
+	mutex  sync.Mutex
+	DB     *MemoryDB
+	Logger *logging.Logger
+}
+
+func NewMemoryUnitOfWork(db *MemoryDB, logger *logging.Logger) UnitOfWork {
+	return &MemoryUnitOfWork{DB: db, Logger: logger}
+}
+
+func (unitOfWork *MemoryUnitOfWork) Do(ctx context.Context, fn func(tx Tx) error) error {
+	unitOfWork.mutex.Lock()
+	defer unitOfWork.mutex.Unlock()
+
+	err := ctx.Err()
+	if err != nil {
+		return err
+	}
+
+	// fn works on a copy which only replaces the tables once it succeeds
+	start := time.Now()
+	tx := unitOfWork.DB.clone()
+
+	err = fn(tx)
+	if err != nil {
+		unitOfWork.Logger.Debug(ctx, "transaction rolled back", "duration", time.Since(start), "error", err)
+		return err
+	}
+
+	*unitOfWork.DB = *tx
+
+	unitOfWork.Logger.Debug(ctx, "transaction committed", "duration", time.Since(start))
+	return nil
+}
+
+func memoryTx(tx Tx) *MemoryDB {
+	return tx.(*MemoryDB)
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"sudutkampus/gorestfulapi/config"
+	"sudutkampus/gorestfulapi/logging"
+	"sudutkampus/gorestfulapi/tracing"
+)
+
+// SQLUnitOfWork logs every transaction at debug level, with how long it
+// took and the error that rolled it back, and failed commits as errors.
+// It records a span for every transaction and every statement run in it.
+type SQLUnitOfWork struct {
+	DB     *sql.DB
+	Driver string
+	Logger *logging.Logger
+	Tracer trace.Tracer
+}
+
+func NewSQLUnitOfWork(db *sql.DB, driver string, logger *logging.Logger, tracer trace.Tracer) UnitOfWork {
+	return &SQLUnitOfWork{DB: db, Driver: driver, Logger: logger, Tracer: tracer}
+}
+
+func (unitOfWork *SQLUnitOfWork) Do(ctx context.Context, fn func(tx Tx) error) (err error) {
+	ctx, span := unitOfWork.Tracer.Start(ctx, "transaction", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(dbSystem(unitOfWork.Driver)))
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	start := time.Now()
+	tx, err := unitOfWork.DB.BeginTx(ctx, nil)
+	if err != nil {
+		unitOfWork.Logger.Error(ctx, "begin transaction failed", "error", err)
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	err = fn(&sqlTransaction{Tx: tx, tracer: unitOfWork.Tracer, system: dbSystem(unitOfWork.Driver)})
+	if err != nil {
+		tx.Rollback()
+		unitOfWork.Logger.Debug(ctx, "transaction rolled back", "duration", time.Since(start), "error", err)
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		unitOfWork.Logger.Error(ctx, "commit failed", "duration", time.Since(start), "error", err)
+		return err
+	}
+
+	unitOfWork.Logger.Debug(ctx, "transaction committed", "duration", time.Since(start))
+	return nil
+}
+
+// sqlTransaction is the Tx handed to the SQL repositories. It records a
+// span for every statement; a query's span ends once it has been sent,
+// not once its rows have been read.
+type sqlTransaction struct {
+	*sql.Tx
+	tracer trace.Tracer
+	system attribute.KeyValue
+}
+
+func (tx *sqlTransaction) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx, span := tx.start(ctx, query)
+	result, err := tx.Tx.ExecContext(ctx, query, args...)
+	tracing.End(span, err)
+
+	return result, err
+}
+
+func (tx *sqlTransaction) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	ctx, span := tx.start(ctx, query)
+	rows, err := tx.Tx.QueryContext(ctx, query, args...)
+	tracing.End(span, err)
+
+	return rows, err
+}
+
+func (tx *sqlTransaction) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	ctx, span := tx.start(ctx, query)
+	row := tx.Tx.QueryRowContext(ctx, query, args...)
+	tracing.End(span, row.Err())
+
+	return row
+}
+
+// start names the span of a statement after its operation, such as
+// select, to keep span names few; the statement itself is an attribute.
+func (tx *sqlTransaction) start(ctx context.Context, query string) (context.Context, trace.Span) {
+	operation := strings.ToLower(strings.Fields(query)[0])
+
+	return tx.tracer.Start(ctx, operation, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		tx.system,
+		semconv.DBOperationKey.String(operation),
+		semconv.DBStatementKey.String(query),
+	))
+}
+
+func dbSystem(driver string) attribute.KeyValue {
+	if driver == config.DriverSQLite {
+		return semconv.DBSystemSqlite
+	}
+
+	return semconv.DBSystemMySQL
+}
+
+func sqlTx(tx Tx) *sqlTransaction {
+	return tx.(*sqlTransaction)
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"sudutkampus/gorestfulapi/model/domain"
+)
+
+var ErrUserNotFound = errors.New("user not found")
+
+type UserRepository interface {
+	Save(ctx context.Context, tx Tx, user domain.User) (domain.User, error)
+	Update(ctx context.Context, tx Tx, user domain.User) (domain.User, error)
+	FindById(ctx context.Context, tx Tx, userId int) (domain.User, error)
+	FindByEmail(ctx context.Context, tx Tx, email string) (domain.User, error)
+	FindAll(ctx context.Context, tx Tx) ([]domain.User, error)
+}
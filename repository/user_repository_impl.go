@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"sudutkampus/gorestfulapi/model/domain"
+)
+
+type UserRepositoryImpl struct {
+}
+
+func NewUserRepository() UserRepository {
+	return &UserRepositoryImpl{}
+}
+
+const userColumns = "id, email, name, password_hash, roles, created_at, updated_at"
+
+func (repository *UserRepositoryImpl) Save(ctx context.Context, tx Tx, user domain.User) (domain.User, error) {
+	SQL := "insert into users(email, name, password_hash, roles, created_at, updated_at) values (?, ?, ?, ?, ?, ?)"
+
+	result, err := sqlTx(tx).ExecContext(ctx, SQL, user.Email, user.Name, user.PasswordHash,
+		strings.Join(user.Roles, ","), user.CreatedAt, user.UpdatedAt)
+	if err != nil {
+		return user, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return user, err
+	}
+
+	user.Id = int(id)
+
+	return user, nil
+}
+
+func (repository *UserRepositoryImpl) Update(ctx context.Context, tx Tx, user domain.User) (domain.User, error) {
+	SQL := "update users set email = ?, name = ?, password_hash = ?, roles = ?, updated_at = ? where id = ?"
+
+	_, err := sqlTx(tx).ExecContext(ctx, SQL, user.Email, user.Name, user.PasswordHash,
+		strings.Join(user.Roles, ","), user.UpdatedAt, user.Id)
+	if err != nil {
+		return user, err
+	}
+
+	return user, nil
+}
+
+func (repository *UserRepositoryImpl) FindById(ctx context.Context, tx Tx, userId int) (domain.User, error) {
+	SQL := "select " + userColumns + " from users where id = ?"
+
+	return scanUser(sqlTx(tx).QueryRowContext(ctx, SQL, userId))
+}
+
+func (repository *UserRepositoryImpl) FindByEmail(ctx context.Context, tx Tx, email string) (domain.User, error) {
+	SQL := "select " + userColumns + " from users where email = ?"
+
+	return scanUser(sqlTx(tx).QueryRowContext(ctx, SQL, email))
+}
+
+func (repository *UserRepositoryImpl) FindAll(ctx context.Context, tx Tx) ([]domain.User, error) {
+	SQL := "select " + userColumns + " from users order by id"
+
+	rows, err := sqlTx(tx).QueryContext(ctx, SQL)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []domain.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
+
+func scanUser(row scanner) (domain.User, error) {
+	user := domain.User{}
+	var roles string
+
+	err := row.Scan(&user.Id, &user.Email, &user.Name, &user.PasswordHash, &roles, &user.CreatedAt, &user.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return user, ErrUserNotFound
+	}
+	if err != nil {
+		return user, err
+	}
+
+	if roles != "" {
+		user.Roles = strings.Split(roles, ",")
+	}
+
+	return user, nil
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"sort"
+
+	"sudutkampus/gorestfulapi/model/domain"
+)
+
+type UserRepositoryMemory struct {
+}
+
+func NewUserRepositoryMemory() UserRepository {
+	return &UserRepositoryMemory{}
+}
+
+func (repository *UserRepositoryMemory) Save(ctx context.Context, tx Tx, user domain.User) (domain.User, error) {
+	db := memoryTx(tx)
+
+	user.Id = db.NextUserId
+	db.NextUserId++
+	db.Users[user.Id] = user
+
+	return user, nil
+}
+
+func (repository *UserRepositoryMemory) Update(ctx context.Context, tx Tx, user domain.User) (domain.User, error) {
+	db := memoryTx(tx)
+
+	if stored, ok := db.Users[user.Id]; ok {
+		user.CreatedAt = stored.CreatedAt
+		db.Users[user.Id] = user
+	}
+
+	return user, nil
+}
+
+func (repository *UserRepositoryMemory) FindById(ctx context.Context, tx Tx, userId int) (domain.User, error) {
+	user, ok := memoryTx(tx).Users[userId]
+	if !ok {
+		return domain.User{}, ErrUserNotFound
+	}
+
+	return user, nil
+}
+
+func (repository *UserRepositoryMemory) FindByEmail(ctx context.Context, tx Tx, email string) (domain.User, error) {
+	for _, user := range memoryTx(tx).Users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+
+	return domain.User{}, ErrUserNotFound
+}
+
+func (repository *UserRepositoryMemory) FindAll(ctx context.Context, tx Tx) ([]domain.User, error) {
+	var users []domain.User
+	for _, user := range memoryTx(tx).Users {
+		users = append(users, user)
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].Id < users[j].Id
+	})
+
+	return users, nil
+}
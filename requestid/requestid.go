@@ -0,0 +1,54 @@
+// Package requestid carries the id of the request being handled, taken from
+// or sent back in the X-Request-ID header.
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+const (
+	Header    = "X-Request-ID"
+	MaxLength = 64
+)
+
+// New returns a random id of 32 hex characters.
+func New() string {
+	id := make([]byte, 16)
+	_, err := rand.Read(id)
+	if err != nil {
+		panic(err)
+	}
+
+	return hex.EncodeToString(id)
+}
+
+// Valid accepts ids a client may choose: up to MaxLength letters, digits,
+// hyphens, underscores and dots, so that they are safe to log.
+func Valid(id string) bool {
+	if id == "" || len(id) > MaxLength {
+		return false
+	}
+
+	for _, c := range id {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.') {
+			return false
+		}
+	}
+
+	return true
+}
+
+type requestIdKey struct{}
+
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIdKey{}, id)
+}
+
+// FromContext returns the request id of ctx, or an empty string outside of
+// a request.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIdKey{}).(string)
+	return id
+}
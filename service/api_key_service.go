@@ -0,0 +1,16 @@
+package service
+
+import (
+	"context"
+
+	"sudutkampus/gorestfulapi/auth"
+	"sudutkampus/gorestfulapi/model/web"
+)
+
+type ApiKeyService interface {
+	Issue(ctx context.Context, request web.ApiKeyCreateRequest) (web.ApiKeyIssueResponse, error)
+	Rotate(ctx context.Context, apiKeyId int) (web.ApiKeyIssueResponse, error)
+	Revoke(ctx context.Context, apiKeyId int) error
+	FindAll(ctx context.Context) ([]web.ApiKeyResponse, error)
+	Authenticate(ctx context.Context, key string) (auth.Principal, error)
+}
@@ -0,0 +1,305 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"sudutkampus/gorestfulapi/auth"
+	"sudutkampus/gorestfulapi/exception"
+	"sudutkampus/gorestfulapi/helper"
+	"sudutkampus/gorestfulapi/logging"
+	"sudutkampus/gorestfulapi/model/domain"
+	"sudutkampus/gorestfulapi/model/web"
+	"sudutkampus/gorestfulapi/repository"
+
+	"github.com/go-playground/validator/v10"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// lastUsedResolution limits how often a successful authentication writes
+// last_used_at back to the store.
+const lastUsedResolution = time.Minute
+
+// verifiedKeyTTL is how long a key whose secret matched its bcrypt hash is
+// trusted without comparing again, and maxVerifiedKeys bounds how many are.
+const (
+	verifiedKeyTTL  = time.Minute
+	maxVerifiedKeys = 1024
+)
+
+type ApiKeyServiceImpl struct {
+	ApiKeyRepository repository.ApiKeyRepository
+	UnitOfWork       repository.UnitOfWork
+	Validate         validator.Validate
+	HashCost         int
+	Logger           *logging.Logger
+	verified         verifiedKeys
+}
+
+// verifiedKeys remembers recent successful bcrypt comparisons by the SHA-256
+// of the plain key, so that a client sending the same key on every request
+// pays for bcrypt about once a minute. An entry only counts while the stored
+// hash is the one it was verified against, so a rotated key is compared
+// again; revocation and expiry are checked on every request regardless.
+type verifiedKeys struct {
+	mutex   sync.Mutex
+	entries map[[sha256.Size]byte]verifiedKey
+}
+
+type verifiedKey struct {
+	keyHash   string
+	expiresAt time.Time
+}
+
+func (keys *verifiedKeys) has(key string, keyHash string, now time.Time) bool {
+	keys.mutex.Lock()
+	defer keys.mutex.Unlock()
+
+	entry, ok := keys.entries[sha256.Sum256([]byte(key))]
+	return ok && entry.keyHash == keyHash && now.Before(entry.expiresAt)
+}
+
+func (keys *verifiedKeys) add(key string, keyHash string, now time.Time) {
+	keys.mutex.Lock()
+	defer keys.mutex.Unlock()
+
+	if keys.entries == nil {
+		keys.entries = map[[sha256.Size]byte]verifiedKey{}
+	}
+
+	if len(keys.entries) >= maxVerifiedKeys {
+		for sum, entry := range keys.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(keys.entries, sum)
+			}
+		}
+		if len(keys.entries) >= maxVerifiedKeys {
+			return
+		}
+	}
+
+	keys.entries[sha256.Sum256([]byte(key))] = verifiedKey{keyHash: keyHash, expiresAt: now.Add(verifiedKeyTTL)}
+}
+
+func NewApiKeyService(apiKeyRepository repository.ApiKeyRepository, unitOfWork repository.UnitOfWork, validate *validator.Validate, hashCost int, logger *logging.Logger) ApiKeyService {
+	return &ApiKeyServiceImpl{
+		ApiKeyRepository: apiKeyRepository,
+		UnitOfWork:       unitOfWork,
+		Validate:         *validate,
+		HashCost:         hashCost,
+		Logger:           logger,
+	}
+}
+
+func (service *ApiKeyServiceImpl) Issue(ctx context.Context, request web.ApiKeyCreateRequest) (web.ApiKeyIssueResponse, error) {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.ApiKeyIssueResponse{}, exception.FromValidator(err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	if request.ExpiresAt != nil && !request.ExpiresAt.After(now) {
+		return web.ApiKeyIssueResponse{}, exception.NewFieldValidationError("expires_at", "expires_at must be in the future")
+	}
+
+	key, prefix, keyHash, err := service.generateKey()
+	if err != nil {
+		return web.ApiKeyIssueResponse{}, err
+	}
+
+	apiKey := domain.ApiKey{
+		Name:      request.Name,
+		Prefix:    prefix,
+		KeyHash:   keyHash,
+		Scopes:    request.Scopes,
+		ExpiresAt: request.ExpiresAt,
+		CreatedAt: now,
+	}
+
+	err = service.UnitOfWork.Do(ctx, func(tx repository.Tx) error {
+		apiKey, err = service.ApiKeyRepository.Save(ctx, tx, apiKey)
+		return err
+	})
+	if err != nil {
+		return web.ApiKeyIssueResponse{}, err
+	}
+
+	service.Logger.Info(ctx, "issued api key", "api_key_id", apiKey.Id, "prefix", apiKey.Prefix, "scopes", apiKey.Scopes)
+
+	return web.ApiKeyIssueResponse{ApiKeyResponse: helper.ToApiKeyResponse(apiKey), Key: key}, nil
+}
+
+func (service *ApiKeyServiceImpl) Rotate(ctx context.Context, apiKeyId int) (web.ApiKeyIssueResponse, error) {
+	key, prefix, keyHash, err := service.generateKey()
+	if err != nil {
+		return web.ApiKeyIssueResponse{}, err
+	}
+
+	var apiKey domain.ApiKey
+	err = service.UnitOfWork.Do(ctx, func(tx repository.Tx) error {
+		apiKey, err = service.findApiKey(ctx, tx, apiKeyId)
+		if err != nil {
+			return err
+		}
+
+		if apiKey.RevokedAt != nil {
+			return exception.NewConflictError("api key is revoked")
+		}
+
+		apiKey.Prefix = prefix
+		apiKey.KeyHash = keyHash
+
+		apiKey, err = service.ApiKeyRepository.Update(ctx, tx, apiKey)
+		return err
+	})
+	if err != nil {
+		return web.ApiKeyIssueResponse{}, err
+	}
+
+	service.Logger.Info(ctx, "rotated api key", "api_key_id", apiKey.Id, "prefix", apiKey.Prefix)
+
+	return web.ApiKeyIssueResponse{ApiKeyResponse: helper.ToApiKeyResponse(apiKey), Key: key}, nil
+}
+
+func (service *ApiKeyServiceImpl) Revoke(ctx context.Context, apiKeyId int) error {
+	revoked := false
+	err := service.UnitOfWork.Do(ctx, func(tx repository.Tx) error {
+		apiKey, err := service.findApiKey(ctx, tx, apiKeyId)
+		if err != nil {
+			return err
+		}
+
+		if apiKey.RevokedAt != nil {
+			return nil
+		}
+
+		now := time.Now().UTC().Truncate(time.Second)
+		apiKey.RevokedAt = &now
+
+		_, err = service.ApiKeyRepository.Update(ctx, tx, apiKey)
+		revoked = err == nil
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if revoked {
+		service.Logger.Info(ctx, "revoked api key", "api_key_id", apiKeyId)
+	}
+	return nil
+}
+
+func (service *ApiKeyServiceImpl) FindAll(ctx context.Context) ([]web.ApiKeyResponse, error) {
+	var apiKeys []domain.ApiKey
+	err := service.UnitOfWork.Do(ctx, func(tx repository.Tx) (err error) {
+		apiKeys, err = service.ApiKeyRepository.FindAll(ctx, tx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return helper.ToApiKeyResponses(apiKeys), nil
+}
+
+func (service *ApiKeyServiceImpl) Authenticate(ctx context.Context, key string) (auth.Principal, error) {
+	invalid := exception.NewUnauthorizedError("invalid api key")
+
+	parts := strings.SplitN(key, ".", 2)
+	if len(parts) != 2 {
+		return auth.Principal{}, invalid
+	}
+
+	var apiKey domain.ApiKey
+	err := service.UnitOfWork.Do(ctx, func(tx repository.Tx) (err error) {
+		apiKey, err = service.ApiKeyRepository.FindByPrefix(ctx, tx, parts[0])
+		return err
+	})
+	if errors.Is(err, repository.ErrApiKeyNotFound) {
+		return auth.Principal{}, invalid
+	}
+	if err != nil {
+		return auth.Principal{}, err
+	}
+
+	// the hash is compared outside the transaction, it is deliberately slow
+	now := time.Now().UTC()
+	if !service.verified.has(key, apiKey.KeyHash, now) {
+		err = bcrypt.CompareHashAndPassword([]byte(apiKey.KeyHash), []byte(parts[1]))
+		if err != nil {
+			return auth.Principal{}, invalid
+		}
+		service.verified.add(key, apiKey.KeyHash, now)
+	}
+
+	if apiKey.RevokedAt != nil {
+		return auth.Principal{}, exception.NewUnauthorizedError("api key is revoked")
+	}
+	if apiKey.ExpiresAt != nil && now.After(*apiKey.ExpiresAt) {
+		return auth.Principal{}, exception.NewUnauthorizedError("api key is expired")
+	}
+
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= lastUsedResolution {
+		err = service.UnitOfWork.Do(ctx, func(tx repository.Tx) error {
+			return service.ApiKeyRepository.TouchLastUsed(ctx, tx, apiKey.Id, now.Truncate(time.Second))
+		})
+		if err != nil {
+			return auth.Principal{}, err
+		}
+	}
+
+	return auth.Principal{
+		Type:   auth.PrincipalApiKey,
+		Id:     strconv.Itoa(apiKey.Id),
+		Name:   apiKey.Name,
+		Scopes: apiKey.Scopes,
+	}, nil
+}
+
+// generateKey returns a new plain key of the form <prefix>.<secret>, its
+// prefix used for lookups and the bcrypt hash of its secret.
+func (service *ApiKeyServiceImpl) generateKey() (string, string, string, error) {
+	prefix, err := randomHex(6)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	secret, err := randomHex(32)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	keyHash, err := bcrypt.GenerateFromPassword([]byte(secret), service.HashCost)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	return prefix + "." + secret, prefix, string(keyHash), nil
+}
+
+func (service *ApiKeyServiceImpl) findApiKey(ctx context.Context, tx repository.Tx, apiKeyId int) (domain.ApiKey, error) {
+	apiKey, err := service.ApiKeyRepository.FindById(ctx, tx, apiKeyId)
+	if errors.Is(err, repository.ErrApiKeyNotFound) {
+		return apiKey, exception.NewNotFoundError(err.Error())
+	}
+
+	return apiKey, err
+}
+
+func randomHex(size int) (string, error) {
+	buffer := make([]byte, size)
+	_, err := rand.Read(buffer)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buffer), nil
+}
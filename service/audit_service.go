@@ -0,0 +1,11 @@
+package service
+
+import (
+	"context"
+
+	"sudutkampus/gorestfulapi/model/web"
+)
+
+type AuditService interface {
+	FindAll(ctx context.Context, request web.AuditLogFindAllRequest) ([]web.AuditLogResponse, web.PageMeta, error)
+}
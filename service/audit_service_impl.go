@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"sudutkampus/gorestfulapi/auth"
+	"sudutkampus/gorestfulapi/exception"
+	"sudutkampus/gorestfulapi/helper"
+	"sudutkampus/gorestfulapi/logging"
+	"sudutkampus/gorestfulapi/model/domain"
+	"sudutkampus/gorestfulapi/model/web"
+	"sudutkampus/gorestfulapi/repository"
+	"sudutkampus/gorestfulapi/requestid"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type AuditServiceImpl struct {
+	AuditLogRepository repository.AuditLogRepository
+	UnitOfWork         repository.UnitOfWork
+	Validate           validator.Validate
+	Logger             *logging.Logger
+}
+
+func NewAuditService(auditLogRepository repository.AuditLogRepository, unitOfWork repository.UnitOfWork, validate *validator.Validate, logger *logging.Logger) AuditService {
+	return &AuditServiceImpl{
+		AuditLogRepository: auditLogRepository,
+		UnitOfWork:         unitOfWork,
+		Validate:           *validate,
+		Logger:             logger,
+	}
+}
+
+func (service *AuditServiceImpl) FindAll(ctx context.Context, request web.AuditLogFindAllRequest) ([]web.AuditLogResponse, web.PageMeta, error) {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return nil, web.PageMeta{}, exception.FromValidator(err)
+	}
+
+	if request.Page == 0 {
+		request.Page = 1
+	}
+	if request.PerPage == 0 {
+		request.PerPage = DefaultPerPage
+	}
+
+	filter := domain.AuditLogFilter{
+		Entity:   request.Entity,
+		EntityId: request.Id,
+		Actor:    request.Actor,
+		Action:   request.Action,
+		Since:    request.Since,
+		Until:    request.Until,
+		Limit:    request.PerPage,
+		Offset:   (request.Page - 1) * request.PerPage,
+	}
+
+	var auditLogs []domain.AuditLog
+	var total int
+	err = service.UnitOfWork.Do(ctx, func(tx repository.Tx) (err error) {
+		auditLogs, err = service.AuditLogRepository.FindAll(ctx, tx, filter)
+		if err != nil {
+			return err
+		}
+
+		total, err = service.AuditLogRepository.Count(ctx, tx, filter)
+		return err
+	})
+	if err != nil {
+		return nil, web.PageMeta{}, err
+	}
+
+	// reading the audit trail is itself worth a trace of who did it
+	service.Logger.Debug(ctx, "listed audit logs", "actor", auditActor(ctx), "entity", request.Entity, "entity_id", request.Id)
+
+	meta := web.PageMeta{
+		Total:   total,
+		Page:    request.Page,
+		PerPage: request.PerPage,
+	}
+
+	return helper.ToAuditLogResponses(auditLogs), meta, nil
+}
+
+// newAuditLog starts an entry for a change made on behalf of the principal
+// and request of ctx.
+func newAuditLog(ctx context.Context, entity string, action string) domain.AuditLog {
+	return domain.AuditLog{
+		Actor:     auditActor(ctx),
+		Action:    action,
+		Entity:    entity,
+		RequestId: requestid.FromContext(ctx),
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}
+}
+
+// auditActor names a principal by its type and id, such as user:12 or
+// api_key:3, and the bootstrap key by its type alone.
+func auditActor(ctx context.Context) string {
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return domain.AuditActorSystem
+	}
+
+	if principal.Id == "" {
+		return principal.Type
+	}
+
+	return principal.Type + ":" + principal.Id
+}
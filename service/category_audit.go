@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+
+	"sudutkampus/gorestfulapi/helper"
+	"sudutkampus/gorestfulapi/model/domain"
+	"sudutkampus/gorestfulapi/repository"
+)
+
+// audit records a change to a category in tx, so that the entry is only
+// kept if the change is committed. before is nil for a created category and
+// after for a purged one.
+func (service *CategoryServiceImpl) audit(ctx context.Context, tx repository.Tx, action string, before *domain.Category, after *domain.Category) error {
+	auditLog := newAuditLog(ctx, domain.AuditEntityCategory, action)
+
+	var err error
+	if before != nil {
+		auditLog.EntityId = before.Id
+		auditLog.Before, err = categorySnapshot(*before)
+		if err != nil {
+			return err
+		}
+	}
+
+	if after != nil {
+		auditLog.EntityId = after.Id
+		auditLog.After, err = categorySnapshot(*after)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = service.AuditLogRepository.Save(ctx, tx, auditLog)
+	return err
+}
+
+func categorySnapshot(category domain.Category) (string, error) {
+	snapshot, err := json.Marshal(helper.ToCategoryResponse(category))
+	return string(snapshot), err
+}
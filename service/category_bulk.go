@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"sudutkampus/gorestfulapi/exception"
+	"sudutkampus/gorestfulapi/helper"
+	"sudutkampus/gorestfulapi/model/domain"
+	"sudutkampus/gorestfulapi/model/web"
+	"sudutkampus/gorestfulapi/repository"
+)
+
+// errBulkFailed rolls back an atomic bulk request once one of its
+// operations has failed; the failure itself is recorded in its result.
+var errBulkFailed = errors.New("bulk operation failed")
+
+// Bulk runs the operations in order and reports each of them in a result at
+// the same index. Consecutive creates are inserted together. In atomic mode
+// the first failure rolls back the whole request and every other result
+// reports 424; in partial mode each create run and each other operation
+// commits on its own.
+func (service *CategoryServiceImpl) Bulk(ctx context.Context, request web.CategoryBulkRequest) ([]web.CategoryBulkResult, error) {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return nil, exception.FromValidator(err)
+	}
+
+	results := make([]web.CategoryBulkResult, len(request.Operations))
+	for i, operation := range request.Operations {
+		results[i] = web.CategoryBulkResult{Index: i, Op: operation.Op}
+	}
+
+	atomic := request.Mode != web.BulkModePartial
+	batches := bulkBatches(request.Operations)
+
+	if !atomic {
+		for _, batch := range batches {
+			err = service.UnitOfWork.Do(ctx, func(tx repository.Tx) error {
+				return service.runBulkBatch(ctx, tx, request, batch, results, false)
+			})
+			if err != nil {
+				failBulkBatch(results, batch, err)
+			}
+		}
+
+		return results, nil
+	}
+
+	var failedBatch []int
+	err = service.UnitOfWork.Do(ctx, func(tx repository.Tx) error {
+		for _, batch := range batches {
+			err := service.runBulkBatch(ctx, tx, request, batch, results, true)
+			if err != nil {
+				failedBatch = batch
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		rollBackBulk(results, failedBatch, err)
+	}
+
+	return results, nil
+}
+
+// bulkBatches groups the indexes of consecutive creates so that they can be
+// inserted together; every other operation is a batch of its own.
+func bulkBatches(operations []web.CategoryBulkOperation) [][]int {
+	var batches [][]int
+	for i, operation := range operations {
+		last := len(batches) - 1
+		if operation.Op == web.BulkOpCreate && last >= 0 && operations[batches[last][0]].Op == web.BulkOpCreate {
+			batches[last] = append(batches[last], i)
+			continue
+		}
+		batches = append(batches, []int{i})
+	}
+
+	return batches
+}
+
+// runBulkBatch records the outcome of every operation of batch in results
+// and returns an error when tx has to be rolled back. With stopOnError an
+// invalid create stops the batch before anything is inserted.
+func (service *CategoryServiceImpl) runBulkBatch(ctx context.Context, tx repository.Tx, request web.CategoryBulkRequest, batch []int, results []web.CategoryBulkResult, stopOnError bool) error {
+	operation := request.Operations[batch[0]]
+	if operation.Op != web.BulkOpCreate {
+		result := &results[batch[0]]
+		result.Data, result.Err = service.runBulkOperation(ctx, tx, request, operation)
+		return result.Err
+	}
+
+	var indexes []int
+	var categories []domain.Category
+	names := map[string]bool{}
+	slugs := map[string]bool{}
+	for _, i := range batch {
+		createRequest := web.CategoryCreateRequest{
+			Name:     request.Operations[i].Name,
+			ParentId: request.Operations[i].ParentId,
+		}
+
+		category, err := service.newCategory(ctx, tx, createRequest, names, slugs)
+		if err != nil {
+			results[i].Err = err
+			if stopOnError {
+				return errBulkFailed
+			}
+			continue
+		}
+
+		indexes = append(indexes, i)
+		categories = append(categories, category)
+	}
+
+	if len(categories) == 0 {
+		return nil
+	}
+
+	categories, err := service.CategoryRepository.SaveAll(ctx, tx, categories)
+	if err != nil {
+		return writeConflict(err)
+	}
+
+	for j, i := range indexes {
+		err = service.audit(ctx, tx, domain.AuditActionCreate, nil, &categories[j])
+		if err != nil {
+			return err
+		}
+
+		response := helper.ToCategoryResponse(categories[j])
+		results[i].Data = &response
+	}
+
+	return nil
+}
+
+// newCategory validates a category to insert and gives it a slug. When
+// several are inserted together, names and slugs collect the lowercased
+// names and the slugs of those before it.
+func (service *CategoryServiceImpl) newCategory(ctx context.Context, tx repository.Tx, request web.CategoryCreateRequest, names map[string]bool, slugs map[string]bool) (domain.Category, error) {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return domain.Category{}, exception.FromValidator(err)
+	}
+
+	err = service.checkParent(ctx, tx, request.ParentId)
+	if err != nil {
+		return domain.Category{}, err
+	}
+
+	name := strings.ToLower(request.Name)
+	if names[name] {
+		return domain.Category{}, exception.NewConflictError("category name already exists")
+	}
+
+	err = service.checkName(ctx, tx, request.Name, 0)
+	if err != nil {
+		return domain.Category{}, err
+	}
+
+	slug, err := service.slug(ctx, tx, request.Name, slugs)
+	if err != nil {
+		return domain.Category{}, err
+	}
+
+	names[name] = true
+	slugs[slug] = true
+
+	return domain.Category{Name: request.Name, Slug: slug, ParentId: request.ParentId}, nil
+}
+
+func (service *CategoryServiceImpl) runBulkOperation(ctx context.Context, tx repository.Tx, request web.CategoryBulkRequest, operation web.CategoryBulkOperation) (*web.CategoryResponse, error) {
+	if request.RequireVersion && operation.Version == 0 {
+		return nil, exception.NewPreconditionRequiredError("version is required")
+	}
+
+	if operation.Op == web.BulkOpDelete {
+		if operation.Id == 0 {
+			return nil, exception.NewFieldValidationError("id", "id is required")
+		}
+		return nil, service.delete(ctx, tx, operation.Id, operation.Version)
+	}
+
+	updateRequest := web.CategoryUpdateRequest{
+		Id:      operation.Id,
+		Name:    operation.Name,
+		Version: operation.Version,
+	}
+
+	err := service.Validate.Struct(updateRequest)
+	if err != nil {
+		return nil, exception.FromValidator(err)
+	}
+
+	category, err := service.update(ctx, tx, updateRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	response := helper.ToCategoryResponse(category)
+	return &response, nil
+}
+
+// failBulkBatch reports err for the operations of batch that were rolled
+// back with it.
+func failBulkBatch(results []web.CategoryBulkResult, batch []int, err error) {
+	for _, i := range batch {
+		if results[i].Err == nil {
+			results[i].Data = nil
+			results[i].Err = err
+		}
+	}
+}
+
+// rollBackBulk reports every operation of a failed atomic request other
+// than the one that failed as a failed dependency. A failure not tied to
+// one operation, such as a failed multi-row insert, is reported for each
+// operation of its batch, and a failed commit for every operation.
+func rollBackBulk(results []web.CategoryBulkResult, failedBatch []int, err error) {
+	if failedBatch == nil {
+		for i := range results {
+			results[i].Data = nil
+			results[i].Err = err
+		}
+		return
+	}
+
+	if !errors.Is(err, errBulkFailed) {
+		failBulkBatch(results, failedBatch, err)
+	}
+
+	failed := 0
+	for i := range results {
+		if results[i].Err != nil {
+			failed = i
+			break
+		}
+	}
+
+	for i := range results {
+		switch {
+		case results[i].Err != nil:
+		case i < failed:
+			results[i].Err = exception.NewFailedDependencyError(fmt.Sprintf("rolled back because operation %d failed", failed))
+		default:
+			results[i].Err = exception.NewFailedDependencyError(fmt.Sprintf("not executed because operation %d failed", failed))
+		}
+		results[i].Data = nil
+	}
+}
@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"sudutkampus/gorestfulapi/config"
+	"sudutkampus/gorestfulapi/exception"
+	"sudutkampus/gorestfulapi/helper"
+	"sudutkampus/gorestfulapi/model/domain"
+	"sudutkampus/gorestfulapi/model/web"
+	"sudutkampus/gorestfulapi/repository"
+)
+
+// Move puts a category and its subtree under another parent. Moving it
+// under itself or one of its descendants would create a cycle and is
+// rejected with 409 Conflict.
+func (service *CategoryServiceImpl) Move(ctx context.Context, request web.CategoryMoveRequest) (web.CategoryResponse, error) {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.CategoryResponse{}, exception.FromValidator(err)
+	}
+
+	var category domain.Category
+	err = service.UnitOfWork.Do(ctx, func(tx repository.Tx) (err error) {
+		category, err = service.findCategory(ctx, tx, request.Id)
+		if err != nil {
+			return err
+		}
+
+		err = checkVersion(category, request.Version)
+		if err != nil {
+			return err
+		}
+
+		if request.ParentId != nil {
+			parent, err := service.CategoryRepository.FindById(ctx, tx, *request.ParentId)
+			if errors.Is(err, repository.ErrCategoryNotFound) {
+				return exception.NewFieldValidationError("parent_id", "parent category not found")
+			}
+			if err != nil {
+				return err
+			}
+
+			ancestors, err := service.ancestors(ctx, tx, parent)
+			if err != nil {
+				return err
+			}
+
+			for _, ancestor := range append(ancestors, parent) {
+				if ancestor.Id == category.Id {
+					return exception.NewConflictError("category cannot be moved under itself or its descendants")
+				}
+			}
+		}
+
+		before := category
+		category.ParentId = request.ParentId
+
+		category, err = service.CategoryRepository.Update(ctx, tx, category)
+		if err != nil {
+			return writeConflict(err)
+		}
+
+		return service.audit(ctx, tx, domain.AuditActionMove, &before, &category)
+	})
+	if err != nil {
+		return web.CategoryResponse{}, err
+	}
+
+	return helper.ToCategoryResponse(category), nil
+}
+
+func (service *CategoryServiceImpl) FindChildren(ctx context.Context, categoryId int) ([]web.CategoryResponse, error) {
+	var children []domain.Category
+	err := service.UnitOfWork.Do(ctx, func(tx repository.Tx) error {
+		_, err := service.findCategory(ctx, tx, categoryId)
+		if err != nil {
+			return err
+		}
+
+		children, err = service.CategoryRepository.FindChildren(ctx, tx, categoryId)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return helper.ToCategoryResponses(children), nil
+}
+
+// FindAncestors returns the path from the root down to the parent of a
+// category, as shown in a breadcrumb.
+func (service *CategoryServiceImpl) FindAncestors(ctx context.Context, categoryId int) ([]web.CategoryResponse, error) {
+	var ancestors []domain.Category
+	err := service.UnitOfWork.Do(ctx, func(tx repository.Tx) error {
+		category, err := service.findCategory(ctx, tx, categoryId)
+		if err != nil {
+			return err
+		}
+
+		ancestors, err = service.ancestors(ctx, tx, category)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return helper.ToCategoryResponses(ancestors), nil
+}
+
+// FindTree returns every live category nested under its parent, with the
+// roots and each list of children ordered by id.
+func (service *CategoryServiceImpl) FindTree(ctx context.Context) ([]web.CategoryTreeResponse, error) {
+	var categories []domain.Category
+	err := service.UnitOfWork.Do(ctx, func(tx repository.Tx) (err error) {
+		categories, err = service.CategoryRepository.FindAll(ctx, tx, domain.CategoryFilter{})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	live := make(map[int]bool, len(categories))
+	for _, category := range categories {
+		live[category.Id] = true
+	}
+
+	children := make(map[int][]domain.Category)
+	var roots []domain.Category
+	for _, category := range categories {
+		if category.ParentId == nil || !live[*category.ParentId] {
+			roots = append(roots, category)
+			continue
+		}
+		children[*category.ParentId] = append(children[*category.ParentId], category)
+	}
+
+	return toCategoryTree(roots, children), nil
+}
+
+func toCategoryTree(categories []domain.Category, children map[int][]domain.Category) []web.CategoryTreeResponse {
+	tree := make([]web.CategoryTreeResponse, len(categories))
+	for i, category := range categories {
+		tree[i] = web.CategoryTreeResponse{
+			Id:       category.Id,
+			Name:     category.Name,
+			Slug:     category.Slug,
+			Version:  category.Version,
+			Children: toCategoryTree(children[category.Id], children),
+		}
+	}
+
+	return tree
+}
+
+// checkParent makes sure a new parent is a live category.
+func (service *CategoryServiceImpl) checkParent(ctx context.Context, tx repository.Tx, parentId *int) error {
+	if parentId == nil {
+		return nil
+	}
+
+	_, err := service.CategoryRepository.FindById(ctx, tx, *parentId)
+	if errors.Is(err, repository.ErrCategoryNotFound) {
+		return exception.NewFieldValidationError("parent_id", "parent category not found")
+	}
+
+	return err
+}
+
+// ancestors walks up from category to its root and returns the categories
+// on the way, root first.
+func (service *CategoryServiceImpl) ancestors(ctx context.Context, tx repository.Tx, category domain.Category) ([]domain.Category, error) {
+	var ancestors []domain.Category
+	seen := map[int]bool{category.Id: true}
+
+	for category.ParentId != nil {
+		if seen[*category.ParentId] {
+			return nil, errors.New("category hierarchy has a cycle")
+		}
+		seen[*category.ParentId] = true
+
+		parent, err := service.CategoryRepository.FindById(ctx, tx, *category.ParentId)
+		if errors.Is(err, repository.ErrCategoryNotFound) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		ancestors = append([]domain.Category{parent}, ancestors...)
+		category = parent
+	}
+
+	return ancestors, nil
+}
+
+// releaseChildren deals with the children of a category about to be
+// deleted or purged according to OnDelete. Cascading applies remove to
+// every descendant, deepest first.
+func (service *CategoryServiceImpl) releaseChildren(ctx context.Context, tx repository.Tx, category domain.Category, remove func(category domain.Category) error) error {
+	children, err := service.CategoryRepository.FindChildren(ctx, tx, category.Id)
+	if err != nil || len(children) == 0 {
+		return err
+	}
+
+	switch service.OnDelete {
+	case config.OnDeleteReparent:
+		err = service.CategoryRepository.Reparent(ctx, tx, category.Id, category.ParentId)
+		if err != nil {
+			return err
+		}
+
+		for _, child := range children {
+			moved := child
+			moved.ParentId = category.ParentId
+			moved.Version++
+
+			err = service.audit(ctx, tx, domain.AuditActionMove, &child, &moved)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	case config.OnDeleteCascade:
+		for _, child := range children {
+			err = service.releaseChildren(ctx, tx, child, remove)
+			if err != nil {
+				return err
+			}
+
+			err = remove(child)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return exception.NewConflictError("category has children")
+	}
+}
@@ -0,0 +1,344 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"sudutkampus/gorestfulapi/exception"
+	"sudutkampus/gorestfulapi/helper"
+	"sudutkampus/gorestfulapi/model/domain"
+	"sudutkampus/gorestfulapi/model/web"
+	"sudutkampus/gorestfulapi/repository"
+)
+
+// maxImportLineSize bounds a single JSON Lines row.
+const maxImportLineSize = 1 << 20
+
+// ExportPageSize is how many categories Export reads at a time.
+const ExportPageSize = 500
+
+// categoryImportRow is one row of an import file. Id and ParentId are the
+// ids used in the file, such as those of an export, not database ids.
+type categoryImportRow struct {
+	Id       int
+	Name     string
+	ParentId *int
+}
+
+// Export calls fn for every live category in the requested order without
+// loading them all at once. It reads ExportPageSize categories per unit of
+// work, continuing after the last one read, and calls fn once each unit of
+// work is done, so a slow client holds no transaction. A category changed
+// between pages is exported as it is when its page is read. The request is
+// validated before fn is first called, so a failure after that comes from
+// fn or the database.
+func (service *CategoryServiceImpl) Export(ctx context.Context, request web.CategoryExportRequest, fn func(category web.CategoryResponse) error) error {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return exception.FromValidator(err)
+	}
+
+	filter := domain.CategoryFilter{
+		Name:  request.Q,
+		Sort:  request.Sort,
+		Limit: ExportPageSize,
+	}
+
+	for {
+		var categories []domain.Category
+		err = service.UnitOfWork.Do(ctx, func(tx repository.Tx) error {
+			categories, err = service.CategoryRepository.FindAll(ctx, tx, filter)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, category := range categories {
+			err = fn(helper.ToCategoryResponse(category))
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(categories) < ExportPageSize {
+			return nil
+		}
+
+		last := categories[len(categories)-1]
+		filter.After = &domain.CategoryCursor{Id: last.Id, Name: last.Name}
+	}
+}
+
+// Import validates every row with the rules of Create, names also having to
+// be unique within the file, and, unless there are row errors or it is a
+// dry run, inserts them all in one transaction. Ids, slugs and versions are
+// assigned anew. A parent_id naming the id of another row in the file makes
+// the category a child of the one created for that row, so an export can be
+// imported without flattening the tree; any other parent_id must be an
+// existing category.
+func (service *CategoryServiceImpl) Import(ctx context.Context, request web.CategoryImportRequest) (web.CategoryImportResponse, error) {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.CategoryImportResponse{}, exception.FromValidator(err)
+	}
+
+	response := web.CategoryImportResponse{DryRun: request.DryRun}
+	var rows []int
+	var importRows []categoryImportRow
+
+	readRow := func(importRow categoryImportRow, err error) {
+		response.Total++
+		if err != nil {
+			response.Errors = append(response.Errors, web.CategoryImportRowError{Row: response.Total, Err: err})
+			return
+		}
+
+		rows = append(rows, response.Total)
+		importRows = append(importRows, importRow)
+	}
+
+	if request.Format == web.FormatJSONL {
+		err = readJSONLines(request.File, readRow)
+	} else {
+		err = readCSV(request.File, readRow)
+	}
+	if err != nil {
+		return web.CategoryImportResponse{}, err
+	}
+
+	fileRows := map[int]int{}
+	duplicates := map[int]bool{}
+	for i, importRow := range importRows {
+		if importRow.Id == 0 {
+			continue
+		}
+		if _, ok := fileRows[importRow.Id]; ok {
+			duplicates[i] = true
+			continue
+		}
+		fileRows[importRow.Id] = i
+	}
+
+	err = service.UnitOfWork.Do(ctx, func(tx repository.Tx) error {
+		categories := make([]domain.Category, len(importRows))
+		var levels [][]int
+		names := map[string]bool{}
+		slugs := map[string]bool{}
+		for i, importRow := range importRows {
+			depth, err := importDepth(importRows, fileRows, i)
+			if err == nil && duplicates[i] {
+				err = exception.NewFieldValidationError("id", "id appears more than once in the file")
+			}
+			if err == nil {
+				createRequest := web.CategoryCreateRequest{Name: importRow.Name}
+				if _, ok := fileRows[intValue(importRow.ParentId)]; !ok {
+					createRequest.ParentId = importRow.ParentId
+				}
+				categories[i], err = service.newCategory(ctx, tx, createRequest, names, slugs)
+			}
+			if err != nil {
+				response.Errors = append(response.Errors, web.CategoryImportRowError{Row: rows[i], Err: err})
+				continue
+			}
+
+			for len(levels) <= depth {
+				levels = append(levels, nil)
+			}
+			levels[depth] = append(levels[depth], i)
+		}
+
+		if len(response.Errors) > 0 || request.DryRun || len(levels) == 0 {
+			return nil
+		}
+
+		// Parents are saved a level before their children, whose parent
+		// ids can then be set to the ids the parents were given.
+		savedIds := map[int]int{}
+		for _, level := range levels {
+			batch := make([]domain.Category, len(level))
+			for j, i := range level {
+				batch[j] = categories[i]
+				if id, ok := savedIds[intValue(importRows[i].ParentId)]; ok {
+					batch[j].ParentId = &id
+				}
+			}
+
+			batch, err := service.CategoryRepository.SaveAll(ctx, tx, batch)
+			if err != nil {
+				return writeConflict(err)
+			}
+
+			for j, i := range level {
+				if importRows[i].Id != 0 {
+					savedIds[importRows[i].Id] = batch[j].Id
+				}
+
+				err = service.audit(ctx, tx, domain.AuditActionCreate, nil, &batch[j])
+				if err != nil {
+					return err
+				}
+			}
+
+			response.Imported += len(batch)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return web.CategoryImportResponse{}, err
+	}
+
+	sort.Slice(response.Errors, func(i, j int) bool {
+		return response.Errors[i].Row < response.Errors[j].Row
+	})
+	response.Failed = len(response.Errors)
+
+	service.Logger.Info(ctx, "imported categories", "format", request.Format, "dry_run", request.DryRun,
+		"total", response.Total, "imported", response.Imported, "failed", response.Failed)
+	return response, nil
+}
+
+// importDepth counts the parents of row i that are themselves rows of the
+// file, failing if they form a cycle.
+func importDepth(importRows []categoryImportRow, fileRows map[int]int, i int) (int, error) {
+	depth := 0
+	seen := map[int]bool{i: true}
+	for {
+		parent, ok := fileRows[intValue(importRows[i].ParentId)]
+		if !ok {
+			return depth, nil
+		}
+		if seen[parent] {
+			return 0, exception.NewFieldValidationError("parent_id", "parent categories form a cycle")
+		}
+
+		seen[parent] = true
+		i = parent
+		depth++
+	}
+}
+
+// intValue returns the value of id, or 0, which no row id uses, if it is nil.
+func intValue(id *int) int {
+	if id == nil {
+		return 0
+	}
+	return *id
+}
+
+// readCSV reads rows by the columns named in the header, which must have a
+// name column and may have id and parent_id columns; other columns, such as
+// the slug and version of an export, are ignored.
+func readCSV(file io.Reader, readRow func(row categoryImportRow, err error)) error {
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return exception.NewFieldValidationError("file", "file is empty")
+	}
+	if err != nil {
+		return exception.NewFieldValidationError("file", "invalid CSV: "+err.Error())
+	}
+
+	columns := map[string]int{}
+	for i, column := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))] = i
+	}
+	if _, ok := columns["name"]; !ok {
+		return exception.NewFieldValidationError("file", "CSV header has no name column")
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+
+		var parseError *csv.ParseError
+		if errors.As(err, &parseError) {
+			readRow(categoryImportRow{}, exception.NewFieldValidationError("row", parseError.Err.Error()))
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if columns["name"] >= len(record) {
+			readRow(categoryImportRow{}, exception.NewFieldValidationError("row", "row has no name column"))
+			continue
+		}
+
+		id, err := csvId(record, columns, "id")
+		if err != nil {
+			readRow(categoryImportRow{}, err)
+			continue
+		}
+
+		parentId, err := csvId(record, columns, "parent_id")
+		if err != nil {
+			readRow(categoryImportRow{}, err)
+			continue
+		}
+
+		readRow(categoryImportRow{Id: intValue(id), Name: record[columns["name"]], ParentId: parentId}, nil)
+	}
+}
+
+// csvId parses the id in the named column of record, returning nil if the
+// file has no such column or the field is empty.
+func csvId(record []string, columns map[string]int, column string) (*int, error) {
+	i, ok := columns[column]
+	if !ok || i >= len(record) || strings.TrimSpace(record[i]) == "" {
+		return nil, nil
+	}
+
+	id, err := strconv.Atoi(strings.TrimSpace(record[i]))
+	if err != nil || id <= 0 {
+		return nil, exception.NewFieldValidationError(column, column+" must be a positive integer")
+	}
+
+	return &id, nil
+}
+
+// readJSONLines reads one JSON object per line, skipping blank lines.
+// Fields other than id, name and parent_id, such as the slug and version
+// of an export, are ignored.
+func readJSONLines(file io.Reader, readRow func(row categoryImportRow, err error)) error {
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxImportLineSize)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var row struct {
+			Id       int    `json:"id"`
+			Name     string `json:"name"`
+			ParentId *int   `json:"parent_id"`
+		}
+		err := json.Unmarshal([]byte(line), &row)
+		if err != nil {
+			readRow(categoryImportRow{}, exception.NewFieldValidationError("row", "invalid JSON"))
+			continue
+		}
+
+		readRow(categoryImportRow{Id: row.Id, Name: row.Name, ParentId: row.ParentId}, nil)
+	}
+
+	if errors.Is(scanner.Err(), bufio.ErrTooLong) {
+		return exception.NewFieldValidationError("file", "line is too long")
+	}
+
+	return scanner.Err()
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+
+	"sudutkampus/gorestfulapi/exception"
+	"sudutkampus/gorestfulapi/model/domain"
+	"sudutkampus/gorestfulapi/model/web"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+)
+
+// applyCategoryPatch applies a patch to the updatable fields of category,
+// the same document PUT accepts, and decodes the result as an update
+// request so that it goes through the same validation.
+func applyCategoryPatch(category domain.Category, request web.CategoryPatchRequest) (web.CategoryUpdateRequest, error) {
+	document, err := json.Marshal(web.CategoryUpdateRequest{Id: category.Id, Name: category.Name})
+	if err != nil {
+		return web.CategoryUpdateRequest{}, err
+	}
+
+	switch request.MediaType {
+	case web.MergePatchMediaType:
+		document, err = jsonpatch.MergePatch(document, request.Patch)
+	case web.JSONPatchMediaType:
+		var patch jsonpatch.Patch
+		patch, err = jsonpatch.DecodePatch(request.Patch)
+		if err == nil {
+			document, err = patch.Apply(document)
+		}
+	default:
+		return web.CategoryUpdateRequest{}, exception.NewUnsupportedMediaTypeError("patch must be " + web.MergePatchMediaType + " or " + web.JSONPatchMediaType)
+	}
+
+	// a failed test operation means the category is not in the state the
+	// client assumed
+	if errors.Is(err, jsonpatch.ErrTestFailed) {
+		return web.CategoryUpdateRequest{}, exception.NewConflictError(err.Error())
+	}
+	if err != nil {
+		return web.CategoryUpdateRequest{}, exception.NewValidationError("invalid patch: " + err.Error())
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(document))
+	decoder.DisallowUnknownFields()
+
+	updateRequest := web.CategoryUpdateRequest{}
+	err = decoder.Decode(&updateRequest)
+	if err != nil {
+		return web.CategoryUpdateRequest{}, exception.NewValidationError("invalid patch: " + err.Error())
+	}
+
+	if updateRequest.Id != category.Id {
+		return web.CategoryUpdateRequest{}, exception.NewFieldValidationError("id", "id cannot be changed")
+	}
+
+	return updateRequest, nil
+}
@@ -2,14 +2,29 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"sudutkampus/gorestfulapi/model/web"
 )
 
 type CategoryService interface {
-	Create(ctx context.Context, request web.CategoryCreateRequest) web.CategoryResponse
-	Update(ctx context.Context, request web.CategoryUpdateRequest) web.CategoryResponse
-	Delete(ctx context.Context, categoryId int)
-	FindById(ctx context.Context, categoryId int) web.CategoryResponse
-	FindAll(ctx context.Context) []web.CategoryResponse
+	Create(ctx context.Context, request web.CategoryCreateRequest) (web.CategoryResponse, error)
+	Update(ctx context.Context, request web.CategoryUpdateRequest) (web.CategoryResponse, error)
+	Patch(ctx context.Context, request web.CategoryPatchRequest) (web.CategoryResponse, error)
+	Delete(ctx context.Context, categoryId int, version int) error
+	Bulk(ctx context.Context, request web.CategoryBulkRequest) ([]web.CategoryBulkResult, error)
+	Move(ctx context.Context, request web.CategoryMoveRequest) (web.CategoryResponse, error)
+	Restore(ctx context.Context, categoryId int) (web.CategoryResponse, error)
+	Purge(ctx context.Context, categoryId int, version int) error
+	PurgeTrashed(ctx context.Context, deletedBefore time.Time) (int, error)
+	FindById(ctx context.Context, categoryId int) (web.CategoryResponse, error)
+	FindBySlug(ctx context.Context, slug string) (web.CategoryResponse, error)
+	BackfillSlugs(ctx context.Context) (int, error)
+	Export(ctx context.Context, request web.CategoryExportRequest, fn func(category web.CategoryResponse) error) error
+	Import(ctx context.Context, request web.CategoryImportRequest) (web.CategoryImportResponse, error)
+	FindChildren(ctx context.Context, categoryId int) ([]web.CategoryResponse, error)
+	FindAncestors(ctx context.Context, categoryId int) ([]web.CategoryResponse, error)
+	FindTree(ctx context.Context) ([]web.CategoryTreeResponse, error)
+	FindAll(ctx context.Context, request web.CategoryFindAllRequest) ([]web.CategoryResponse, web.PageMeta, error)
+	FindTrashed(ctx context.Context, request web.CategoryFindAllRequest) ([]web.CategoryResponse, web.PageMeta, error)
 }
@@ -2,10 +2,13 @@ package service
 
 import (
 	"context"
-	"database/sql"
+	"errors"
+	"time"
 
+	"sudutkampus/gorestfulapi/config"
 	"sudutkampus/gorestfulapi/exception"
 	"sudutkampus/gorestfulapi/helper"
+	"sudutkampus/gorestfulapi/logging"
 	"sudutkampus/gorestfulapi/model/domain"
 	"sudutkampus/gorestfulapi/model/web"
 	"sudutkampus/gorestfulapi/repository"
@@ -13,90 +16,457 @@ import (
 	"github.com/go-playground/validator/v10"
 )
 
+const DefaultPerPage = 20
+
 type CategoryServiceImpl struct {
 	CategoryRepository repository.CategoryRepository
-	DB                 *sql.DB
+	ProductRepository  repository.ProductRepository
+	AuditLogRepository repository.AuditLogRepository
+	UnitOfWork         repository.UnitOfWork
 	Validate           validator.Validate
+	OnDelete           string
+	Logger             *logging.Logger
 }
 
-func NewCategoryService(categoryRepository repository.CategoryRepository, DB *sql.DB, validate *validator.Validate) CategoryService {
+func NewCategoryService(categoryRepository repository.CategoryRepository, productRepository repository.ProductRepository, auditLogRepository repository.AuditLogRepository, unitOfWork repository.UnitOfWork, validate *validator.Validate, hierarchyConfig config.HierarchyConfig, logger *logging.Logger) CategoryService {
 	return &CategoryServiceImpl{
 		CategoryRepository: categoryRepository,
-		DB:                 DB,
+		ProductRepository:  productRepository,
+		AuditLogRepository: auditLogRepository,
+		UnitOfWork:         unitOfWork,
 		Validate:           *validate,
+		OnDelete:           hierarchyConfig.OnDelete,
+		Logger:             logger,
 	}
 }
 
-func (service *CategoryServiceImpl) Create(ctx context.Context, request web.CategoryCreateRequest) web.CategoryResponse {
-	err := service.Validate.Struct(request)
-	helper.PanicIfError(err)
+func (service *CategoryServiceImpl) Create(ctx context.Context, request web.CategoryCreateRequest) (web.CategoryResponse, error) {
+	var category domain.Category
+	err := service.UnitOfWork.Do(ctx, func(tx repository.Tx) (err error) {
+		category, err = service.newCategory(ctx, tx, request, map[string]bool{}, map[string]bool{})
+		if err != nil {
+			return err
+		}
 
-	tx, err := service.DB.Begin()
-	helper.PanicIfError(err)
-	defer helper.CommitOrRollback(tx)
+		category, err = service.CategoryRepository.Save(ctx, tx, category)
+		if err != nil {
+			return writeConflict(err)
+		}
 
-	category := domain.Category{
-		Id:   0,
-		Name: request.Name,
+		return service.audit(ctx, tx, domain.AuditActionCreate, nil, &category)
+	})
+	if err != nil {
+		return web.CategoryResponse{}, err
 	}
 
-	category = service.CategoryRepository.Save(ctx, tx, category)
-
-	return helper.ToCategoryResponse(category)
+	return helper.ToCategoryResponse(category), nil
 }
 
-func (service *CategoryServiceImpl) Update(ctx context.Context, request web.CategoryUpdateRequest) web.CategoryResponse {
+func (service *CategoryServiceImpl) Update(ctx context.Context, request web.CategoryUpdateRequest) (web.CategoryResponse, error) {
 	err := service.Validate.Struct(request)
-	helper.PanicIfError(err)
+	if err != nil {
+		return web.CategoryResponse{}, exception.FromValidator(err)
+	}
 
-	tx, err := service.DB.Begin()
-	helper.PanicIfError(err)
-	defer helper.CommitOrRollback(tx)
+	var category domain.Category
+	err = service.UnitOfWork.Do(ctx, func(tx repository.Tx) error {
+		category, err = service.update(ctx, tx, request)
+		return err
+	})
+	if err != nil {
+		return web.CategoryResponse{}, err
+	}
+
+	return helper.ToCategoryResponse(category), nil
+}
+
+func (service *CategoryServiceImpl) update(ctx context.Context, tx repository.Tx, request web.CategoryUpdateRequest) (domain.Category, error) {
+	category, err := service.findCategory(ctx, tx, request.Id)
+	if err != nil {
+		return category, err
+	}
+
+	err = checkVersion(category, request.Version)
+	if err != nil {
+		return category, err
+	}
 
-	category, err := service.CategoryRepository.FindById(ctx, tx, request.Id)
+	err = service.checkName(ctx, tx, request.Name, category.Id)
 	if err != nil {
-		panic(exception.NewNotFoundError(err.Error()))
+		return category, err
 	}
 
+	before := category
 	category.Name = request.Name
 
-	category = service.CategoryRepository.Update(ctx, tx, category)
+	category, err = service.CategoryRepository.Update(ctx, tx, category)
+	if err != nil {
+		return category, writeConflict(err)
+	}
 
-	return helper.ToCategoryResponse(category)
+	return category, service.audit(ctx, tx, domain.AuditActionUpdate, &before, &category)
 }
 
-func (service *CategoryServiceImpl) Delete(ctx context.Context, categoryId int) {
-	tx, err := service.DB.Begin()
-	helper.PanicIfError(err)
-	defer helper.CommitOrRollback(tx)
+func (service *CategoryServiceImpl) Patch(ctx context.Context, request web.CategoryPatchRequest) (web.CategoryResponse, error) {
+	var category domain.Category
+	err := service.UnitOfWork.Do(ctx, func(tx repository.Tx) (err error) {
+		category, err = service.findCategory(ctx, tx, request.Id)
+		if err != nil {
+			return err
+		}
 
-	category, err := service.CategoryRepository.FindById(ctx, tx, categoryId)
+		err = checkVersion(category, request.Version)
+		if err != nil {
+			return err
+		}
+
+		updateRequest, err := applyCategoryPatch(category, request)
+		if err != nil {
+			return err
+		}
+
+		err = service.Validate.Struct(updateRequest)
+		if err != nil {
+			return exception.FromValidator(err)
+		}
+
+		err = service.checkName(ctx, tx, updateRequest.Name, category.Id)
+		if err != nil {
+			return err
+		}
+
+		before := category
+		category.Name = updateRequest.Name
+
+		category, err = service.CategoryRepository.Update(ctx, tx, category)
+		if err != nil {
+			return writeConflict(err)
+		}
+
+		return service.audit(ctx, tx, domain.AuditActionUpdate, &before, &category)
+	})
 	if err != nil {
-		panic(exception.NewNotFoundError(err.Error()))
+		return web.CategoryResponse{}, err
 	}
 
-	service.CategoryRepository.Delete(ctx, tx, category)
+	return helper.ToCategoryResponse(category), nil
+}
+
+// Delete moves a category to the trash, from which it can be restored
+// until it is purged.
+func (service *CategoryServiceImpl) Delete(ctx context.Context, categoryId int, version int) error {
+	return service.UnitOfWork.Do(ctx, func(tx repository.Tx) error {
+		return service.delete(ctx, tx, categoryId, version)
+	})
 }
 
-func (service *CategoryServiceImpl) FindById(ctx context.Context, categoryId int) web.CategoryResponse {
-	tx, err := service.DB.Begin()
-	helper.PanicIfError(err)
-	defer helper.CommitOrRollback(tx)
+func (service *CategoryServiceImpl) delete(ctx context.Context, tx repository.Tx, categoryId int, version int) error {
+	category, err := service.findCategory(ctx, tx, categoryId)
+	if err != nil {
+		return err
+	}
+
+	err = checkVersion(category, version)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	trash := func(category domain.Category) error {
+		err := service.checkProducts(ctx, tx, category)
+		if err != nil {
+			return err
+		}
+
+		before := category
+		category.DeletedAt = &now
+		err = service.CategoryRepository.Delete(ctx, tx, category)
+		if err != nil {
+			return writeConflict(err)
+		}
+		category.Version++
+
+		return service.audit(ctx, tx, domain.AuditActionDelete, &before, &category)
+	}
+
+	err = service.releaseChildren(ctx, tx, category, trash)
+	if err != nil {
+		return err
+	}
+
+	return trash(category)
+}
+
+func (service *CategoryServiceImpl) Restore(ctx context.Context, categoryId int) (web.CategoryResponse, error) {
+	var category domain.Category
+	err := service.UnitOfWork.Do(ctx, func(tx repository.Tx) (err error) {
+		category, err = service.findCategoryWithTrashed(ctx, tx, categoryId)
+		if err != nil || category.DeletedAt == nil {
+			return err
+		}
+
+		err = service.checkName(ctx, tx, category.Name, category.Id)
+		if err != nil {
+			return err
+		}
+
+		before := category
+		err = service.CategoryRepository.Restore(ctx, tx, category)
+		if err != nil {
+			return writeConflict(err)
+		}
+		category.DeletedAt = nil
+		category.Version++
+
+		// a category whose parent has gone since is restored at the root
+		if category.ParentId != nil {
+			_, err = service.CategoryRepository.FindById(ctx, tx, *category.ParentId)
+			if errors.Is(err, repository.ErrCategoryNotFound) {
+				category.ParentId = nil
+				category, err = service.CategoryRepository.Update(ctx, tx, category)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		return service.audit(ctx, tx, domain.AuditActionRestore, &before, &category)
+	})
+	if err != nil {
+		return web.CategoryResponse{}, err
+	}
+
+	return helper.ToCategoryResponse(category), nil
+}
+
+// Purge permanently deletes a category, whether or not it is in the trash.
+func (service *CategoryServiceImpl) Purge(ctx context.Context, categoryId int, version int) error {
+	err := service.UnitOfWork.Do(ctx, func(tx repository.Tx) error {
+		category, err := service.findCategoryWithTrashed(ctx, tx, categoryId)
+		if err != nil {
+			return err
+		}
+
+		err = checkVersion(category, version)
+		if err != nil {
+			return err
+		}
+
+		purge := func(category domain.Category) error {
+			err := service.checkProducts(ctx, tx, category)
+			if err != nil {
+				return err
+			}
+
+			err = service.CategoryRepository.Purge(ctx, tx, category)
+			if err != nil {
+				return err
+			}
+
+			return service.audit(ctx, tx, domain.AuditActionPurge, &category, nil)
+		}
+
+		if category.DeletedAt == nil {
+			err = service.releaseChildren(ctx, tx, category, purge)
+			if err != nil {
+				return err
+			}
+		}
+
+		return purge(category)
+	})
+	if err != nil {
+		return err
+	}
 
+	service.Logger.Info(ctx, "purged category", "category_id", categoryId)
+	return nil
+}
+
+func (service *CategoryServiceImpl) PurgeTrashed(ctx context.Context, deletedBefore time.Time) (int, error) {
+	var purged int
+	err := service.UnitOfWork.Do(ctx, func(tx repository.Tx) (err error) {
+		var expired []domain.Category
+		err = service.CategoryRepository.Each(ctx, tx, domain.CategoryFilter{Trashed: true}, func(category domain.Category) error {
+			if category.DeletedAt.Before(deletedBefore) {
+				expired = append(expired, category)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		purged, err = service.CategoryRepository.PurgeTrashed(ctx, tx, deletedBefore)
+		if err != nil {
+			return err
+		}
+
+		for i := range expired {
+			err = service.audit(ctx, tx, domain.AuditActionPurge, &expired[i], nil)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return purged, err
+}
+
+func (service *CategoryServiceImpl) FindById(ctx context.Context, categoryId int) (web.CategoryResponse, error) {
+	var category domain.Category
+	err := service.UnitOfWork.Do(ctx, func(tx repository.Tx) (err error) {
+		category, err = service.findCategory(ctx, tx, categoryId)
+		return err
+	})
+	if err != nil {
+		return web.CategoryResponse{}, err
+	}
+
+	return helper.ToCategoryResponse(category), nil
+}
+
+func (service *CategoryServiceImpl) FindBySlug(ctx context.Context, slug string) (web.CategoryResponse, error) {
+	var category domain.Category
+	err := service.UnitOfWork.Do(ctx, func(tx repository.Tx) (err error) {
+		category, err = service.CategoryRepository.FindBySlug(ctx, tx, slug)
+		if errors.Is(err, repository.ErrCategoryNotFound) {
+			return exception.NewNotFoundError(err.Error())
+		}
+		return err
+	})
+	if err != nil {
+		return web.CategoryResponse{}, err
+	}
+
+	return helper.ToCategoryResponse(category), nil
+}
+
+func (service *CategoryServiceImpl) FindAll(ctx context.Context, request web.CategoryFindAllRequest) ([]web.CategoryResponse, web.PageMeta, error) {
+	return service.findAll(ctx, request, false)
+}
+
+func (service *CategoryServiceImpl) FindTrashed(ctx context.Context, request web.CategoryFindAllRequest) ([]web.CategoryResponse, web.PageMeta, error) {
+	return service.findAll(ctx, request, true)
+}
+
+func (service *CategoryServiceImpl) findAll(ctx context.Context, request web.CategoryFindAllRequest, trashed bool) ([]web.CategoryResponse, web.PageMeta, error) {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return nil, web.PageMeta{}, exception.FromValidator(err)
+	}
+
+	if request.Page == 0 {
+		request.Page = 1
+	}
+	if request.PerPage == 0 {
+		request.PerPage = DefaultPerPage
+	}
+
+	filter := domain.CategoryFilter{
+		Name:    request.Q,
+		Sort:    request.Sort,
+		Limit:   request.PerPage + 1,
+		Offset:  (request.Page - 1) * request.PerPage,
+		Trashed: trashed,
+	}
+
+	if request.After != "" {
+		filter.After, err = helper.DecodeCursor(request.After)
+		if err != nil {
+			return nil, web.PageMeta{}, exception.NewFieldValidationError("after", "invalid cursor")
+		}
+	}
+
+	var categories []domain.Category
+	var total int
+	err = service.UnitOfWork.Do(ctx, func(tx repository.Tx) error {
+		categories, err = service.CategoryRepository.FindAll(ctx, tx, filter)
+		if err != nil {
+			return err
+		}
+
+		total, err = service.CategoryRepository.Count(ctx, tx, filter)
+		return err
+	})
+	if err != nil {
+		return nil, web.PageMeta{}, err
+	}
+
+	meta := web.PageMeta{
+		Total:   total,
+		PerPage: request.PerPage,
+	}
+	if filter.After == nil {
+		meta.Page = request.Page
+	}
+
+	if len(categories) > request.PerPage {
+		categories = categories[:request.PerPage]
+		last := categories[len(categories)-1]
+		meta.NextCursor, err = helper.EncodeCursor(domain.CategoryCursor{Id: last.Id, Name: last.Name})
+		if err != nil {
+			return nil, web.PageMeta{}, err
+		}
+	}
+
+	return helper.ToCategoryResponses(categories), meta, nil
+}
+
+func (service *CategoryServiceImpl) findCategory(ctx context.Context, tx repository.Tx, categoryId int) (domain.Category, error) {
 	category, err := service.CategoryRepository.FindById(ctx, tx, categoryId)
+	if errors.Is(err, repository.ErrCategoryNotFound) {
+		return category, exception.NewNotFoundError(err.Error())
+	}
+
+	return category, err
+}
+
+func (service *CategoryServiceImpl) findCategoryWithTrashed(ctx context.Context, tx repository.Tx, categoryId int) (domain.Category, error) {
+	category, err := service.CategoryRepository.FindByIdWithTrashed(ctx, tx, categoryId)
+	if errors.Is(err, repository.ErrCategoryNotFound) {
+		return category, exception.NewNotFoundError(err.Error())
+	}
+
+	return category, err
+}
+
+// checkProducts keeps a category that still has products from being deleted
+// or purged, since they would be left without one.
+func (service *CategoryServiceImpl) checkProducts(ctx context.Context, tx repository.Tx, category domain.Category) error {
+	count, err := service.ProductRepository.CountByCategory(ctx, tx, category.Id)
 	if err != nil {
-		panic(exception.NewNotFoundError(err.Error()))
+		return err
 	}
 
-	return helper.ToCategoryResponse(category)
+	if count > 0 {
+		return exception.NewConflictError("category has products")
+	}
+
+	return nil
 }
 
-func (service *CategoryServiceImpl) FindAll(ctx context.Context) []web.CategoryResponse {
-	tx, err := service.DB.Begin()
-	helper.PanicIfError(err)
-	defer helper.CommitOrRollback(tx)
+// checkVersion fails when the client expects a version, from If-Match, that
+// the category has already moved past.
+func checkVersion(category domain.Category, version int) error {
+	if version != 0 && version != category.Version {
+		return exception.NewPreconditionFailedError("category has been modified")
+	}
 
-	categories := service.CategoryRepository.FindAll(ctx, tx)
+	return nil
+}
+
+// writeConflict reports a concurrent write that slipped in between reading
+// and updating a category like a failed If-Match, and one that took the
+// same name between checking and writing it like the name check does.
+func writeConflict(err error) error {
+	if errors.Is(err, repository.ErrCategoryVersionConflict) {
+		return exception.NewPreconditionFailedError(err.Error())
+	}
+	if errors.Is(err, repository.ErrCategoryNameConflict) {
+		return exception.NewConflictError(err.Error())
+	}
 
-	return helper.ToCategoryResponses(categories)
+	return err
 }
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"sudutkampus/gorestfulapi/model/web"
+	"sudutkampus/gorestfulapi/tracing"
+)
+
+// CategoryServiceTraced records a span for every call to the category
+// service it wraps.
+type CategoryServiceTraced struct {
+	Service CategoryService
+	Tracer  trace.Tracer
+}
+
+func NewCategoryServiceTraced(service CategoryService, tracer trace.Tracer) CategoryService {
+	return &CategoryServiceTraced{
+		Service: service,
+		Tracer:  tracer,
+	}
+}
+
+func (service *CategoryServiceTraced) Create(ctx context.Context, request web.CategoryCreateRequest) (web.CategoryResponse, error) {
+	ctx, span := service.Tracer.Start(ctx, "CategoryService.Create")
+	response, err := service.Service.Create(ctx, request)
+	tracing.End(span, err)
+
+	return response, err
+}
+
+func (service *CategoryServiceTraced) Update(ctx context.Context, request web.CategoryUpdateRequest) (web.CategoryResponse, error) {
+	ctx, span := service.Tracer.Start(ctx, "CategoryService.Update")
+	response, err := service.Service.Update(ctx, request)
+	tracing.End(span, err)
+
+	return response, err
+}
+
+func (service *CategoryServiceTraced) Patch(ctx context.Context, request web.CategoryPatchRequest) (web.CategoryResponse, error) {
+	ctx, span := service.Tracer.Start(ctx, "CategoryService.Patch")
+	response, err := service.Service.Patch(ctx, request)
+	tracing.End(span, err)
+
+	return response, err
+}
+
+func (service *CategoryServiceTraced) Delete(ctx context.Context, categoryId int, version int) error {
+	ctx, span := service.Tracer.Start(ctx, "CategoryService.Delete")
+	err := service.Service.Delete(ctx, categoryId, version)
+	tracing.End(span, err)
+
+	return err
+}
+
+func (service *CategoryServiceTraced) Bulk(ctx context.Context, request web.CategoryBulkRequest) ([]web.CategoryBulkResult, error) {
+	ctx, span := service.Tracer.Start(ctx, "CategoryService.Bulk")
+	results, err := service.Service.Bulk(ctx, request)
+	tracing.End(span, err)
+
+	return results, err
+}
+
+func (service *CategoryServiceTraced) Move(ctx context.Context, request web.CategoryMoveRequest) (web.CategoryResponse, error) {
+	ctx, span := service.Tracer.Start(ctx, "CategoryService.Move")
+	response, err := service.Service.Move(ctx, request)
+	tracing.End(span, err)
+
+	return response, err
+}
+
+func (service *CategoryServiceTraced) Restore(ctx context.Context, categoryId int) (web.CategoryResponse, error) {
+	ctx, span := service.Tracer.Start(ctx, "CategoryService.Restore")
+	response, err := service.Service.Restore(ctx, categoryId)
+	tracing.End(span, err)
+
+	return response, err
+}
+
+func (service *CategoryServiceTraced) Purge(ctx context.Context, categoryId int, version int) error {
+	ctx, span := service.Tracer.Start(ctx, "CategoryService.Purge")
+	err := service.Service.Purge(ctx, categoryId, version)
+	tracing.End(span, err)
+
+	return err
+}
+
+func (service *CategoryServiceTraced) PurgeTrashed(ctx context.Context, deletedBefore time.Time) (int, error) {
+	ctx, span := service.Tracer.Start(ctx, "CategoryService.PurgeTrashed")
+	count, err := service.Service.PurgeTrashed(ctx, deletedBefore)
+	tracing.End(span, err)
+
+	return count, err
+}
+
+func (service *CategoryServiceTraced) FindById(ctx context.Context, categoryId int) (web.CategoryResponse, error) {
+	ctx, span := service.Tracer.Start(ctx, "CategoryService.FindById")
+	response, err := service.Service.FindById(ctx, categoryId)
+	tracing.End(span, err)
+
+	return response, err
+}
+
+func (service *CategoryServiceTraced) FindBySlug(ctx context.Context, slug string) (web.CategoryResponse, error) {
+	ctx, span := service.Tracer.Start(ctx, "CategoryService.FindBySlug")
+	response, err := service.Service.FindBySlug(ctx, slug)
+	tracing.End(span, err)
+
+	return response, err
+}
+
+func (service *CategoryServiceTraced) BackfillSlugs(ctx context.Context) (int, error) {
+	ctx, span := service.Tracer.Start(ctx, "CategoryService.BackfillSlugs")
+	count, err := service.Service.BackfillSlugs(ctx)
+	tracing.End(span, err)
+
+	return count, err
+}
+
+func (service *CategoryServiceTraced) Export(ctx context.Context, request web.CategoryExportRequest, fn func(category web.CategoryResponse) error) error {
+	ctx, span := service.Tracer.Start(ctx, "CategoryService.Export")
+	err := service.Service.Export(ctx, request, fn)
+	tracing.End(span, err)
+
+	return err
+}
+
+func (service *CategoryServiceTraced) Import(ctx context.Context, request web.CategoryImportRequest) (web.CategoryImportResponse, error) {
+	ctx, span := service.Tracer.Start(ctx, "CategoryService.Import")
+	response, err := service.Service.Import(ctx, request)
+	tracing.End(span, err)
+
+	return response, err
+}
+
+func (service *CategoryServiceTraced) FindChildren(ctx context.Context, categoryId int) ([]web.CategoryResponse, error) {
+	ctx, span := service.Tracer.Start(ctx, "CategoryService.FindChildren")
+	responses, err := service.Service.FindChildren(ctx, categoryId)
+	tracing.End(span, err)
+
+	return responses, err
+}
+
+func (service *CategoryServiceTraced) FindAncestors(ctx context.Context, categoryId int) ([]web.CategoryResponse, error) {
+	ctx, span := service.Tracer.Start(ctx, "CategoryService.FindAncestors")
+	responses, err := service.Service.FindAncestors(ctx, categoryId)
+	tracing.End(span, err)
+
+	return responses, err
+}
+
+func (service *CategoryServiceTraced) FindTree(ctx context.Context) ([]web.CategoryTreeResponse, error) {
+	ctx, span := service.Tracer.Start(ctx, "CategoryService.FindTree")
+	tree, err := service.Service.FindTree(ctx)
+	tracing.End(span, err)
+
+	return tree, err
+}
+
+func (service *CategoryServiceTraced) FindAll(ctx context.Context, request web.CategoryFindAllRequest) ([]web.CategoryResponse, web.PageMeta, error) {
+	ctx, span := service.Tracer.Start(ctx, "CategoryService.FindAll")
+	responses, meta, err := service.Service.FindAll(ctx, request)
+	tracing.End(span, err)
+
+	return responses, meta, err
+}
+
+func (service *CategoryServiceTraced) FindTrashed(ctx context.Context, request web.CategoryFindAllRequest) ([]web.CategoryResponse, web.PageMeta, error) {
+	ctx, span := service.Tracer.Start(ctx, "CategoryService.FindTrashed")
+	responses, meta, err := service.Service.FindTrashed(ctx, request)
+	tracing.End(span, err)
+
+	return responses, meta, err
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"sudutkampus/gorestfulapi/exception"
+	"sudutkampus/gorestfulapi/helper"
+	"sudutkampus/gorestfulapi/model/domain"
+	"sudutkampus/gorestfulapi/repository"
+)
+
+// defaultSlug is used for names without a single letter or digit that
+// can be transliterated.
+const defaultSlug = "category"
+
+// slugAttempts bounds how often backfillSlug starts over after a
+// concurrent writer changed the category or took the slug it chose.
+const slugAttempts = 5
+
+// BackfillSlugs gives a slug to every category created before slugs
+// existed and returns how many it updated. Each category is updated in its
+// own unit of work and only while it still has no slug, so concurrent runs
+// share the work instead of failing, and running it again does nothing.
+func (service *CategoryServiceImpl) BackfillSlugs(ctx context.Context) (int, error) {
+	var categories []domain.Category
+	err := service.UnitOfWork.Do(ctx, func(tx repository.Tx) error {
+		var err error
+		categories, err = service.CategoryRepository.FindWithoutSlug(ctx, tx)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var updated int
+	for _, category := range categories {
+		ok, err := service.backfillSlug(ctx, category.Id)
+		if err != nil {
+			return updated, err
+		}
+		if ok {
+			updated++
+		}
+	}
+
+	return updated, nil
+}
+
+// backfillSlug gives the category a slug unless it already has one, or
+// is gone, and reports whether it did.
+func (service *CategoryServiceImpl) backfillSlug(ctx context.Context, categoryId int) (bool, error) {
+	for attempt := 1; ; attempt++ {
+		var updated bool
+		err := service.UnitOfWork.Do(ctx, func(tx repository.Tx) error {
+			category, err := service.CategoryRepository.FindByIdWithTrashed(ctx, tx, categoryId)
+			if errors.Is(err, repository.ErrCategoryNotFound) {
+				return nil
+			}
+			if err != nil || category.Slug != "" {
+				return err
+			}
+
+			before := category
+			category.Slug, err = service.slug(ctx, tx, category.Name, nil)
+			if err != nil {
+				return err
+			}
+
+			category, err = service.CategoryRepository.Update(ctx, tx, category)
+			if err != nil {
+				return err
+			}
+
+			updated = true
+			return service.audit(ctx, tx, domain.AuditActionUpdate, &before, &category)
+		})
+		conflict := errors.Is(err, repository.ErrCategoryVersionConflict) || errors.Is(err, repository.ErrCategorySlugConflict)
+		if conflict && attempt < slugAttempts {
+			continue
+		}
+
+		return updated, err
+	}
+}
+
+// slug derives a slug from name that no category uses yet, trashed ones
+// included, by appending -2, -3 and so on. Slugs in reserved are treated
+// as taken, for categories about to be inserted together.
+func (service *CategoryServiceImpl) slug(ctx context.Context, tx repository.Tx, name string, reserved map[string]bool) (string, error) {
+	base := helper.Slugify(name)
+	if base == "" {
+		base = defaultSlug
+	}
+
+	for n := 1; ; n++ {
+		slug := base
+		if n > 1 {
+			slug += "-" + strconv.Itoa(n)
+		}
+		if reserved[slug] {
+			continue
+		}
+
+		exists, err := service.CategoryRepository.SlugExists(ctx, tx, slug)
+		if err != nil || !exists {
+			return slug, err
+		}
+	}
+}
+
+// checkName rejects a name that another live category already has,
+// ignoring case. categoryId is the category being renamed, if any.
+func (service *CategoryServiceImpl) checkName(ctx context.Context, tx repository.Tx, name string, categoryId int) error {
+	category, err := service.CategoryRepository.FindByName(ctx, tx, name)
+	if errors.Is(err, repository.ErrCategoryNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if category.Id != categoryId {
+		return exception.NewConflictError("category name already exists")
+	}
+
+	return nil
+}
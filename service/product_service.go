@@ -0,0 +1,16 @@
+package service
+
+import (
+	"context"
+
+	"sudutkampus/gorestfulapi/model/web"
+)
+
+type ProductService interface {
+	Create(ctx context.Context, request web.ProductCreateRequest) (web.ProductResponse, error)
+	Update(ctx context.Context, request web.ProductUpdateRequest) (web.ProductResponse, error)
+	Delete(ctx context.Context, productId int) error
+	FindById(ctx context.Context, productId int) (web.ProductResponse, error)
+	FindAll(ctx context.Context, request web.ProductFindAllRequest) ([]web.ProductResponse, web.PageMeta, error)
+	FindByCategory(ctx context.Context, categoryId int, request web.ProductFindAllRequest) ([]web.ProductResponse, web.PageMeta, error)
+}
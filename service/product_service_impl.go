@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"sudutkampus/gorestfulapi/exception"
+	"sudutkampus/gorestfulapi/helper"
+	"sudutkampus/gorestfulapi/logging"
+	"sudutkampus/gorestfulapi/model/domain"
+	"sudutkampus/gorestfulapi/model/web"
+	"sudutkampus/gorestfulapi/repository"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/shopspring/decimal"
+)
+
+// maxPrice is the first value the decimal(12, 2) price column cannot hold.
+var maxPrice = decimal.New(1, 10)
+
+type ProductServiceImpl struct {
+	ProductRepository  repository.ProductRepository
+	CategoryRepository repository.CategoryRepository
+	UnitOfWork         repository.UnitOfWork
+	Validate           validator.Validate
+	Logger             *logging.Logger
+}
+
+func NewProductService(productRepository repository.ProductRepository, categoryRepository repository.CategoryRepository, unitOfWork repository.UnitOfWork, validate *validator.Validate, logger *logging.Logger) ProductService {
+	return &ProductServiceImpl{
+		ProductRepository:  productRepository,
+		CategoryRepository: categoryRepository,
+		UnitOfWork:         unitOfWork,
+		Validate:           *validate,
+		Logger:             logger,
+	}
+}
+
+func (service *ProductServiceImpl) Create(ctx context.Context, request web.ProductCreateRequest) (web.ProductResponse, error) {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.ProductResponse{}, exception.FromValidator(err)
+	}
+
+	err = checkPrice(*request.Price)
+	if err != nil {
+		return web.ProductResponse{}, err
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	product := domain.Product{
+		CategoryId: request.CategoryId,
+		Name:       request.Name,
+		Sku:        request.Sku,
+		Price:      *request.Price,
+		Stock:      request.Stock,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	err = service.UnitOfWork.Do(ctx, func(tx repository.Tx) (err error) {
+		err = service.checkProduct(ctx, tx, product)
+		if err != nil {
+			return err
+		}
+
+		product, err = service.ProductRepository.Save(ctx, tx, product)
+		return err
+	})
+	if err != nil {
+		return web.ProductResponse{}, err
+	}
+
+	service.Logger.Info(ctx, "created product", "product_id", product.Id, "category_id", product.CategoryId)
+	return helper.ToProductResponse(product), nil
+}
+
+func (service *ProductServiceImpl) Update(ctx context.Context, request web.ProductUpdateRequest) (web.ProductResponse, error) {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.ProductResponse{}, exception.FromValidator(err)
+	}
+
+	err = checkPrice(*request.Price)
+	if err != nil {
+		return web.ProductResponse{}, err
+	}
+
+	var product domain.Product
+	err = service.UnitOfWork.Do(ctx, func(tx repository.Tx) (err error) {
+		product, err = service.findProduct(ctx, tx, request.Id)
+		if err != nil {
+			return err
+		}
+
+		product.CategoryId = request.CategoryId
+		product.Name = request.Name
+		product.Sku = request.Sku
+		product.Price = *request.Price
+		product.Stock = request.Stock
+		product.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+
+		err = service.checkProduct(ctx, tx, product)
+		if err != nil {
+			return err
+		}
+
+		product, err = service.ProductRepository.Update(ctx, tx, product)
+		return err
+	})
+	if err != nil {
+		return web.ProductResponse{}, err
+	}
+
+	service.Logger.Info(ctx, "updated product", "product_id", product.Id, "category_id", product.CategoryId)
+	return helper.ToProductResponse(product), nil
+}
+
+func (service *ProductServiceImpl) Delete(ctx context.Context, productId int) error {
+	err := service.UnitOfWork.Do(ctx, func(tx repository.Tx) error {
+		product, err := service.findProduct(ctx, tx, productId)
+		if err != nil {
+			return err
+		}
+
+		return service.ProductRepository.Delete(ctx, tx, product)
+	})
+	if err != nil {
+		return err
+	}
+
+	service.Logger.Info(ctx, "deleted product", "product_id", productId)
+	return nil
+}
+
+func (service *ProductServiceImpl) FindById(ctx context.Context, productId int) (web.ProductResponse, error) {
+	var product domain.Product
+	err := service.UnitOfWork.Do(ctx, func(tx repository.Tx) (err error) {
+		product, err = service.findProduct(ctx, tx, productId)
+		return err
+	})
+	if err != nil {
+		return web.ProductResponse{}, err
+	}
+
+	return helper.ToProductResponse(product), nil
+}
+
+func (service *ProductServiceImpl) FindAll(ctx context.Context, request web.ProductFindAllRequest) ([]web.ProductResponse, web.PageMeta, error) {
+	return service.findAll(ctx, request, nil)
+}
+
+// FindByCategory lists the products of a live category.
+func (service *ProductServiceImpl) FindByCategory(ctx context.Context, categoryId int, request web.ProductFindAllRequest) ([]web.ProductResponse, web.PageMeta, error) {
+	request.CategoryId = categoryId
+
+	return service.findAll(ctx, request, func(tx repository.Tx) error {
+		_, err := service.CategoryRepository.FindById(ctx, tx, categoryId)
+		if errors.Is(err, repository.ErrCategoryNotFound) {
+			return exception.NewNotFoundError(err.Error())
+		}
+		return err
+	})
+}
+
+func (service *ProductServiceImpl) findAll(ctx context.Context, request web.ProductFindAllRequest, check func(tx repository.Tx) error) ([]web.ProductResponse, web.PageMeta, error) {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return nil, web.PageMeta{}, exception.FromValidator(err)
+	}
+
+	if request.Page == 0 {
+		request.Page = 1
+	}
+	if request.PerPage == 0 {
+		request.PerPage = DefaultPerPage
+	}
+
+	filter := domain.ProductFilter{
+		CategoryId: request.CategoryId,
+		Query:      request.Q,
+		Limit:      request.PerPage,
+		Offset:     (request.Page - 1) * request.PerPage,
+	}
+
+	var products []domain.Product
+	var total int
+	err = service.UnitOfWork.Do(ctx, func(tx repository.Tx) error {
+		if check != nil {
+			err := check(tx)
+			if err != nil {
+				return err
+			}
+		}
+
+		products, err = service.ProductRepository.FindAll(ctx, tx, filter)
+		if err != nil {
+			return err
+		}
+
+		total, err = service.ProductRepository.Count(ctx, tx, filter)
+		return err
+	})
+	if err != nil {
+		return nil, web.PageMeta{}, err
+	}
+
+	meta := web.PageMeta{
+		Total:   total,
+		Page:    request.Page,
+		PerPage: request.PerPage,
+	}
+
+	return helper.ToProductResponses(products), meta, nil
+}
+
+func (service *ProductServiceImpl) findProduct(ctx context.Context, tx repository.Tx, productId int) (domain.Product, error) {
+	product, err := service.ProductRepository.FindById(ctx, tx, productId)
+	if errors.Is(err, repository.ErrProductNotFound) {
+		return product, exception.NewNotFoundError(err.Error())
+	}
+
+	return product, err
+}
+
+// checkProduct makes sure a product belongs to a live category and that no
+// other product has its SKU.
+func (service *ProductServiceImpl) checkProduct(ctx context.Context, tx repository.Tx, product domain.Product) error {
+	_, err := service.CategoryRepository.FindById(ctx, tx, product.CategoryId)
+	if errors.Is(err, repository.ErrCategoryNotFound) {
+		return exception.NewFieldValidationError("category_id", "category not found")
+	}
+	if err != nil {
+		return err
+	}
+
+	existing, err := service.ProductRepository.FindBySku(ctx, tx, product.Sku)
+	if errors.Is(err, repository.ErrProductNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if existing.Id != product.Id {
+		return exception.NewConflictError("product sku already exists")
+	}
+
+	return nil
+}
+
+func checkPrice(price decimal.Decimal) error {
+	switch {
+	case price.IsNegative():
+		return exception.NewFieldValidationError("price", "price must not be negative")
+	case !price.Equal(price.Round(2)):
+		return exception.NewFieldValidationError("price", "price must have at most 2 decimal places")
+	case price.GreaterThanOrEqual(maxPrice):
+		return exception.NewFieldValidationError("price", "price is too large")
+	}
+
+	return nil
+}
@@ -0,0 +1,20 @@
+package service
+
+import (
+	"context"
+
+	"sudutkampus/gorestfulapi/auth"
+	"sudutkampus/gorestfulapi/model/web"
+)
+
+type UserService interface {
+	Register(ctx context.Context, request web.UserRegisterRequest) (web.UserResponse, error)
+	Login(ctx context.Context, request web.UserLoginRequest) (web.TokenResponse, error)
+	Refresh(ctx context.Context, request web.UserRefreshRequest) (web.TokenResponse, error)
+	Logout(ctx context.Context, sessionId int) error
+	ChangePassword(ctx context.Context, userId int, sessionId int, request web.UserChangePasswordRequest) error
+	Me(ctx context.Context, userId int) (web.UserResponse, error)
+	FindAll(ctx context.Context) ([]web.UserResponse, error)
+	AssignRoles(ctx context.Context, userId int, request web.UserRolesRequest) (web.UserResponse, error)
+	Authenticate(ctx context.Context, accessToken string) (auth.Principal, error)
+}
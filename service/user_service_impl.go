@@ -0,0 +1,413 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"sudutkampus/gorestfulapi/auth"
+	"sudutkampus/gorestfulapi/config"
+	"sudutkampus/gorestfulapi/exception"
+	"sudutkampus/gorestfulapi/helper"
+	"sudutkampus/gorestfulapi/logging"
+	"sudutkampus/gorestfulapi/model/domain"
+	"sudutkampus/gorestfulapi/model/web"
+	"sudutkampus/gorestfulapi/repository"
+
+	"github.com/go-playground/validator/v10"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Session tokens are random and opaque; the prefixes tell them apart from
+// JWTs and from each other.
+const (
+	AccessTokenPrefix  = "at_"
+	RefreshTokenPrefix = "rt_"
+)
+
+type UserServiceImpl struct {
+	UserRepository    repository.UserRepository
+	SessionRepository repository.SessionRepository
+	UnitOfWork        repository.UnitOfWork
+	Validate          validator.Validate
+	HashCost          int
+	SessionConfig     config.SessionConfig
+	Logger            *logging.Logger
+	// dummyHash is compared against on unknown emails so that login takes
+	// as long whether or not the account exists
+	dummyHash []byte
+}
+
+func NewUserService(userRepository repository.UserRepository, sessionRepository repository.SessionRepository, unitOfWork repository.UnitOfWork, validate *validator.Validate, hashCost int, sessionConfig config.SessionConfig, logger *logging.Logger) UserService {
+	dummyHash, _ := bcrypt.GenerateFromPassword([]byte("dummy password"), hashCost)
+
+	return &UserServiceImpl{
+		UserRepository:    userRepository,
+		SessionRepository: sessionRepository,
+		UnitOfWork:        unitOfWork,
+		Validate:          *validate,
+		HashCost:          hashCost,
+		SessionConfig:     sessionConfig,
+		Logger:            logger,
+		dummyHash:         dummyHash,
+	}
+}
+
+func (service *UserServiceImpl) Register(ctx context.Context, request web.UserRegisterRequest) (web.UserResponse, error) {
+	request.Email = normalizeEmail(request.Email)
+	request.Name = strings.TrimSpace(request.Name)
+
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.UserResponse{}, exception.FromValidator(err)
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(request.Password), service.HashCost)
+	if err != nil {
+		return web.UserResponse{}, err
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	user := domain.User{
+		Email:        request.Email,
+		Name:         request.Name,
+		PasswordHash: string(passwordHash),
+		Roles:        []string{auth.RoleViewer},
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	err = service.UnitOfWork.Do(ctx, func(tx repository.Tx) error {
+		_, err := service.UserRepository.FindByEmail(ctx, tx, user.Email)
+		if err == nil {
+			return exception.NewConflictError("email is already registered")
+		}
+		if !errors.Is(err, repository.ErrUserNotFound) {
+			return err
+		}
+
+		user, err = service.UserRepository.Save(ctx, tx, user)
+		return err
+	})
+	if err != nil {
+		return web.UserResponse{}, err
+	}
+
+	service.Logger.Info(ctx, "registered user", "user_id", user.Id)
+	return helper.ToUserResponse(user), nil
+}
+
+func (service *UserServiceImpl) Login(ctx context.Context, request web.UserLoginRequest) (web.TokenResponse, error) {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.TokenResponse{}, exception.FromValidator(err)
+	}
+
+	var user domain.User
+	err = service.UnitOfWork.Do(ctx, func(tx repository.Tx) (err error) {
+		user, err = service.UserRepository.FindByEmail(ctx, tx, normalizeEmail(request.Email))
+		return err
+	})
+	if err != nil && !errors.Is(err, repository.ErrUserNotFound) {
+		return web.TokenResponse{}, err
+	}
+
+	passwordHash := []byte(user.PasswordHash)
+	if user.Id == 0 {
+		passwordHash = service.dummyHash
+	}
+
+	// the hash is compared outside the transaction, it is deliberately slow
+	errCompare := bcrypt.CompareHashAndPassword(passwordHash, []byte(request.Password))
+	if user.Id == 0 || errCompare != nil {
+		return web.TokenResponse{}, exception.NewUnauthorizedError("invalid email or password")
+	}
+
+	tokens, session, err := service.newTokens(domain.Session{
+		UserId:    user.Id,
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	})
+	if err != nil {
+		return web.TokenResponse{}, err
+	}
+
+	err = service.UnitOfWork.Do(ctx, func(tx repository.Tx) error {
+		_, err := service.SessionRepository.Save(ctx, tx, session)
+		return err
+	})
+	if err != nil {
+		return web.TokenResponse{}, err
+	}
+
+	return tokens, nil
+}
+
+func (service *UserServiceImpl) Refresh(ctx context.Context, request web.UserRefreshRequest) (web.TokenResponse, error) {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.TokenResponse{}, exception.FromValidator(err)
+	}
+
+	var tokens web.TokenResponse
+	err = service.UnitOfWork.Do(ctx, func(tx repository.Tx) error {
+		session, err := service.SessionRepository.FindByRefreshTokenHash(ctx, tx, hashToken(request.RefreshToken))
+		if errors.Is(err, repository.ErrSessionNotFound) {
+			return exception.NewUnauthorizedError("invalid refresh token")
+		}
+		if err != nil {
+			return err
+		}
+
+		if session.RevokedAt != nil {
+			return exception.NewUnauthorizedError("session is revoked")
+		}
+		if time.Now().UTC().After(session.RefreshExpiresAt) {
+			return exception.NewUnauthorizedError("refresh token is expired")
+		}
+
+		// both tokens are replaced, so a refresh token works only once
+		tokens, session, err = service.newTokens(session)
+		if err != nil {
+			return err
+		}
+
+		_, err = service.SessionRepository.Update(ctx, tx, session)
+		return err
+	})
+	if err != nil {
+		return web.TokenResponse{}, err
+	}
+
+	return tokens, nil
+}
+
+func (service *UserServiceImpl) Logout(ctx context.Context, sessionId int) error {
+	return service.UnitOfWork.Do(ctx, func(tx repository.Tx) error {
+		session, err := service.SessionRepository.FindById(ctx, tx, sessionId)
+		if errors.Is(err, repository.ErrSessionNotFound) {
+			return exception.NewUnauthorizedError(err.Error())
+		}
+		if err != nil {
+			return err
+		}
+
+		if session.RevokedAt != nil {
+			return nil
+		}
+
+		now := time.Now().UTC().Truncate(time.Second)
+		session.RevokedAt = &now
+
+		_, err = service.SessionRepository.Update(ctx, tx, session)
+		return err
+	})
+}
+
+func (service *UserServiceImpl) ChangePassword(ctx context.Context, userId int, sessionId int, request web.UserChangePasswordRequest) error {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return exception.FromValidator(err)
+	}
+
+	var user domain.User
+	err = service.UnitOfWork.Do(ctx, func(tx repository.Tx) (err error) {
+		user, err = service.findUser(ctx, tx, userId)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(request.CurrentPassword))
+	if err != nil {
+		return exception.NewFieldValidationError("current_password", "current password is incorrect")
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(request.NewPassword), service.HashCost)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// every other session of the user is logged out
+	err = service.UnitOfWork.Do(ctx, func(tx repository.Tx) error {
+		user, err := service.findUser(ctx, tx, userId)
+		if err != nil {
+			return err
+		}
+
+		user.PasswordHash = string(passwordHash)
+		user.UpdatedAt = now
+
+		_, err = service.UserRepository.Update(ctx, tx, user)
+		if err != nil {
+			return err
+		}
+
+		return service.SessionRepository.RevokeByUser(ctx, tx, userId, sessionId, now)
+	})
+	if err != nil {
+		return err
+	}
+
+	service.Logger.Info(ctx, "changed password", "user_id", userId)
+	return nil
+}
+
+func (service *UserServiceImpl) Me(ctx context.Context, userId int) (web.UserResponse, error) {
+	var user domain.User
+	err := service.UnitOfWork.Do(ctx, func(tx repository.Tx) (err error) {
+		user, err = service.findUser(ctx, tx, userId)
+		return err
+	})
+	if err != nil {
+		return web.UserResponse{}, err
+	}
+
+	return helper.ToUserResponse(user), nil
+}
+
+func (service *UserServiceImpl) FindAll(ctx context.Context) ([]web.UserResponse, error) {
+	var users []domain.User
+	err := service.UnitOfWork.Do(ctx, func(tx repository.Tx) (err error) {
+		users, err = service.UserRepository.FindAll(ctx, tx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return helper.ToUserResponses(users), nil
+}
+
+func (service *UserServiceImpl) AssignRoles(ctx context.Context, userId int, request web.UserRolesRequest) (web.UserResponse, error) {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		return web.UserResponse{}, exception.FromValidator(err)
+	}
+
+	var user domain.User
+	err = service.UnitOfWork.Do(ctx, func(tx repository.Tx) (err error) {
+		user, err = service.findUser(ctx, tx, userId)
+		if err != nil {
+			return err
+		}
+
+		user.Roles = distinct(request.Roles)
+		user.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+
+		user, err = service.UserRepository.Update(ctx, tx, user)
+		return err
+	})
+	if err != nil {
+		return web.UserResponse{}, err
+	}
+
+	service.Logger.Info(ctx, "assigned roles", "user_id", user.Id, "roles", user.Roles)
+	return helper.ToUserResponse(user), nil
+}
+
+func (service *UserServiceImpl) Authenticate(ctx context.Context, accessToken string) (auth.Principal, error) {
+	var session domain.Session
+	var user domain.User
+	err := service.UnitOfWork.Do(ctx, func(tx repository.Tx) (err error) {
+		session, err = service.SessionRepository.FindByAccessTokenHash(ctx, tx, hashToken(accessToken))
+		if err != nil {
+			return err
+		}
+
+		user, err = service.UserRepository.FindById(ctx, tx, session.UserId)
+		return err
+	})
+	if errors.Is(err, repository.ErrSessionNotFound) || errors.Is(err, repository.ErrUserNotFound) {
+		return auth.Principal{}, exception.NewUnauthorizedError("invalid access token")
+	}
+	if err != nil {
+		return auth.Principal{}, err
+	}
+
+	if session.RevokedAt != nil {
+		return auth.Principal{}, exception.NewUnauthorizedError("session is revoked")
+	}
+	if time.Now().UTC().After(session.AccessExpiresAt) {
+		return auth.Principal{}, exception.NewUnauthorizedError("access token is expired")
+	}
+
+	return auth.Principal{
+		Type:      auth.PrincipalUser,
+		Id:        strconv.Itoa(user.Id),
+		Name:      user.Name,
+		Roles:     user.Roles,
+		Scopes:    auth.RolePermissions(user.Roles),
+		SessionId: strconv.Itoa(session.Id),
+	}, nil
+}
+
+// newTokens generates a fresh token pair for session and returns it along
+// with the session holding their hashes and expiry times.
+func (service *UserServiceImpl) newTokens(session domain.Session) (web.TokenResponse, domain.Session, error) {
+	accessToken, err := randomHex(32)
+	if err != nil {
+		return web.TokenResponse{}, session, err
+	}
+
+	refreshToken, err := randomHex(32)
+	if err != nil {
+		return web.TokenResponse{}, session, err
+	}
+
+	accessToken = AccessTokenPrefix + accessToken
+	refreshToken = RefreshTokenPrefix + refreshToken
+
+	now := time.Now().UTC().Truncate(time.Second)
+	session.AccessTokenHash = hashToken(accessToken)
+	session.RefreshTokenHash = hashToken(refreshToken)
+	session.AccessExpiresAt = now.Add(service.SessionConfig.AccessTokenTTL)
+	session.RefreshExpiresAt = now.Add(service.SessionConfig.RefreshTokenTTL)
+
+	return web.TokenResponse{
+		TokenType:        "Bearer",
+		AccessToken:      accessToken,
+		ExpiresIn:        int(service.SessionConfig.AccessTokenTTL / time.Second),
+		RefreshToken:     refreshToken,
+		RefreshExpiresIn: int(service.SessionConfig.RefreshTokenTTL / time.Second),
+	}, session, nil
+}
+
+func (service *UserServiceImpl) findUser(ctx context.Context, tx repository.Tx, userId int) (domain.User, error) {
+	user, err := service.UserRepository.FindById(ctx, tx, userId)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		return user, exception.NewNotFoundError(err.Error())
+	}
+
+	return user, err
+}
+
+// hashToken returns the SHA-256 of a session token. Tokens carry 256 bits
+// of randomness, so unlike passwords they need no slow hash.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func distinct(values []string) []string {
+	seen := map[string]bool{}
+	var result []string
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			result = append(result, value)
+		}
+	}
+
+	return result
+}
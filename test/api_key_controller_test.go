@@ -0,0 +1,121 @@
+package test
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sudutkampus/gorestfulapi/auth"
+)
+
+func issueApiKey(router http.Handler, scopes string) (int, string) {
+	_, responseBody := doRequest(router, http.MethodPost, "/api/api-keys", apiKeyHeader("RAHASIA"), `{"name": "frontend", "scopes": [`+scopes+`]}`)
+	data := responseBody["data"].(map[string]interface{})
+
+	return int(data["id"].(float64)), data["key"].(string)
+}
+
+func TestIssueApiKeySuccess(t *testing.T) {
+	storage := setupTestStorage()
+	router := setupRouter(storage)
+
+	_, responseBody := doRequest(router, http.MethodPost, "/api/api-keys", apiKeyHeader("RAHASIA"), `{"name": "frontend", "scopes": ["categories:read"]}`)
+	data := responseBody["data"].(map[string]interface{})
+
+	assert.Equal(t, http.StatusOK, int(responseBody["code"].(float64)))
+	assert.Equal(t, "frontend", data["name"])
+	assert.Equal(t, []interface{}{"categories:read"}, data["scopes"])
+	assert.True(t, strings.HasPrefix(data["key"].(string), data["prefix"].(string)+"."))
+
+	_, responseBody = doRequest(router, http.MethodGet, "/api/api-keys", apiKeyHeader("RAHASIA"), "")
+	apiKeys := responseBody["data"].([]interface{})
+
+	assert.NotEmpty(t, apiKeys)
+	assert.Nil(t, apiKeys[len(apiKeys)-1].(map[string]interface{})["key"])
+}
+
+func TestIssueApiKeyFailed(t *testing.T) {
+	storage := setupTestStorage()
+	router := setupRouter(storage)
+
+	_, responseBody := doRequest(router, http.MethodPost, "/api/api-keys", apiKeyHeader("RAHASIA"), `{"name": "frontend", "scopes": ["categories:read", "everything"]}`)
+	assert.Equal(t, http.StatusBadRequest, int(responseBody["code"].(float64)))
+	fieldError := responseBody["data"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "scopes[1]", fieldError["field"])
+	assert.Equal(t, "scope", fieldError["tag"])
+	assert.Equal(t, "scopes[1] must be one of ["+strings.Join(auth.Scopes(), " ")+"]", fieldError["message"])
+
+	_, responseBody = doRequest(router, http.MethodPost, "/api/api-keys", apiKeyHeader("RAHASIA"), `{"name": "frontend", "scopes": ["*"]}`)
+	assert.Equal(t, http.StatusBadRequest, int(responseBody["code"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodPost, "/api/api-keys", apiKeyHeader("RAHASIA"), `{"name": "frontend", "scopes": ["categories:read"], "expires_at": "2020-01-01T00:00:00Z"}`)
+	assert.Equal(t, http.StatusBadRequest, int(responseBody["code"].(float64)))
+}
+
+func TestApiKeyScopes(t *testing.T) {
+	storage := setupTestStorage()
+	router := setupRouter(storage)
+	id, key := issueApiKey(router, `"categories:read"`)
+
+	_, responseBody := doRequest(router, http.MethodGet, "/api/categories", apiKeyHeader(key), "")
+	assert.Equal(t, http.StatusOK, int(responseBody["code"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodPost, "/api/categories", apiKeyHeader(key), `{"name": "Gadget"}`)
+	assert.Equal(t, http.StatusForbidden, int(responseBody["code"].(float64)))
+	assert.Equal(t, "Forbidden", responseBody["status"])
+
+	_, responseBody = doRequest(router, http.MethodGet, "/api/api-keys", apiKeyHeader(key), "")
+	assert.Equal(t, http.StatusForbidden, int(responseBody["code"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodGet, "/api/api-keys", apiKeyHeader("RAHASIA"), "")
+	for _, apiKey := range responseBody["data"].([]interface{}) {
+		if int(apiKey.(map[string]interface{})["id"].(float64)) == id {
+			assert.NotNil(t, apiKey.(map[string]interface{})["last_used_at"])
+		}
+	}
+}
+
+func TestRotateApiKey(t *testing.T) {
+	storage := setupTestStorage()
+	router := setupRouter(storage)
+	id, key := issueApiKey(router, `"categories:read"`)
+
+	// a key verified a moment ago must still stop working once rotated
+	_, responseBody := doRequest(router, http.MethodGet, "/api/categories", apiKeyHeader(key), "")
+	assert.Equal(t, http.StatusOK, int(responseBody["code"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodPost, "/api/api-keys/"+strconv.Itoa(id)+"/rotate", apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, http.StatusOK, int(responseBody["code"].(float64)))
+	rotatedKey := responseBody["data"].(map[string]interface{})["key"].(string)
+
+	_, responseBody = doRequest(router, http.MethodGet, "/api/categories", apiKeyHeader(key), "")
+	assert.Equal(t, http.StatusUnauthorized, int(responseBody["code"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodGet, "/api/categories", apiKeyHeader(rotatedKey), "")
+	assert.Equal(t, http.StatusOK, int(responseBody["code"].(float64)))
+}
+
+func TestRevokeApiKey(t *testing.T) {
+	storage := setupTestStorage()
+	router := setupRouter(storage)
+	id, key := issueApiKey(router, `"categories:read"`)
+
+	// a key verified a moment ago must still stop working once revoked
+	_, responseBody := doRequest(router, http.MethodGet, "/api/categories", apiKeyHeader(key), "")
+	assert.Equal(t, http.StatusOK, int(responseBody["code"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodDelete, "/api/api-keys/"+strconv.Itoa(id), apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, http.StatusOK, int(responseBody["code"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodGet, "/api/categories", apiKeyHeader(key), "")
+	assert.Equal(t, http.StatusUnauthorized, int(responseBody["code"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodPost, "/api/api-keys/"+strconv.Itoa(id)+"/rotate", apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, http.StatusConflict, int(responseBody["code"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodDelete, "/api/api-keys/99999", apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, http.StatusNotFound, int(responseBody["code"].(float64)))
+}
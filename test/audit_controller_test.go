@@ -0,0 +1,82 @@
+package test
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func auditEntries(router http.Handler, query string) []map[string]interface{} {
+	_, responseBody := doRequest(router, http.MethodGet, "/api/audit"+query, apiKeyHeader("RAHASIA"), "")
+
+	var entries []map[string]interface{}
+	data, _ := responseBody["data"].([]interface{})
+	for _, entry := range data {
+		entries = append(entries, entry.(map[string]interface{}))
+	}
+
+	return entries
+}
+
+func TestAuditCategoryChanges(t *testing.T) {
+	storage := setupTestStorage()
+	truncateCategory(storage)
+	router := setupRouter(storage)
+
+	recorder, responseBody := doRequest(router, http.MethodPost, "/api/categories", map[string]string{"X-API-Key": "RAHASIA", "X-Request-ID": "req-create"}, `{"name": "Gadget"}`)
+	assert.Equal(t, "req-create", recorder.Header().Get("X-Request-ID"))
+	id := strconv.Itoa(int(responseBody["data"].(map[string]interface{})["id"].(float64)))
+
+	doRequest(router, http.MethodPut, "/api/categories/"+id, apiKeyHeader("RAHASIA"), `{"name": "Gadgets"}`)
+	doRequest(router, http.MethodDelete, "/api/categories/"+id, apiKeyHeader("RAHASIA"), "")
+
+	entries := auditEntries(router, "?entity=category&id="+id)
+	assert.Len(t, entries, 3)
+	assert.Equal(t, "delete", entries[0]["action"])
+	assert.Equal(t, "update", entries[1]["action"])
+	assert.Equal(t, "create", entries[2]["action"])
+	assert.Equal(t, "bootstrap", entries[2]["actor"])
+	assert.Equal(t, "req-create", entries[2]["request_id"])
+	assert.Nil(t, entries[2]["before"])
+	assert.Equal(t, "Gadget", entries[1]["before"].(map[string]interface{})["name"])
+	assert.Equal(t, "Gadgets", entries[1]["after"].(map[string]interface{})["name"])
+	assert.NotNil(t, entries[0]["after"].(map[string]interface{})["deleted_at"])
+
+	entries = auditEntries(router, "?entity=category&id="+id+"&action=update")
+	assert.Len(t, entries, 1)
+
+	_, responseBody = doRequest(router, http.MethodGet, "/api/audit?per_page=1&page=2", apiKeyHeader("RAHASIA"), "")
+	assert.Len(t, responseBody["data"], 1)
+	assert.Equal(t, 3, int(responseBody["meta"].(map[string]interface{})["total"].(float64)))
+}
+
+func TestAuditRolledBackChange(t *testing.T) {
+	storage := setupTestStorage()
+	truncateCategory(storage)
+	router := setupRouter(storage)
+
+	code, _ := bulkCategories(router, `{"operations": [
+		{"op": "create", "name": "Laptop"},
+		{"op": "update", "id": 99999, "name": "Tablet"}
+	]}`)
+	assert.Equal(t, http.StatusNotFound, code)
+
+	assert.Empty(t, auditEntries(router, "?entity=category"))
+}
+
+func TestFindAuditFailed(t *testing.T) {
+	storage := setupTestStorage()
+	router := setupRouter(storage)
+	_, viewerKey := issueApiKey(router, `"categories:read"`)
+
+	_, responseBody := doRequest(router, http.MethodGet, "/api/audit", apiKeyHeader(viewerKey), "")
+	assert.Equal(t, http.StatusForbidden, int(responseBody["code"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodGet, "/api/audit?since=yesterday", apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, http.StatusBadRequest, int(responseBody["code"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodGet, "/api/audit?entity=order", apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, http.StatusBadRequest, int(responseBody["code"].(float64)))
+}
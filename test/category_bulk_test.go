@@ -0,0 +1,119 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func bulkCategories(router http.Handler, body string) (int, []map[string]interface{}) {
+	recorder, _ := doRequest(router, http.MethodPost, "/api/categories/bulk", apiKeyHeader("RAHASIA"), body)
+
+	var responseBody struct {
+		Data []map[string]interface{} `json:"data"`
+	}
+	json.Unmarshal(recorder.Body.Bytes(), &responseBody)
+
+	return recorder.Code, responseBody.Data
+}
+
+func TestBulkCategoryAtomic(t *testing.T) {
+	storage := setupTestStorage()
+	truncateCategory(storage)
+	router := setupRouter(storage)
+	category := createCategory(storage, "Gadget")
+	id := strconv.Itoa(category.Id)
+
+	code, results := bulkCategories(router, `{"operations": [
+		{"op": "create", "name": "Laptop"},
+		{"op": "create", "name": "Handphone"},
+		{"op": "update", "id": `+id+`, "name": "Tablet", "version": 1}
+	]}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, results, 3)
+	first := results[0]["data"].(map[string]interface{})
+	second := results[1]["data"].(map[string]interface{})
+	assert.Equal(t, "Laptop", first["name"])
+	assert.Equal(t, first["id"].(float64)+1, second["id"].(float64))
+	assert.Equal(t, "Tablet", results[2]["data"].(map[string]interface{})["name"])
+
+	_, responseBody := doRequest(router, http.MethodGet, "/api/categories/"+strconv.Itoa(int(second["id"].(float64))), apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, "Handphone", responseBody["data"].(map[string]interface{})["name"])
+
+	// a stale version rolls back the creates before it
+	code, results = bulkCategories(router, `{"mode": "atomic", "operations": [
+		{"op": "create", "name": "Camera"},
+		{"op": "delete", "id": `+id+`, "version": 1},
+		{"op": "create", "name": "Printer"}
+	]}`)
+	assert.Equal(t, http.StatusPreconditionFailed, code)
+	assert.Equal(t, http.StatusFailedDependency, int(results[0]["code"].(float64)))
+	assert.Nil(t, results[0]["data"])
+	assert.Equal(t, http.StatusPreconditionFailed, int(results[1]["code"].(float64)))
+	assert.Equal(t, http.StatusFailedDependency, int(results[2]["code"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodGet, "/api/categories?q=Camera", apiKeyHeader("RAHASIA"), "")
+	assert.Empty(t, responseBody["data"])
+}
+
+func TestBulkCategoryPartial(t *testing.T) {
+	storage := setupTestStorage()
+	truncateCategory(storage)
+	router := setupRouter(storage)
+	category := createCategory(storage, "Gadget")
+
+	code, results := bulkCategories(router, `{"mode": "partial", "operations": [
+		{"op": "create", "name": "Laptop"},
+		{"op": "create", "name": ""},
+		{"op": "update", "id": 99999, "name": "Tablet"},
+		{"op": "delete", "id": `+strconv.Itoa(category.Id)+`}
+	]}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, http.StatusOK, int(results[0]["code"].(float64)))
+	assert.Equal(t, http.StatusBadRequest, int(results[1]["code"].(float64)))
+	assert.Equal(t, "name", results[1]["error"].([]interface{})[0].(map[string]interface{})["field"])
+	assert.Equal(t, http.StatusNotFound, int(results[2]["code"].(float64)))
+	assert.Equal(t, http.StatusOK, int(results[3]["code"].(float64)))
+
+	_, responseBody := doRequest(router, http.MethodGet, "/api/categories", apiKeyHeader("RAHASIA"), "")
+	categories := responseBody["data"].([]interface{})
+	assert.Len(t, categories, 1)
+	assert.Equal(t, "Laptop", categories[0].(map[string]interface{})["name"])
+}
+
+func TestBulkCategoryInvalid(t *testing.T) {
+	storage := setupTestStorage()
+	router := setupRouter(storage)
+
+	code, _ := bulkCategories(router, `{"operations": []}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = bulkCategories(router, `{"mode": "sometimes", "operations": [{"op": "create", "name": "Laptop"}]}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = bulkCategories(router, `{"operations": [{"op": "upsert", "name": "Laptop"}]}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	recorder, _ := doRequest(router, http.MethodPost, "/api/categories/1", apiKeyHeader("RAHASIA"), `{}`)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestBulkCategoryRequireVersion(t *testing.T) {
+	storage := setupTestStorage()
+	truncateCategory(storage)
+	cfg := setupTestConfig()
+	cfg.Concurrency.RequireIfMatch = true
+	router := setupRouterWithConfig(storage, cfg)
+	category := createCategory(storage, "Gadget")
+
+	code, results := bulkCategories(router, `{"mode": "partial", "operations": [
+		{"op": "create", "name": "Laptop"},
+		{"op": "update", "id": `+strconv.Itoa(category.Id)+`, "name": "Tablet"}
+	]}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, http.StatusOK, int(results[0]["code"].(float64)))
+	assert.Equal(t, http.StatusPreconditionRequired, int(results[1]["code"].(float64)))
+}
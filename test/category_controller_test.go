@@ -2,60 +2,192 @@ package test
 
 import (
 	"context"
-	"database/sql"
 	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"strconv"
 	"strings"
 	"testing"
-	"time"
 
 	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/crypto/bcrypt"
 
 	"sudutkampus/gorestfulapi/app"
+	"sudutkampus/gorestfulapi/auth"
+	"sudutkampus/gorestfulapi/config"
 	"sudutkampus/gorestfulapi/controller"
 	"sudutkampus/gorestfulapi/helper"
+	"sudutkampus/gorestfulapi/logging"
+	"sudutkampus/gorestfulapi/metrics"
 	"sudutkampus/gorestfulapi/middleware"
+	"sudutkampus/gorestfulapi/migration"
 	"sudutkampus/gorestfulapi/model/domain"
+	"sudutkampus/gorestfulapi/ratelimit"
 	"sudutkampus/gorestfulapi/repository"
 	"sudutkampus/gorestfulapi/service"
-
-	"github.com/go-playground/validator/v10"
+	"sudutkampus/gorestfulapi/tracing"
 )
 
-func setupTestDB() *sql.DB {
-	db, err := sql.Open("mysql", "root:root@tcp(localhost:8889)/gorestfulapitest")
+// setupTestConfig uses the in-memory backend unless TEST_DATABASE_DRIVER and
+// TEST_DATABASE_DSN point the suite at a real database.
+func setupTestConfig() config.Config {
+	cfg := config.Default()
+	cfg.Database.Driver = config.DriverMemory
+	cfg.Database.DSN = ""
+	if driver := os.Getenv("TEST_DATABASE_DRIVER"); driver != "" {
+		cfg.Database.Driver = driver
+		cfg.Database.DSN = os.Getenv("TEST_DATABASE_DSN")
+	}
+	cfg.Auth.APIKey = "RAHASIA"
+	cfg.Auth.APIKeyHashCost = bcrypt.MinCost
+	cfg.Auth.PasswordHashCost = bcrypt.MinCost
+	cfg.Auth.JWT.HMACSecret = testJWTSecret
+	cfg.Auth.JWT.Issuer = "https://auth.sudutkampus.test"
+	cfg.Auth.JWT.Audience = "gorestfulapi"
+	cfg.RateLimit.Enabled = false
+
+	return cfg
+}
+
+func setupTestStorage() *app.Storage {
+	return setupTestStorageWithTracer(tracing.Noop())
+}
+
+func setupTestStorageWithTracer(tracer trace.Tracer) *app.Storage {
+	cfg := setupTestConfig()
+	storage := app.NewStorage(cfg.Database, logging.Discard(), tracer)
+
+	if storage.DB != nil {
+		migrator, err := migration.NewMigrator(storage.DB, cfg.Database.Driver)
+		helper.PanicIfError(err)
+		_, err = migrator.Up(context.Background())
+		helper.PanicIfError(err)
+	}
+
+	return storage
+}
+
+func setupRouter(storage *app.Storage) http.Handler {
+	return setupRouterWithConfig(storage, setupTestConfig())
+}
+
+func setupRouterWithConfig(storage *app.Storage, cfg config.Config) http.Handler {
+	return setupRouterWithLogger(storage, cfg, logging.Discard())
+}
+
+func setupRouterWithLogger(storage *app.Storage, cfg config.Config, logger *logging.Logger) http.Handler {
+	return setupRouterWithTracer(storage, cfg, logger, tracing.Noop())
+}
+
+func setupRouterWithTracer(storage *app.Storage, cfg config.Config, logger *logging.Logger, tracer trace.Tracer) http.Handler {
+	validate := app.NewValidator()
+	categoryService := service.NewCategoryServiceTraced(service.NewCategoryService(storage.CategoryRepository, storage.ProductRepository, storage.AuditLogRepository, storage.UnitOfWork, validate, cfg.Hierarchy, logger), tracer)
+	categoryController := controller.NewCategoryController(categoryService, cfg.Concurrency, logger)
+	productService := service.NewProductService(storage.ProductRepository, storage.CategoryRepository, storage.UnitOfWork, validate, logger)
+	productController := controller.NewProductController(productService)
+	auditService := service.NewAuditService(storage.AuditLogRepository, storage.UnitOfWork, validate, logger)
+	auditController := controller.NewAuditController(auditService)
+	apiKeyService := service.NewApiKeyService(storage.ApiKeyRepository, storage.UnitOfWork, validate, cfg.Auth.APIKeyHashCost, logger)
+	apiKeyController := controller.NewApiKeyController(apiKeyService)
+	userService := service.NewUserService(storage.UserRepository, storage.SessionRepository, storage.UnitOfWork, validate, cfg.Auth.PasswordHashCost, cfg.Auth.Session, logger)
+	userController := controller.NewUserController(userService)
+	rateLimitStore := ratelimit.NewMemoryStore()
+	router := app.NewRouter(app.RouterDependencies{
+		Config:         cfg.Router,
+		RateLimit:      cfg.RateLimit,
+		RateLimitStore: rateLimitStore,
+		Logger:         logger,
+		Tracer:         tracer,
+	}, app.Controllers{
+		CategoryController: categoryController,
+		ProductController:  productController,
+		AuditController:    auditController,
+		ApiKeyController:   apiKeyController,
+		UserController:     userController,
+	})
+
+	jwtVerifier, err := auth.NewJWTVerifier(cfg.Auth.JWT)
 	helper.PanicIfError(err)
 
-	db.SetMaxIdleConns(5)
-	db.SetMaxOpenConns(20)
-	db.SetConnMaxIdleTime(10 * time.Minute)
-	db.SetConnMaxLifetime(60 * time.Minute)
+	var handler http.Handler = middleware.NewAuthMiddleware(router, cfg.Auth, cfg.RateLimit, rateLimitStore, apiKeyService, userService, jwtVerifier, logger)
+	if cfg.Log.AccessLog {
+		handler = middleware.NewAccessLogMiddleware(handler, logger)
+	}
+
+	healthChecker, err := app.NewHealthChecker(storage, cfg.Database, cfg.Health, app.NewLifecycle(), logger)
+	helper.PanicIfError(err)
+
+	endpoints := healthChecker.Endpoints()
+	if cfg.Metrics.Enabled {
+		appMetrics := metrics.New(storage.DB)
+		handler = middleware.NewMetricsMiddleware(handler, appMetrics)
+		endpoints[cfg.Metrics.Path] = appMetrics.Handler()
+	}
+
+	handler = middleware.NewTracingMiddleware(handler, tracer)
 
-	return db
+	return app.NewHandler(middleware.NewRequestIdMiddleware(handler), endpoints)
 }
 
-func setupRouter(db *sql.DB) http.Handler {
-	validate := validator.New()
-	categoryRepository := repository.NewCategoryRepository()
-	categoryService := service.NewCategoryService(categoryRepository, db, validate)
-	categoryController := controller.NewCategoryController(categoryService)
-	router := app.NewRouter(categoryController)
+// doRequest sends a JSON request with the given headers to router and
+// returns the recorded response along with its body decoded as JSON.
+func doRequest(router http.Handler, method string, target string, headers map[string]string, body string) (*httptest.ResponseRecorder, map[string]interface{}) {
+	var requestBody io.Reader
+	if body != "" {
+		requestBody = strings.NewReader(body)
+	}
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(method, "http://localhost:3000"+target, requestBody)
+	request.Header.Add("Content-Type", "application/json")
+	for name, value := range headers {
+		request.Header.Set(name, value)
+	}
+
+	router.ServeHTTP(recorder, request)
+
+	var responseBody map[string]interface{}
+	json.Unmarshal(recorder.Body.Bytes(), &responseBody)
 
-	return middleware.NewAuthMiddleware(router)
+	return recorder, responseBody
 }
 
-func truncateCategory(db *sql.DB) {
-	db.Exec("TRUNCATE categories")
+func apiKeyHeader(key string) map[string]string {
+	return map[string]string{"X-API-Key": key}
+}
+
+func bearerHeader(token string) map[string]string {
+	return map[string]string{"Authorization": "Bearer " + token}
+}
+
+func truncateCategory(storage *app.Storage) {
+	if storage.DB != nil {
+		storage.DB.Exec("DELETE FROM audit_logs")
+		storage.DB.Exec("DELETE FROM products")
+		storage.DB.Exec("DELETE FROM categories")
+	}
+}
+
+func createCategory(storage *app.Storage, name string) domain.Category {
+	var category domain.Category
+	err := storage.UnitOfWork.Do(context.Background(), func(tx repository.Tx) (err error) {
+		category, err = storage.CategoryRepository.Save(context.Background(), tx, domain.Category{Name: name})
+		return err
+	})
+	helper.PanicIfError(err)
+
+	return category
 }
 
 func TestCreateCategorySuccess(t *testing.T) {
-	db := setupTestDB()
-	truncateCategory(db)
-	router := setupRouter(db)
+	storage := setupTestStorage()
+	truncateCategory(storage)
+	router := setupRouter(storage)
 
 	requestBody := strings.NewReader(`{"name": "Gadget"}`)
 	recorder := httptest.NewRecorder()
@@ -76,9 +208,9 @@ func TestCreateCategorySuccess(t *testing.T) {
 }
 
 func TestCreateCategoryFailed(t *testing.T) {
-	db := setupTestDB()
-	truncateCategory(db)
-	router := setupRouter(db)
+	storage := setupTestStorage()
+	truncateCategory(storage)
+	router := setupRouter(storage)
 
 	requestBody := strings.NewReader(`{"name": ""}`)
 	recorder := httptest.NewRecorder()
@@ -95,21 +227,45 @@ func TestCreateCategoryFailed(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, int(responseBody["code"].(float64)))
 	assert.Equal(t, "Bad Request", responseBody["status"])
+
+	fieldError := responseBody["data"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "name", fieldError["field"])
+	assert.Equal(t, "required", fieldError["tag"])
+	assert.Equal(t, "name is a required field", fieldError["message"])
 }
 
-func TestUpdateCategorySuccess(t *testing.T) {
-	db := setupTestDB()
-	truncateCategory(db)
+func TestCreateCategoryFailedTranslated(t *testing.T) {
+	storage := setupTestStorage()
+	truncateCategory(storage)
+	router := setupRouter(storage)
 
-	tx, err := db.Begin()
+	requestBody := strings.NewReader(`{"name": ""}`)
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:3000/api/categories", requestBody)
+	request.Header.Add("Content-Type", "application/json")
+	request.Header.Add("Accept-Language", "id-ID,id;q=0.9,en;q=0.8")
+	request.Header.Add("X-API-Key", "RAHASIA")
+
+	router.ServeHTTP(recorder, request)
+
+	var responseBody map[string]interface{}
+	body, err := io.ReadAll(recorder.Result().Body)
 	helper.PanicIfError(err)
-	categoryRepository := repository.NewCategoryRepository()
-	newCategory := categoryRepository.Save(context.Background(), tx, domain.Category{
-		Name: "Gadget",
-	})
-	tx.Commit()
+	json.Unmarshal(body, &responseBody)
+
+	fieldError := responseBody["data"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, http.StatusBadRequest, int(responseBody["code"].(float64)))
+	assert.Equal(t, "name", fieldError["field"])
+	assert.Equal(t, "name wajib diisi", fieldError["message"])
+}
+
+func TestUpdateCategorySuccess(t *testing.T) {
+	storage := setupTestStorage()
+	truncateCategory(storage)
+
+	newCategory := createCategory(storage, "Gadget")
 
-	router := setupRouter(db)
+	router := setupRouter(storage)
 
 	requestBody := strings.NewReader(`{"name": "Gadget Update"}`)
 	recorder := httptest.NewRecorder()
@@ -131,18 +287,12 @@ func TestUpdateCategorySuccess(t *testing.T) {
 }
 
 func TestUpdateCategoryFailed(t *testing.T) {
-	db := setupTestDB()
-	truncateCategory(db)
+	storage := setupTestStorage()
+	truncateCategory(storage)
 
-	tx, err := db.Begin()
-	helper.PanicIfError(err)
-	categoryRepository := repository.NewCategoryRepository()
-	newCategory := categoryRepository.Save(context.Background(), tx, domain.Category{
-		Name: "Gadget",
-	})
-	tx.Commit()
+	newCategory := createCategory(storage, "Gadget")
 
-	router := setupRouter(db)
+	router := setupRouter(storage)
 
 	requestBody := strings.NewReader(`{"name": ""}`)
 	recorder := httptest.NewRecorder()
@@ -162,18 +312,12 @@ func TestUpdateCategoryFailed(t *testing.T) {
 }
 
 func TestGetOneCategorySuccess(t *testing.T) {
-	db := setupTestDB()
-	truncateCategory(db)
+	storage := setupTestStorage()
+	truncateCategory(storage)
 
-	tx, err := db.Begin()
-	helper.PanicIfError(err)
-	categoryRepository := repository.NewCategoryRepository()
-	newCategory := categoryRepository.Save(context.Background(), tx, domain.Category{
-		Name: "Gadget",
-	})
-	tx.Commit()
+	newCategory := createCategory(storage, "Gadget")
 
-	router := setupRouter(db)
+	router := setupRouter(storage)
 
 	recorder := httptest.NewRecorder()
 	request := httptest.NewRequest(http.MethodGet, "http://localhost:3000/api/categories/"+strconv.Itoa(newCategory.Id), nil)
@@ -194,10 +338,10 @@ func TestGetOneCategorySuccess(t *testing.T) {
 }
 
 func TestGetOneCategoryFailed(t *testing.T) {
-	db := setupTestDB()
-	truncateCategory(db)
+	storage := setupTestStorage()
+	truncateCategory(storage)
 
-	router := setupRouter(db)
+	router := setupRouter(storage)
 
 	recorder := httptest.NewRecorder()
 	request := httptest.NewRequest(http.MethodGet, "http://localhost:3000/api/categories/1", nil)
@@ -216,18 +360,12 @@ func TestGetOneCategoryFailed(t *testing.T) {
 }
 
 func TestDeleteCategorySuccess(t *testing.T) {
-	db := setupTestDB()
-	truncateCategory(db)
+	storage := setupTestStorage()
+	truncateCategory(storage)
 
-	tx, err := db.Begin()
-	helper.PanicIfError(err)
-	categoryRepository := repository.NewCategoryRepository()
-	newCategory := categoryRepository.Save(context.Background(), tx, domain.Category{
-		Name: "Gadget",
-	})
-	tx.Commit()
+	newCategory := createCategory(storage, "Gadget")
 
-	router := setupRouter(db)
+	router := setupRouter(storage)
 
 	recorder := httptest.NewRecorder()
 	request := httptest.NewRequest(http.MethodDelete, "http://localhost:3000/api/categories/"+strconv.Itoa(newCategory.Id), nil)
@@ -246,10 +384,10 @@ func TestDeleteCategorySuccess(t *testing.T) {
 }
 
 func TestDeleteCategoryFailed(t *testing.T) {
-	db := setupTestDB()
-	truncateCategory(db)
+	storage := setupTestStorage()
+	truncateCategory(storage)
 
-	router := setupRouter(db)
+	router := setupRouter(storage)
 
 	recorder := httptest.NewRecorder()
 	request := httptest.NewRequest(http.MethodDelete, "http://localhost:3000/api/categories/1", nil)
@@ -268,18 +406,12 @@ func TestDeleteCategoryFailed(t *testing.T) {
 }
 
 func TestGetAllCategorySuccess(t *testing.T) {
-	db := setupTestDB()
-	truncateCategory(db)
+	storage := setupTestStorage()
+	truncateCategory(storage)
 
-	tx, err := db.Begin()
-	helper.PanicIfError(err)
-	categoryRepository := repository.NewCategoryRepository()
-	newCategory := categoryRepository.Save(context.Background(), tx, domain.Category{
-		Name: "Gadget",
-	})
-	tx.Commit()
+	newCategory := createCategory(storage, "Gadget")
 
-	router := setupRouter(db)
+	router := setupRouter(storage)
 
 	recorder := httptest.NewRecorder()
 	request := httptest.NewRequest(http.MethodGet, "http://localhost:3000/api/categories", nil)
@@ -303,11 +435,84 @@ func TestGetAllCategorySuccess(t *testing.T) {
 	assert.Equal(t, newCategory.Name, categoryResponse["name"])
 }
 
+func TestGetAllCategoryPagination(t *testing.T) {
+	storage := setupTestStorage()
+	truncateCategory(storage)
+
+	createCategory(storage, "Gadget")
+	createCategory(storage, "Fashion")
+	createCategory(storage, "Food")
+
+	router := setupRouter(storage)
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:3000/api/categories?per_page=2&sort=name", nil)
+	request.Header.Add("Content-Type", "application/json")
+	request.Header.Add("X-API-Key", "RAHASIA")
+
+	router.ServeHTTP(recorder, request)
+
+	var responseBody map[string]interface{}
+	body, err := io.ReadAll(recorder.Result().Body)
+	helper.PanicIfError(err)
+	json.Unmarshal(body, &responseBody)
+
+	var categories = responseBody["data"].([]interface{})
+	var meta = responseBody["meta"].(map[string]interface{})
+	var links = meta["links"].(map[string]interface{})
+
+	assert.Equal(t, http.StatusOK, int(responseBody["code"].(float64)))
+	assert.Equal(t, 2, len(categories))
+	assert.Equal(t, "Fashion", categories[0].(map[string]interface{})["name"])
+	assert.Equal(t, "Food", categories[1].(map[string]interface{})["name"])
+	assert.Equal(t, 3, int(meta["total"].(float64)))
+	assert.Equal(t, "/api/categories?page=2&per_page=2&sort=name", links["next"])
+	assert.Nil(t, links["prev"])
+
+	recorder = httptest.NewRecorder()
+	request = httptest.NewRequest(http.MethodGet, "http://localhost:3000/api/categories?per_page=2&sort=name&q=ad&after="+meta["next_cursor"].(string), nil)
+	request.Header.Add("X-API-Key", "RAHASIA")
+
+	router.ServeHTTP(recorder, request)
+
+	body, err = io.ReadAll(recorder.Result().Body)
+	helper.PanicIfError(err)
+	json.Unmarshal(body, &responseBody)
+
+	categories = responseBody["data"].([]interface{})
+
+	assert.Equal(t, http.StatusOK, int(responseBody["code"].(float64)))
+	assert.Equal(t, 1, len(categories))
+	assert.Equal(t, "Gadget", categories[0].(map[string]interface{})["name"])
+}
+
+func TestGetAllCategoryInvalidQuery(t *testing.T) {
+	storage := setupTestStorage()
+	truncateCategory(storage)
+
+	router := setupRouter(storage)
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "http://localhost:3000/api/categories?sort=created", nil)
+	request.Header.Add("Content-Type", "application/json")
+	request.Header.Add("X-API-Key", "RAHASIA")
+
+	router.ServeHTTP(recorder, request)
+
+	var responseBody map[string]interface{}
+	body, err := io.ReadAll(recorder.Result().Body)
+	helper.PanicIfError(err)
+	json.Unmarshal(body, &responseBody)
+
+	assert.Equal(t, http.StatusBadRequest, int(responseBody["code"].(float64)))
+	assert.Equal(t, "Bad Request", responseBody["status"])
+}
+
 func TestUnauthorized(t *testing.T) {
-	db := setupTestDB()
-	truncateCategory(db)
+	storage := setupTestStorage()
+	truncateCategory(storage)
 
-	router := setupRouter(db)
+	router := setupRouter(storage)
 
 	recorder := httptest.NewRecorder()
 	request := httptest.NewRequest(http.MethodGet, "http://localhost:3000/api/categories", nil)
@@ -0,0 +1,92 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sudutkampus/gorestfulapi/model/domain"
+	"sudutkampus/gorestfulapi/repository"
+)
+
+func TestCategoryETag(t *testing.T) {
+	storage := setupTestStorage()
+	truncateCategory(storage)
+	router := setupRouter(storage)
+	category := createCategory(storage, "Gadget")
+	target := "/api/categories/" + strconv.Itoa(category.Id)
+
+	recorder, _ := doRequest(router, http.MethodGet, target, apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, `"1"`, recorder.Header().Get("ETag"))
+
+	recorder, _ = doRequest(router, http.MethodGet, target, map[string]string{"X-API-Key": "RAHASIA", "If-None-Match": `W/"1"`}, "")
+	assert.Equal(t, http.StatusNotModified, recorder.Code)
+	assert.Empty(t, recorder.Body.String())
+
+	recorder, _ = doRequest(router, http.MethodPut, target, map[string]string{"X-API-Key": "RAHASIA", "If-Match": `"1"`}, `{"name": "Handphone"}`)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, `"2"`, recorder.Header().Get("ETag"))
+
+	// the first writer won, a second client still holding version 1 loses
+	recorder, _ = doRequest(router, http.MethodPut, target, map[string]string{"X-API-Key": "RAHASIA", "If-Match": `"1"`}, `{"name": "Laptop"}`)
+	assert.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+
+	recorder, _ = doRequest(router, http.MethodGet, target, map[string]string{"X-API-Key": "RAHASIA", "If-None-Match": `"1"`}, "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "Handphone")
+
+	recorder, _ = doRequest(router, http.MethodDelete, target, map[string]string{"X-API-Key": "RAHASIA", "If-Match": `"1"`}, "")
+	assert.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+
+	recorder, _ = doRequest(router, http.MethodDelete, target, map[string]string{"X-API-Key": "RAHASIA", "If-Match": `W/"2"`}, "")
+	assert.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+
+	recorder, _ = doRequest(router, http.MethodDelete, target, map[string]string{"X-API-Key": "RAHASIA", "If-Match": `"2"`}, "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestCategoryIfMatchRequired(t *testing.T) {
+	storage := setupTestStorage()
+	truncateCategory(storage)
+	cfg := setupTestConfig()
+	cfg.Concurrency.RequireIfMatch = true
+	router := setupRouterWithConfig(storage, cfg)
+	category := createCategory(storage, "Gadget")
+	target := "/api/categories/" + strconv.Itoa(category.Id)
+
+	recorder, _ := doRequest(router, http.MethodPut, target, apiKeyHeader("RAHASIA"), `{"name": "Handphone"}`)
+	assert.Equal(t, http.StatusPreconditionRequired, recorder.Code)
+
+	recorder, _ = doRequest(router, http.MethodDelete, target, apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, http.StatusPreconditionRequired, recorder.Code)
+
+	recorder, _ = doRequest(router, http.MethodPut, target, map[string]string{"X-API-Key": "RAHASIA", "If-Match": "*"}, `{"name": "Handphone"}`)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	recorder, _ = doRequest(router, http.MethodDelete, target+"?purge=true", map[string]string{"X-API-Key": "RAHASIA", "If-Match": `"2"`}, "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestCategoryRepositoryVersionConflict(t *testing.T) {
+	storage := setupTestStorage()
+	truncateCategory(storage)
+	category := createCategory(storage, "Gadget")
+	ctx := context.Background()
+
+	err := storage.UnitOfWork.Do(ctx, func(tx repository.Tx) error {
+		updated, err := storage.CategoryRepository.Update(ctx, tx, domain.Category{Id: category.Id, Name: "Handphone", Version: 1})
+		assert.Equal(t, 2, updated.Version)
+		return err
+	})
+	assert.Nil(t, err)
+
+	err = storage.UnitOfWork.Do(ctx, func(tx repository.Tx) error {
+		_, err := storage.CategoryRepository.Update(ctx, tx, domain.Category{Id: category.Id, Name: "Laptop", Version: 1})
+		return err
+	})
+	assert.ErrorIs(t, err, repository.ErrCategoryVersionConflict)
+}
@@ -0,0 +1,134 @@
+package test
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sudutkampus/gorestfulapi/config"
+)
+
+func createChildCategory(router http.Handler, name string, parentId int) int {
+	_, responseBody := doRequest(router, http.MethodPost, "/api/categories", apiKeyHeader("RAHASIA"), `{"name": "`+name+`", "parent_id": `+strconv.Itoa(parentId)+`}`)
+	return int(responseBody["data"].(map[string]interface{})["id"].(float64))
+}
+
+func categoryNames(responseBody map[string]interface{}) []string {
+	var names []string
+	for _, category := range responseBody["data"].([]interface{}) {
+		names = append(names, category.(map[string]interface{})["name"].(string))
+	}
+
+	return names
+}
+
+func TestCategoryHierarchy(t *testing.T) {
+	storage := setupTestStorage()
+	truncateCategory(storage)
+	router := setupRouter(storage)
+	electronics := createCategory(storage, "Electronics")
+	computers := createChildCategory(router, "Computers", electronics.Id)
+	laptops := createChildCategory(router, "Laptops", computers)
+	createChildCategory(router, "Phones", electronics.Id)
+
+	_, responseBody := doRequest(router, http.MethodGet, "/api/categories/"+strconv.Itoa(laptops), apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, computers, int(responseBody["data"].(map[string]interface{})["parent_id"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodGet, "/api/categories/"+strconv.Itoa(electronics.Id)+"/children", apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, []string{"Computers", "Phones"}, categoryNames(responseBody))
+
+	_, responseBody = doRequest(router, http.MethodGet, "/api/categories/"+strconv.Itoa(laptops)+"/ancestors", apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, []string{"Electronics", "Computers"}, categoryNames(responseBody))
+
+	_, responseBody = doRequest(router, http.MethodGet, "/api/categories/tree", apiKeyHeader("RAHASIA"), "")
+	tree := responseBody["data"].([]interface{})
+	assert.Len(t, tree, 1)
+	children := tree[0].(map[string]interface{})["children"].([]interface{})
+	assert.Len(t, children, 2)
+	assert.Equal(t, "Laptops", children[0].(map[string]interface{})["children"].([]interface{})[0].(map[string]interface{})["name"])
+
+	_, responseBody = doRequest(router, http.MethodPost, "/api/categories", apiKeyHeader("RAHASIA"), `{"name": "Tablets", "parent_id": 99999}`)
+	assert.Equal(t, http.StatusBadRequest, int(responseBody["code"].(float64)))
+}
+
+func TestMoveCategory(t *testing.T) {
+	storage := setupTestStorage()
+	truncateCategory(storage)
+	router := setupRouter(storage)
+	electronics := createCategory(storage, "Electronics")
+	office := createCategory(storage, "Office")
+	computers := createChildCategory(router, "Computers", electronics.Id)
+	laptops := createChildCategory(router, "Laptops", computers)
+
+	_, responseBody := doRequest(router, http.MethodPost, "/api/categories/"+strconv.Itoa(computers)+"/move", apiKeyHeader("RAHASIA"), `{"parent_id": `+strconv.Itoa(office.Id)+`}`)
+	assert.Equal(t, http.StatusOK, int(responseBody["code"].(float64)))
+	assert.Equal(t, office.Id, int(responseBody["data"].(map[string]interface{})["parent_id"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodGet, "/api/categories/"+strconv.Itoa(laptops)+"/ancestors", apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, []string{"Office", "Computers"}, categoryNames(responseBody))
+
+	// under itself or its own descendant would be a cycle
+	_, responseBody = doRequest(router, http.MethodPost, "/api/categories/"+strconv.Itoa(computers)+"/move", apiKeyHeader("RAHASIA"), `{"parent_id": `+strconv.Itoa(laptops)+`}`)
+	assert.Equal(t, http.StatusConflict, int(responseBody["code"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodPost, "/api/categories/"+strconv.Itoa(computers)+"/move", apiKeyHeader("RAHASIA"), `{"parent_id": `+strconv.Itoa(computers)+`}`)
+	assert.Equal(t, http.StatusConflict, int(responseBody["code"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodPost, "/api/categories/"+strconv.Itoa(computers)+"/move", apiKeyHeader("RAHASIA"), `{"parent_id": null}`)
+	assert.Equal(t, http.StatusOK, int(responseBody["code"].(float64)))
+	assert.Nil(t, responseBody["data"].(map[string]interface{})["parent_id"])
+}
+
+func TestDeleteCategoryWithChildren(t *testing.T) {
+	tests := []struct {
+		onDelete string
+		code     int
+		live     []string
+	}{
+		{config.OnDeleteReject, http.StatusConflict, []string{"Electronics", "Computers", "Laptops"}},
+		{config.OnDeleteReparent, http.StatusOK, []string{"Electronics", "Laptops"}},
+		{config.OnDeleteCascade, http.StatusOK, []string{"Electronics"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.onDelete, func(t *testing.T) {
+			storage := setupTestStorage()
+			truncateCategory(storage)
+			cfg := setupTestConfig()
+			cfg.Hierarchy.OnDelete = test.onDelete
+			router := setupRouterWithConfig(storage, cfg)
+			electronics := createCategory(storage, "Electronics")
+			computers := createChildCategory(router, "Computers", electronics.Id)
+			laptops := createChildCategory(router, "Laptops", computers)
+
+			_, responseBody := doRequest(router, http.MethodDelete, "/api/categories/"+strconv.Itoa(computers), apiKeyHeader("RAHASIA"), "")
+			assert.Equal(t, test.code, int(responseBody["code"].(float64)))
+
+			_, responseBody = doRequest(router, http.MethodGet, "/api/categories", apiKeyHeader("RAHASIA"), "")
+			assert.Equal(t, test.live, categoryNames(responseBody))
+
+			if test.onDelete == config.OnDeleteReparent {
+				_, responseBody = doRequest(router, http.MethodGet, "/api/categories/"+strconv.Itoa(laptops), apiKeyHeader("RAHASIA"), "")
+				assert.Equal(t, electronics.Id, int(responseBody["data"].(map[string]interface{})["parent_id"].(float64)))
+			}
+		})
+	}
+}
+
+func TestRestoreCategoryWithoutParent(t *testing.T) {
+	storage := setupTestStorage()
+	truncateCategory(storage)
+	cfg := setupTestConfig()
+	cfg.Hierarchy.OnDelete = config.OnDeleteCascade
+	router := setupRouterWithConfig(storage, cfg)
+	electronics := createCategory(storage, "Electronics")
+	computers := createChildCategory(router, "Computers", electronics.Id)
+
+	doRequest(router, http.MethodDelete, "/api/categories/"+strconv.Itoa(electronics.Id), apiKeyHeader("RAHASIA"), "")
+
+	_, responseBody := doRequest(router, http.MethodPost, "/api/categories/"+strconv.Itoa(computers)+"/restore", apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, http.StatusOK, int(responseBody["code"].(float64)))
+	assert.Nil(t, responseBody["data"].(map[string]interface{})["parent_id"])
+}
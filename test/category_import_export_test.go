@@ -0,0 +1,230 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sudutkampus/gorestfulapi/app"
+	"sudutkampus/gorestfulapi/helper"
+	"sudutkampus/gorestfulapi/logging"
+	"sudutkampus/gorestfulapi/model/domain"
+	"sudutkampus/gorestfulapi/model/web"
+	"sudutkampus/gorestfulapi/repository"
+	"sudutkampus/gorestfulapi/service"
+)
+
+func importCategories(router http.Handler, query string, filename string, content string) (int, map[string]interface{}) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, _ := writer.CreateFormFile("file", filename)
+	part.Write([]byte(content))
+	writer.Close()
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, "http://localhost:3000/api/categories/import"+query, &body)
+	request.Header.Add("Content-Type", writer.FormDataContentType())
+	request.Header.Add("X-API-Key", "RAHASIA")
+
+	router.ServeHTTP(recorder, request)
+
+	var responseBody map[string]interface{}
+	json.Unmarshal(recorder.Body.Bytes(), &responseBody)
+
+	return recorder.Code, responseBody
+}
+
+func countCategories(router http.Handler) int {
+	_, responseBody := doRequest(router, http.MethodGet, "/api/categories", apiKeyHeader("RAHASIA"), "")
+	return int(responseBody["meta"].(map[string]interface{})["total"].(float64))
+}
+
+func TestExportCategory(t *testing.T) {
+	storage := setupTestStorage()
+	truncateCategory(storage)
+	router := setupRouter(storage)
+	doRequest(router, http.MethodPost, "/api/categories", apiKeyHeader("RAHASIA"), `{"name": "Gadget"}`)
+	doRequest(router, http.MethodPost, "/api/categories", apiKeyHeader("RAHASIA"), `{"name": "Laptop, \"Pro\""}`)
+
+	recorder, _ := doRequest(router, http.MethodGet, "/api/categories/export?sort=name", apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", recorder.Header().Get("Content-Type"))
+	lines := strings.Split(strings.TrimSpace(recorder.Body.String()), "\n")
+	assert.Equal(t, "id,name,slug,parent_id,version", lines[0])
+	assert.Len(t, lines, 3)
+	assert.True(t, strings.HasSuffix(lines[1], `,Gadget,gadget,,1`))
+	assert.True(t, strings.HasSuffix(lines[2], `,"Laptop, ""Pro""",laptop-pro,,1`))
+
+	recorder, _ = doRequest(router, http.MethodGet, "/api/categories/export?format=jsonl&q=gad", apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/x-ndjson", recorder.Header().Get("Content-Type"))
+	var category map[string]interface{}
+	lines = strings.Split(strings.TrimSpace(recorder.Body.String()), "\n")
+	assert.Len(t, lines, 1)
+	json.Unmarshal([]byte(lines[0]), &category)
+	assert.Equal(t, "Gadget", category["name"])
+
+	recorder, _ = doRequest(router, http.MethodGet, "/api/categories/export?format=xml", apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestImportCategory(t *testing.T) {
+	storage := setupTestStorage()
+	truncateCategory(storage)
+	router := setupRouter(storage)
+
+	code, responseBody := importCategories(router, "?dry_run=true", "categories.csv", "id,name,version\n1,Gadget,1\n2,Laptop,3\n")
+	assert.Equal(t, http.StatusOK, code)
+	data := responseBody["data"].(map[string]interface{})
+	assert.Equal(t, true, data["dry_run"])
+	assert.Equal(t, 2, int(data["total"].(float64)))
+	assert.Equal(t, 0, int(data["imported"].(float64)))
+	assert.Equal(t, 0, countCategories(router))
+
+	code, responseBody = importCategories(router, "", "categories.csv", "name\nGadget\nLaptop\n")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 2, int(responseBody["data"].(map[string]interface{})["imported"].(float64)))
+	assert.Equal(t, 2, countCategories(router))
+
+	code, responseBody = importCategories(router, "", "categories.jsonl", `{"name": "Camera"}`+"\n\n"+`{"id": 9, "name": "Printer", "version": 4}`+"\n")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 2, int(responseBody["data"].(map[string]interface{})["imported"].(float64)))
+	assert.Equal(t, 4, countCategories(router))
+}
+
+func TestImportCategoryRowErrors(t *testing.T) {
+	storage := setupTestStorage()
+	truncateCategory(storage)
+	router := setupRouter(storage)
+
+	code, responseBody := importCategories(router, "?format=jsonl", "categories.txt", `{"name": "Camera"}`+"\n"+`{"name": ""}`+"\n"+`not json`+"\n")
+	assert.Equal(t, http.StatusBadRequest, code)
+	data := responseBody["data"].(map[string]interface{})
+	assert.Equal(t, 3, int(data["total"].(float64)))
+	assert.Equal(t, 2, int(data["failed"].(float64)))
+	assert.Equal(t, 0, int(data["imported"].(float64)))
+	rowErrors := data["errors"].([]interface{})
+	assert.Equal(t, 2, int(rowErrors[0].(map[string]interface{})["row"].(float64)))
+	assert.Equal(t, "name", rowErrors[0].(map[string]interface{})["errors"].([]interface{})[0].(map[string]interface{})["field"])
+	assert.Equal(t, 3, int(rowErrors[1].(map[string]interface{})["row"].(float64)))
+	assert.Equal(t, 0, countCategories(router))
+
+	code, _ = importCategories(router, "", "categories.csv", "title\nCamera\n")
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = importCategories(router, "", "categories.txt", "name\nCamera\n")
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	recorder, _ := doRequest(router, http.MethodPost, "/api/categories/import", apiKeyHeader("RAHASIA"), `{"name": "Camera"}`)
+	assert.Equal(t, http.StatusUnsupportedMediaType, recorder.Code)
+}
+
+func categoryParents(router http.Handler) map[string]string {
+	_, responseBody := doRequest(router, http.MethodGet, "/api/categories", apiKeyHeader("RAHASIA"), "")
+	names := map[float64]string{}
+	for _, category := range responseBody["data"].([]interface{}) {
+		category := category.(map[string]interface{})
+		names[category["id"].(float64)] = category["name"].(string)
+	}
+
+	parents := map[string]string{}
+	for _, category := range responseBody["data"].([]interface{}) {
+		category := category.(map[string]interface{})
+		if parentId, ok := category["parent_id"].(float64); ok {
+			parents[category["name"].(string)] = names[parentId]
+		} else {
+			parents[category["name"].(string)] = ""
+		}
+	}
+
+	return parents
+}
+
+func TestExportImportCategoryHierarchy(t *testing.T) {
+	storage := setupTestStorage()
+	truncateCategory(storage)
+	router := setupRouter(storage)
+	electronics := createCategory(storage, "Electronics")
+	computers := createChildCategory(router, "Computers", electronics.Id)
+	createChildCategory(router, "Laptops", computers)
+	createCategory(storage, "Books")
+	expected := map[string]string{"Electronics": "", "Computers": "Electronics", "Laptops": "Computers", "Books": ""}
+
+	for _, format := range []string{"csv", "jsonl"} {
+		recorder, _ := doRequest(router, http.MethodGet, "/api/categories/export?sort=name&format="+format, apiKeyHeader("RAHASIA"), "")
+		assert.Equal(t, http.StatusOK, recorder.Code)
+
+		storage := setupTestStorage()
+		truncateCategory(storage)
+		importRouter := setupRouter(storage)
+		code, responseBody := importCategories(importRouter, "", "categories."+format, recorder.Body.String())
+		assert.Equal(t, http.StatusOK, code, format)
+		assert.Equal(t, 4, int(responseBody["data"].(map[string]interface{})["imported"].(float64)), format)
+		assert.Equal(t, expected, categoryParents(importRouter), format)
+	}
+}
+
+func TestImportCategoryParents(t *testing.T) {
+	storage := setupTestStorage()
+	truncateCategory(storage)
+	router := setupRouter(storage)
+	electronics := createCategory(storage, "Electronics")
+
+	code, responseBody := importCategories(router, "", "categories.csv", "id,name,parent_id\n101,Computers,"+strconv.Itoa(electronics.Id)+"\n102,Laptops,101\n")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 2, int(responseBody["data"].(map[string]interface{})["imported"].(float64)))
+	assert.Equal(t, map[string]string{"Electronics": "", "Computers": "Electronics", "Laptops": "Computers"}, categoryParents(router))
+
+	code, responseBody = importCategories(router, "", "categories.csv", "id,name,parent_id\n1,Camera,2\n2,Phones,1\n3,Tablets,99999\n4,Printers,x\n3,Scanners,\n")
+	assert.Equal(t, http.StatusBadRequest, code)
+	data := responseBody["data"].(map[string]interface{})
+	assert.Equal(t, 5, int(data["failed"].(float64)))
+	fields := []string{}
+	for _, rowError := range data["errors"].([]interface{}) {
+		fields = append(fields, rowError.(map[string]interface{})["errors"].([]interface{})[0].(map[string]interface{})["field"].(string))
+	}
+	assert.Equal(t, []string{"parent_id", "parent_id", "parent_id", "parent_id", "id"}, fields)
+	assert.Equal(t, 3, countCategories(router))
+}
+
+func TestExportCategoryPages(t *testing.T) {
+	storage := setupTestStorage()
+	truncateCategory(storage)
+	ctx := context.Background()
+
+	categories := make([]domain.Category, service.ExportPageSize+1)
+	for i := range categories {
+		name := fmt.Sprintf("Category %04d", i)
+		categories[i] = domain.Category{Name: name, Slug: helper.Slugify(name)}
+	}
+	err := storage.UnitOfWork.Do(ctx, func(tx repository.Tx) error {
+		_, err := storage.CategoryRepository.SaveAll(ctx, tx, categories)
+		return err
+	})
+	assert.Nil(t, err)
+
+	categoryService := service.NewCategoryService(storage.CategoryRepository, storage.ProductRepository, storage.AuditLogRepository, storage.UnitOfWork, app.NewValidator(), setupTestConfig().Hierarchy, logging.Discard())
+
+	// rows are handed over between units of work, so writing one may use the database
+	var names []string
+	err = categoryService.Export(ctx, web.CategoryExportRequest{Format: web.FormatCSV, Sort: "-name"}, func(category web.CategoryResponse) error {
+		_, err := categoryService.FindById(ctx, category.Id)
+		names = append(names, category.Name)
+		return err
+	})
+	assert.Nil(t, err)
+	assert.Len(t, names, len(categories))
+	assert.Equal(t, "Category 0500", names[0])
+	assert.Equal(t, "Category 0000", names[len(names)-1])
+	assert.True(t, sort.SliceIsSorted(names, func(i, j int) bool { return names[i] > names[j] }))
+}
@@ -0,0 +1,72 @@
+package test
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergePatchCategory(t *testing.T) {
+	storage := setupTestStorage()
+	truncateCategory(storage)
+	router := setupRouter(storage)
+	category := createCategory(storage, "Gadget")
+	target := "/api/categories/" + strconv.Itoa(category.Id)
+	mergePatch := map[string]string{"X-API-Key": "RAHASIA", "Content-Type": "application/merge-patch+json"}
+
+	recorder, responseBody := doRequest(router, http.MethodPatch, target, map[string]string{"X-API-Key": "RAHASIA", "Content-Type": "application/merge-patch+json", "If-Match": `"1"`}, `{"name": "Handphone"}`)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "Handphone", responseBody["data"].(map[string]interface{})["name"])
+	assert.Equal(t, 2, int(responseBody["data"].(map[string]interface{})["version"].(float64)))
+
+	recorder, _ = doRequest(router, http.MethodPatch, target, map[string]string{"X-API-Key": "RAHASIA", "Content-Type": "application/merge-patch+json", "If-Match": `"1"`}, `{"name": "Laptop"}`)
+	assert.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+
+	// removing a required field fails the same validation as PUT
+	recorder, responseBody = doRequest(router, http.MethodPatch, target, mergePatch, `{"name": null}`)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, "name", responseBody["data"].([]interface{})[0].(map[string]interface{})["field"])
+
+	recorder, _ = doRequest(router, http.MethodPatch, target, mergePatch, `{"color": "red"}`)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	recorder, _ = doRequest(router, http.MethodPatch, target, mergePatch, `{"id": 99}`)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	recorder, _ = doRequest(router, http.MethodPatch, target, map[string]string{"X-API-Key": "RAHASIA", "Content-Type": "application/json"}, `{"name": "Laptop"}`)
+	assert.Equal(t, http.StatusUnsupportedMediaType, recorder.Code)
+
+	recorder, _ = doRequest(router, http.MethodPatch, "/api/categories/99999", mergePatch, `{"name": "Laptop"}`)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestJSONPatchCategory(t *testing.T) {
+	storage := setupTestStorage()
+	truncateCategory(storage)
+	router := setupRouter(storage)
+	category := createCategory(storage, "Gadget")
+	target := "/api/categories/" + strconv.Itoa(category.Id)
+	jsonPatch := map[string]string{"X-API-Key": "RAHASIA", "Content-Type": "application/json-patch+json"}
+
+	recorder, responseBody := doRequest(router, http.MethodPatch, target, map[string]string{"X-API-Key": "RAHASIA", "Content-Type": "application/json-patch+json; charset=utf-8"},
+		`[{"op": "test", "path": "/name", "value": "Gadget"}, {"op": "replace", "path": "/name", "value": "Handphone"}]`)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "Handphone", responseBody["data"].(map[string]interface{})["name"])
+
+	recorder, _ = doRequest(router, http.MethodPatch, target, jsonPatch,
+		`[{"op": "test", "path": "/name", "value": "Gadget"}, {"op": "replace", "path": "/name", "value": "Laptop"}]`)
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+
+	recorder, _ = doRequest(router, http.MethodPatch, target, jsonPatch, `[{"op": "remove", "path": "/name"}]`)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	recorder, _ = doRequest(router, http.MethodPatch, target, jsonPatch, `{"op": "replace"}`)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	recorder, responseBody = doRequest(router, http.MethodPatch, target, jsonPatch, `[{"op": "replace", "path": "/name", "value": "`+strings.Repeat("a", 256)+`"}]`)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, "max", responseBody["data"].([]interface{})[0].(map[string]interface{})["tag"])
+}
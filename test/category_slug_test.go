@@ -0,0 +1,197 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"sudutkampus/gorestfulapi/app"
+	"sudutkampus/gorestfulapi/config"
+	"sudutkampus/gorestfulapi/helper"
+	"sudutkampus/gorestfulapi/logging"
+	"sudutkampus/gorestfulapi/model/domain"
+	"sudutkampus/gorestfulapi/repository"
+	"sudutkampus/gorestfulapi/service"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := map[string]string{
+		"Gadget":              "gadget",
+		"Home & Garden":       "home-garden",
+		"  Café Crème  ":      "cafe-creme",
+		"Straße":              "strasse",
+		"Электроника":         "elektronika",
+		"Йогурт":              "yogurt",
+		"Їжак":                "yizhak",
+		"Ø 2.5 mm":            "o-2-5-mm",
+		"日本":                  "",
+		"--":                  "",
+		"Laptop (15\") - Pro": "laptop-15-pro",
+	}
+
+	for name, slug := range tests {
+		assert.Equal(t, slug, helper.Slugify(name), name)
+	}
+}
+
+func TestCategorySlug(t *testing.T) {
+	storage := setupTestStorage()
+	truncateCategory(storage)
+	router := setupRouter(storage)
+
+	_, responseBody := doRequest(router, http.MethodPost, "/api/categories", apiKeyHeader("RAHASIA"), `{"name": "Café Crème"}`)
+	assert.Equal(t, "cafe-creme", responseBody["data"].(map[string]interface{})["slug"])
+
+	_, responseBody = doRequest(router, http.MethodPost, "/api/categories", apiKeyHeader("RAHASIA"), `{"name": "Cafe-Creme!"}`)
+	assert.Equal(t, "cafe-creme-2", responseBody["data"].(map[string]interface{})["slug"])
+
+	_, responseBody = doRequest(router, http.MethodPost, "/api/categories", apiKeyHeader("RAHASIA"), `{"name": "日本"}`)
+	assert.Equal(t, "category", responseBody["data"].(map[string]interface{})["slug"])
+
+	_, responseBody = doRequest(router, http.MethodGet, "/api/categories/by-slug/cafe-creme-2", apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, http.StatusOK, int(responseBody["code"].(float64)))
+	assert.Equal(t, "Cafe-Creme!", responseBody["data"].(map[string]interface{})["name"])
+
+	_, responseBody = doRequest(router, http.MethodGet, "/api/categories/by-slug/unknown", apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, http.StatusNotFound, int(responseBody["code"].(float64)))
+
+	recorder, _ := doRequest(router, http.MethodGet, "/api/categories/1/siblings", apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestCategoryUniqueName(t *testing.T) {
+	storage := setupTestStorage()
+	truncateCategory(storage)
+	router := setupRouter(storage)
+
+	_, responseBody := doRequest(router, http.MethodPost, "/api/categories", apiKeyHeader("RAHASIA"), `{"name": "Gadget"}`)
+	gadget := strconv.Itoa(int(responseBody["data"].(map[string]interface{})["id"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodPost, "/api/categories", apiKeyHeader("RAHASIA"), `{"name": "GADGET"}`)
+	assert.Equal(t, http.StatusConflict, int(responseBody["code"].(float64)))
+	assert.Equal(t, "category name already exists", responseBody["data"])
+
+	_, responseBody = doRequest(router, http.MethodPost, "/api/categories", apiKeyHeader("RAHASIA"), `{"name": "Laptop"}`)
+	laptop := strconv.Itoa(int(responseBody["data"].(map[string]interface{})["id"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodPut, "/api/categories/"+laptop, apiKeyHeader("RAHASIA"), `{"name": "gadget"}`)
+	assert.Equal(t, http.StatusConflict, int(responseBody["code"].(float64)))
+
+	// renaming keeps the slug and may change the case of the name itself
+	_, responseBody = doRequest(router, http.MethodPut, "/api/categories/"+gadget, apiKeyHeader("RAHASIA"), `{"name": "GADGET"}`)
+	assert.Equal(t, http.StatusOK, int(responseBody["code"].(float64)))
+	assert.Equal(t, "gadget", responseBody["data"].(map[string]interface{})["slug"])
+
+	// a trashed name can be reused, but then the trashed one cannot be restored
+	doRequest(router, http.MethodDelete, "/api/categories/"+laptop, apiKeyHeader("RAHASIA"), "")
+	_, responseBody = doRequest(router, http.MethodPost, "/api/categories", apiKeyHeader("RAHASIA"), `{"name": "Laptop"}`)
+	assert.Equal(t, http.StatusOK, int(responseBody["code"].(float64)))
+	assert.Equal(t, "laptop-2", responseBody["data"].(map[string]interface{})["slug"])
+
+	_, responseBody = doRequest(router, http.MethodPost, "/api/categories/"+laptop+"/restore", apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, http.StatusConflict, int(responseBody["code"].(float64)))
+
+	code, results := bulkCategories(router, `{"mode": "partial", "operations": [
+		{"op": "create", "name": "Camera"},
+		{"op": "create", "name": "camera"}
+	]}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, http.StatusOK, int(results[0]["code"].(float64)))
+	assert.Equal(t, http.StatusConflict, int(results[1]["code"].(float64)))
+}
+
+func TestCategoryUniqueNameConstraint(t *testing.T) {
+	storage := setupTestStorage()
+	if storage.DB == nil {
+		t.Skip("the in-memory backend serializes writes, only SQL databases need the index")
+	}
+	truncateCategory(storage)
+	ctx := context.Background()
+
+	// writes that skip the service checks, as a concurrent request could
+	gadget := createCategory(storage, "Gadget")
+	err := storage.UnitOfWork.Do(ctx, func(tx repository.Tx) error {
+		_, err := storage.CategoryRepository.Save(ctx, tx, domain.Category{Name: "gADGET", Slug: "gadget-2"})
+		return err
+	})
+	assert.ErrorIs(t, err, repository.ErrCategoryNameConflict)
+
+	deletedAt := time.Now().UTC()
+	gadget.DeletedAt = &deletedAt
+	err = storage.UnitOfWork.Do(ctx, func(tx repository.Tx) error {
+		err := storage.CategoryRepository.Delete(ctx, tx, gadget)
+		if err != nil {
+			return err
+		}
+		_, err = storage.CategoryRepository.Save(ctx, tx, domain.Category{Name: "gADGET", Slug: "gadget-2"})
+		return err
+	})
+	assert.Nil(t, err)
+
+	err = storage.UnitOfWork.Do(ctx, func(tx repository.Tx) error {
+		return storage.CategoryRepository.Restore(ctx, tx, gadget)
+	})
+	assert.ErrorIs(t, err, repository.ErrCategoryNameConflict)
+}
+
+func TestBackfillCategorySlugs(t *testing.T) {
+	storage := setupTestStorage()
+	truncateCategory(storage)
+	category := createCategory(storage, "Home & Garden")
+	assert.Empty(t, category.Slug)
+
+	categoryService := service.NewCategoryService(storage.CategoryRepository, storage.ProductRepository, storage.AuditLogRepository, storage.UnitOfWork, app.NewValidator(), setupTestConfig().Hierarchy, logging.Discard())
+	backfilled, err := categoryService.BackfillSlugs(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, backfilled)
+
+	categoryResponse, err := categoryService.FindBySlug(context.Background(), "home-garden")
+	assert.Nil(t, err)
+	assert.Equal(t, category.Id, categoryResponse.Id)
+}
+
+func TestBackfillCategorySlugsConcurrently(t *testing.T) {
+	if setupTestConfig().Database.Driver == config.DriverSQLite {
+		t.Skip("sqlite fails concurrent write transactions instead of waiting for them")
+	}
+	storage := setupTestStorage()
+	truncateCategory(storage)
+	createCategory(storage, "Home & Garden")
+	createCategory(storage, "Home - Garden")
+	createCategory(storage, "Gadget")
+
+	categoryService := service.NewCategoryService(storage.CategoryRepository, storage.ProductRepository, storage.AuditLogRepository, storage.UnitOfWork, app.NewValidator(), setupTestConfig().Hierarchy, logging.Discard())
+
+	var wg sync.WaitGroup
+	results := make([]int, 4)
+	errs := make([]error, len(results))
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = categoryService.BackfillSlugs(context.Background())
+		}(i)
+	}
+	wg.Wait()
+
+	var backfilled int
+	for i := range results {
+		assert.Nil(t, errs[i])
+		backfilled += results[i]
+	}
+	assert.Equal(t, 3, backfilled)
+
+	for _, slug := range []string{"home-garden", "home-garden-2", "gadget"} {
+		_, err := categoryService.FindBySlug(context.Background(), slug)
+		assert.Nil(t, err, slug)
+	}
+
+	backfilled, err := categoryService.BackfillSlugs(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 0, backfilled)
+}
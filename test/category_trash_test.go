@@ -0,0 +1,117 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"sudutkampus/gorestfulapi/app"
+	"sudutkampus/gorestfulapi/config"
+	"sudutkampus/gorestfulapi/logging"
+	"sudutkampus/gorestfulapi/model/domain"
+	"sudutkampus/gorestfulapi/repository"
+	"sudutkampus/gorestfulapi/service"
+)
+
+func countTrashed(storage *app.Storage) int {
+	var total int
+	err := storage.UnitOfWork.Do(context.Background(), func(tx repository.Tx) (err error) {
+		total, err = storage.CategoryRepository.Count(context.Background(), tx, domain.CategoryFilter{Trashed: true})
+		return err
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return total
+}
+
+func TestSoftDeleteAndRestoreCategory(t *testing.T) {
+	storage := setupTestStorage()
+	truncateCategory(storage)
+	router := setupRouter(storage)
+	category := createCategory(storage, "Gadget")
+	createCategory(storage, "Fashion")
+	target := "/api/categories/" + strconv.Itoa(category.Id)
+
+	_, responseBody := doRequest(router, http.MethodDelete, target, apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, http.StatusOK, int(responseBody["code"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodGet, target, apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, http.StatusNotFound, int(responseBody["code"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodGet, "/api/categories", apiKeyHeader("RAHASIA"), "")
+	assert.Len(t, responseBody["data"], 1)
+
+	_, responseBody = doRequest(router, http.MethodGet, "/api/categories/trash", apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, http.StatusOK, int(responseBody["code"].(float64)))
+	trashed := responseBody["data"].([]interface{})
+	assert.Len(t, trashed, 1)
+	assert.Equal(t, "Gadget", trashed[0].(map[string]interface{})["name"])
+	assert.NotNil(t, trashed[0].(map[string]interface{})["deleted_at"])
+	assert.Equal(t, 1, int(responseBody["meta"].(map[string]interface{})["total"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodPost, target+"/restore", apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, http.StatusOK, int(responseBody["code"].(float64)))
+	assert.Nil(t, responseBody["data"].(map[string]interface{})["deleted_at"])
+
+	_, responseBody = doRequest(router, http.MethodGet, target, apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, http.StatusOK, int(responseBody["code"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodGet, "/api/categories/trash", apiKeyHeader("RAHASIA"), "")
+	assert.Empty(t, responseBody["data"])
+
+	_, responseBody = doRequest(router, http.MethodPost, "/api/categories/99999/restore", apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, http.StatusNotFound, int(responseBody["code"].(float64)))
+}
+
+func TestPurgeCategory(t *testing.T) {
+	storage := setupTestStorage()
+	truncateCategory(storage)
+	router := setupRouter(storage)
+	category := createCategory(storage, "Gadget")
+	target := "/api/categories/" + strconv.Itoa(category.Id)
+
+	_, responseBody := doRequest(router, http.MethodDelete, target+"?purge=maybe", apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, http.StatusBadRequest, int(responseBody["code"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodDelete, target, apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, http.StatusOK, int(responseBody["code"].(float64)))
+
+	// trashed categories can still be purged
+	_, responseBody = doRequest(router, http.MethodDelete, target+"?purge=true", apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, http.StatusOK, int(responseBody["code"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodPost, target+"/restore", apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, http.StatusNotFound, int(responseBody["code"].(float64)))
+	assert.Equal(t, 0, countTrashed(storage))
+}
+
+func TestTrashPurgeHook(t *testing.T) {
+	storage := setupTestStorage()
+	truncateCategory(storage)
+	router := setupRouter(storage)
+	category := createCategory(storage, "Gadget")
+
+	doRequest(router, http.MethodDelete, "/api/categories/"+strconv.Itoa(category.Id), apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, 1, countTrashed(storage))
+
+	categoryService := service.NewCategoryService(storage.CategoryRepository, storage.ProductRepository, storage.AuditLogRepository, storage.UnitOfWork, app.NewValidator(), setupTestConfig().Hierarchy, logging.Discard())
+
+	hook := app.TrashPurgeHook(categoryService, config.TrashConfig{Retention: time.Hour, PurgeInterval: 10 * time.Millisecond}, logging.Discard())
+	assert.Nil(t, hook.OnStart(context.Background()))
+	time.Sleep(30 * time.Millisecond)
+	assert.Nil(t, hook.OnStop(context.Background()))
+	assert.Equal(t, 1, countTrashed(storage))
+
+	hook = app.TrashPurgeHook(categoryService, config.TrashConfig{Retention: time.Nanosecond, PurgeInterval: 10 * time.Millisecond}, logging.Discard())
+	assert.Nil(t, hook.OnStart(context.Background()))
+	assert.Eventually(t, func() bool {
+		return countTrashed(storage) == 0
+	}, time.Second, 10*time.Millisecond)
+	assert.Nil(t, hook.OnStop(context.Background()))
+}
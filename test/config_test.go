@@ -0,0 +1,100 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"sudutkampus/gorestfulapi/config"
+)
+
+func writeConfigFile(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	err := os.WriteFile(path, []byte(content), 0600)
+	assert.Nil(t, err)
+
+	return path
+}
+
+func TestConfigDefaults(t *testing.T) {
+	cfg, err := config.Load([]string{"-auth-api-key", "RAHASIA"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "localhost:3000", cfg.Server.Addr)
+	assert.Equal(t, "mysql", cfg.Database.Driver)
+	assert.Equal(t, 20, cfg.Database.MaxOpenConns)
+	assert.Equal(t, 10*time.Minute, cfg.Database.ConnMaxIdleTime)
+	assert.False(t, cfg.Concurrency.RequireIfMatch)
+}
+
+func TestConfigPrecedence(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", `
+server:
+  addr: localhost:4000
+database:
+  dsn: file-dsn
+  max_open_conns: 50
+  conn_max_lifetime: 5m
+auth:
+  api_key: FILE
+`)
+	t.Setenv("GORESTFULAPI_DATABASE_DSN", "env-dsn")
+	t.Setenv("GORESTFULAPI_AUTH_API_KEY", "ENV")
+
+	cfg, err := config.Load([]string{"-config", path, "-auth-api-key", "FLAG"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "localhost:4000", cfg.Server.Addr)
+	assert.Equal(t, "env-dsn", cfg.Database.DSN)
+	assert.Equal(t, 50, cfg.Database.MaxOpenConns)
+	assert.Equal(t, 5*time.Minute, cfg.Database.ConnMaxLifetime)
+	assert.Equal(t, 5, cfg.Database.MaxIdleConns)
+	assert.Equal(t, "FLAG", cfg.Auth.APIKey)
+}
+
+func TestConfigJSONFileFromEnv(t *testing.T) {
+	path := writeConfigFile(t, "config.json", `{"server": {"addr": ":8080"}, "auth": {"api_key": "JSON"}}`)
+	t.Setenv("GORESTFULAPI_CONFIG", path)
+
+	cfg, err := config.Load(nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, ":8080", cfg.Server.Addr)
+	assert.Equal(t, "JSON", cfg.Auth.APIKey)
+}
+
+func TestConfigInvalid(t *testing.T) {
+	_, err := config.Load([]string{"-auth-api-key-hash-cost", "2"})
+	assert.NotNil(t, err)
+
+	_, err = config.Load([]string{"-auth-api-key", "change-me"})
+	assert.NotNil(t, err)
+
+	_, err = config.Load([]string{"-auth-api-key", "RAHASIA", "-database-driver", "oracle"})
+	assert.NotNil(t, err)
+
+	_, err = config.Load([]string{"-auth-session-access-token-ttl", "1h", "-auth-session-refresh-token-ttl", "30m"})
+	assert.NotNil(t, err)
+
+	_, err = config.Load([]string{"-auth-api-key", "RAHASIA", "-hierarchy-on-delete", "orphan"})
+	assert.NotNil(t, err)
+
+	_, err = config.Load([]string{"-auth-api-key", "RAHASIA", "-log-level", "verbose"})
+	assert.NotNil(t, err)
+
+	_, err = config.Load([]string{"-auth-api-key", "RAHASIA", "-tracing-exporter", "file"})
+	assert.NotNil(t, err)
+
+	_, err = config.Load([]string{"-auth-api-key", "RAHASIA", "-tracing-sample-ratio", "2"})
+	assert.NotNil(t, err)
+
+	_, err = config.Load([]string{"-auth-api-key", "RAHASIA", "-rate-limit-burst", "0"})
+	assert.NotNil(t, err)
+
+	t.Setenv("GORESTFULAPI_DATABASE_MAX_OPEN_CONNS", "many")
+	_, err = config.Load([]string{"-auth-api-key", "RAHASIA"})
+	assert.NotNil(t, err)
+}
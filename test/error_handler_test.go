@@ -0,0 +1,74 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sudutkampus/gorestfulapi/app"
+	"sudutkampus/gorestfulapi/exception"
+	"sudutkampus/gorestfulapi/model/web"
+)
+
+func TestErrorToWebResponse(t *testing.T) {
+	validationErr := app.NewValidator().Struct(web.CategoryCreateRequest{})
+
+	tests := []struct {
+		name   string
+		err    error
+		code   int
+		status string
+	}{
+		{"not found", exception.NewNotFoundError("category not found"), http.StatusNotFound, "Not Found"},
+		{"wrapped not found", fmt.Errorf("find: %w", exception.NewNotFoundError("category not found")), http.StatusNotFound, "Not Found"},
+		{"validation", exception.FromValidator(validationErr), http.StatusBadRequest, "Bad Request"},
+		{"conflict", exception.NewConflictError("duplicate"), http.StatusConflict, "Conflict"},
+		{"unauthorized", exception.NewUnauthorizedError("invalid api key"), http.StatusUnauthorized, "Unauthorized"},
+		{"precondition failed", exception.NewPreconditionFailedError("category has been modified"), http.StatusPreconditionFailed, "Precondition Failed"},
+		{"precondition required", exception.NewPreconditionRequiredError("If-Match header is required"), http.StatusPreconditionRequired, "Precondition Required"},
+		{"unsupported media type", exception.NewUnsupportedMediaTypeError("unsupported patch format"), http.StatusUnsupportedMediaType, "Unsupported Media Type"},
+		{"failed dependency", exception.NewFailedDependencyError("rolled back"), http.StatusFailedDependency, "Failed Dependency"},
+		{"internal", exception.NewInternalError(errors.New("boom")), http.StatusInternalServerError, "Internal Server Error"},
+		{"untyped", errors.New("boom"), http.StatusInternalServerError, "Internal Server Error"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			webResponse := exception.ToWebResponse(test.err, exception.Translator(""))
+
+			assert.Equal(t, test.code, webResponse.Code)
+			assert.Equal(t, test.status, webResponse.Status)
+			if test.code == http.StatusInternalServerError {
+				assert.Equal(t, "internal server error", webResponse.Data)
+			}
+		})
+	}
+}
+
+func TestValidationErrorTranslation(t *testing.T) {
+	err := app.NewValidator().Struct(web.CategoryCreateRequest{Name: strings.Repeat("a", 256)})
+
+	tests := []struct {
+		acceptLanguage string
+		message        string
+	}{
+		{"", "name must be a maximum of 255 characters in length"},
+		{"fr-FR, id;q=0.5", "panjang maksimal name adalah 255 karakter"},
+		{"en;q=0.4, id-ID;q=0.9", "panjang maksimal name adalah 255 karakter"},
+	}
+
+	for _, test := range tests {
+		webResponse := exception.ToWebResponse(exception.FromValidator(err), exception.Translator(test.acceptLanguage))
+		fieldErrors := webResponse.Data.([]web.FieldErrorResponse)
+
+		assert.Equal(t, http.StatusBadRequest, webResponse.Code)
+		assert.Equal(t, "name", fieldErrors[0].Field)
+		assert.Equal(t, "max", fieldErrors[0].Tag)
+		assert.Equal(t, "255", fieldErrors[0].Param)
+		assert.Equal(t, test.message, fieldErrors[0].Message)
+	}
+}
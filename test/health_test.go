@@ -0,0 +1,98 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"sudutkampus/gorestfulapi/app"
+	"sudutkampus/gorestfulapi/health"
+	"sudutkampus/gorestfulapi/logging"
+)
+
+func healthResponse(t *testing.T, router http.Handler, target string) (int, map[string]interface{}) {
+	recorder, responseBody := doRequest(router, http.MethodGet, target, apiKeyHeader("SALAH"), "")
+	assert.Equal(t, "no-store", recorder.Header().Get("Cache-Control"))
+
+	return recorder.Code, responseBody["data"].(map[string]interface{})
+}
+
+func TestHealth(t *testing.T) {
+	storage := setupTestStorage()
+	router := setupRouter(storage)
+
+	code, data := healthResponse(t, router, "/healthz")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "up", data["status"])
+	assert.Nil(t, data["checks"])
+
+	code, data = healthResponse(t, router, "/readyz")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "up", data["status"])
+
+	code, data = healthResponse(t, router, "/health")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "up", data["status"])
+
+	var names []interface{}
+	for _, check := range data["checks"].([]interface{}) {
+		check := check.(map[string]interface{})
+		assert.Equal(t, "up", check["status"])
+		assert.Contains(t, check, "latency_ms")
+		names = append(names, check["name"])
+	}
+	if storage.DB != nil {
+		assert.Equal(t, []interface{}{"database", "migrations", "lifecycle"}, names)
+	} else {
+		assert.Equal(t, []interface{}{"lifecycle"}, names)
+	}
+}
+
+func TestHealthDraining(t *testing.T) {
+	storage := setupTestStorage()
+	cfg := setupTestConfig()
+	lifecycle := app.NewLifecycle()
+	checker, err := app.NewHealthChecker(storage, cfg.Database, cfg.Health, lifecycle, logging.Discard())
+	assert.Nil(t, err)
+	router := app.NewHandler(setupRouter(storage), checker.Endpoints())
+
+	assert.Nil(t, lifecycle.Stop(context.Background()))
+
+	code, _ := healthResponse(t, router, "/healthz")
+	assert.Equal(t, http.StatusOK, code)
+
+	code, data := healthResponse(t, router, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "down", data["status"])
+
+	code, data = healthResponse(t, router, "/health")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	checks := data["checks"].([]interface{})
+	lifecycleCheck := checks[len(checks)-1].(map[string]interface{})
+	assert.Equal(t, "lifecycle", lifecycleCheck["name"])
+	assert.Equal(t, "down", lifecycleCheck["status"])
+	assert.Equal(t, "unavailable", lifecycleCheck["error"])
+}
+
+func TestHealthCheckTimeout(t *testing.T) {
+	var output bytes.Buffer
+	checker := health.NewChecker(10*time.Millisecond, logging.New(&output, logging.LevelInfo), health.Check{Name: "slow", Probe: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}})
+
+	response := checker.Run(context.Background())
+	assert.Equal(t, "down", response.Status)
+	assert.Equal(t, "unavailable", response.Checks[0].Error)
+	assert.GreaterOrEqual(t, response.Checks[0].LatencyMs, float64(10))
+
+	entries := logEntries(t, &output)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "health check failed", entries[0]["msg"])
+	assert.Equal(t, "slow", entries[0]["check"])
+	assert.Equal(t, "context deadline exceeded", entries[0]["error"])
+}
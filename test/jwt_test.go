@@ -0,0 +1,180 @@
+package test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+
+	"sudutkampus/gorestfulapi/auth"
+	"sudutkampus/gorestfulapi/config"
+)
+
+const testJWTSecret = "0123456789abcdef0123456789abcdef"
+
+func signToken(method jwt.SigningMethod, key interface{}, kid string, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	signed, err := token.SignedString(key)
+	if err != nil {
+		panic(err)
+	}
+
+	return signed
+}
+
+func testClaims(scope string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":   "user-42",
+		"name":  "Budi",
+		"iss":   "https://auth.sudutkampus.test",
+		"aud":   "gorestfulapi",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"scope": scope,
+	}
+}
+
+func TestBearerTokenAuthentication(t *testing.T) {
+	storage := setupTestStorage()
+	router := setupRouter(storage)
+	secret := []byte(testJWTSecret)
+
+	token := signToken(jwt.SigningMethodHS256, secret, "", testClaims("categories:read"))
+	recorder, _ := doRequest(router, http.MethodGet, "/api/categories", bearerHeader(token), "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	recorder, _ = doRequest(router, http.MethodDelete, "/api/categories/1", bearerHeader(token), "")
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+
+	expired := testClaims("categories:read")
+	expired["exp"] = time.Now().Add(-time.Hour).Unix()
+	token = signToken(jwt.SigningMethodHS256, secret, "", expired)
+	recorder, _ = doRequest(router, http.MethodGet, "/api/categories", bearerHeader(token), "")
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	otherIssuer := testClaims("categories:read")
+	otherIssuer["iss"] = "https://evil.test"
+	token = signToken(jwt.SigningMethodHS256, secret, "", otherIssuer)
+	recorder, _ = doRequest(router, http.MethodGet, "/api/categories", bearerHeader(token), "")
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	otherAudience := testClaims("categories:read")
+	otherAudience["aud"] = []string{"another-api"}
+	token = signToken(jwt.SigningMethodHS256, secret, "", otherAudience)
+	recorder, _ = doRequest(router, http.MethodGet, "/api/categories", bearerHeader(token), "")
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	token = signToken(jwt.SigningMethodHS256, []byte("another secret of 32 characters!"), "", testClaims("categories:read"))
+	recorder, _ = doRequest(router, http.MethodGet, "/api/categories", bearerHeader(token), "")
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
+
+func TestBearerTokenScopes(t *testing.T) {
+	verifier, err := auth.NewJWTVerifier(config.JWTConfig{HMACSecret: testJWTSecret, Issuer: "https://auth.sudutkampus.test", Audience: "gorestfulapi"})
+	assert.Nil(t, err)
+	secret := []byte(testJWTSecret)
+
+	// a token cannot claim every scope, nor scopes that do not exist
+	principal, err := verifier.Verify(signToken(jwt.SigningMethodHS256, secret, "", testClaims("* categories:read everything")))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"categories:read"}, principal.Scopes)
+	assert.False(t, principal.HasScope(auth.ScopeApiKeysAdmin))
+
+	claims := testClaims("")
+	delete(claims, "scope")
+	claims["scp"] = []interface{}{"*", "users:admin"}
+	principal, err = verifier.Verify(signToken(jwt.SigningMethodHS256, secret, "", claims))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"users:admin"}, principal.Scopes)
+
+	storage := setupTestStorage()
+	router := setupRouter(storage)
+	token := signToken(jwt.SigningMethodHS256, secret, "", testClaims("*"))
+	recorder, _ := doRequest(router, http.MethodGet, "/api/api-keys", bearerHeader(token), "")
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+}
+
+func writeJWKS(t *testing.T, rsaKey *rsa.PublicKey, ecKey *ecdsa.PublicKey) string {
+	encode := func(value *big.Int) string {
+		return base64.RawURLEncoding.EncodeToString(value.Bytes())
+	}
+
+	content, err := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{
+			{"kty": "RSA", "kid": "rsa-1", "use": "sig", "alg": "RS256", "n": encode(rsaKey.N), "e": encode(big.NewInt(int64(rsaKey.E)))},
+			{"kty": "EC", "kid": "ec-1", "crv": "P-256", "x": encode(ecKey.X), "y": encode(ecKey.Y)},
+		},
+	})
+	assert.Nil(t, err)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	assert.Nil(t, os.WriteFile(path, content, 0600))
+
+	return path
+}
+
+func TestJWTVerifierJWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+
+	verifier, err := auth.NewJWTVerifier(config.JWTConfig{
+		JWKSFile: writeJWKS(t, &rsaKey.PublicKey, &ecKey.PublicKey),
+		Issuer:   "https://auth.sudutkampus.test",
+		Audience: "gorestfulapi",
+	})
+	assert.Nil(t, err)
+
+	principal, err := verifier.Verify(signToken(jwt.SigningMethodRS256, rsaKey, "rsa-1", testClaims("categories:read categories:write")))
+	assert.Nil(t, err)
+	assert.Equal(t, auth.PrincipalJWT, principal.Type)
+	assert.Equal(t, "user-42", principal.Id)
+	assert.Equal(t, "Budi", principal.Name)
+	assert.Equal(t, []string{"categories:read", "categories:write"}, principal.Scopes)
+	assert.Equal(t, "user-42", principal.Claims["sub"])
+
+	principal, err = verifier.Verify(signToken(jwt.SigningMethodES256, ecKey, "ec-1", testClaims("categories:read")))
+	assert.Nil(t, err)
+	assert.Equal(t, "user-42", principal.Id)
+
+	_, err = verifier.Verify(signToken(jwt.SigningMethodRS256, rsaKey, "unknown", testClaims("categories:read")))
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+
+	// HS256 must not be accepted when only asymmetric keys are configured
+	_, err = verifier.Verify(signToken(jwt.SigningMethodHS256, []byte(testJWTSecret), "", testClaims("categories:read")))
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+
+	noExpiry := testClaims("categories:read")
+	delete(noExpiry, "exp")
+	_, err = verifier.Verify(signToken(jwt.SigningMethodES256, ecKey, "ec-1", noExpiry))
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+}
+
+func TestJWTVerifierLeeway(t *testing.T) {
+	verifier, err := auth.NewJWTVerifier(config.JWTConfig{HMACSecret: testJWTSecret, Leeway: time.Minute})
+	assert.Nil(t, err)
+
+	claims := testClaims("categories:read")
+	claims["exp"] = time.Now().Add(-30 * time.Second).Unix()
+
+	_, err = verifier.Verify(signToken(jwt.SigningMethodHS256, []byte(testJWTSecret), "", claims))
+	assert.Nil(t, err)
+
+	disabled, err := auth.NewJWTVerifier(config.JWTConfig{})
+	assert.Nil(t, err)
+	assert.Nil(t, disabled)
+}
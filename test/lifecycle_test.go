@@ -0,0 +1,141 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"sudutkampus/gorestfulapi/app"
+	"sudutkampus/gorestfulapi/config"
+	"sudutkampus/gorestfulapi/logging"
+)
+
+func recordingHook(name string, events *[]string, startErr error) app.Hook {
+	return app.Hook{
+		Name: name,
+		OnStart: func(ctx context.Context) error {
+			*events = append(*events, "start "+name)
+			return startErr
+		},
+		OnStop: func(ctx context.Context) error {
+			*events = append(*events, "stop "+name)
+			return nil
+		},
+	}
+}
+
+func TestLifecycleOrder(t *testing.T) {
+	var events []string
+	lifecycle := app.NewLifecycle()
+	lifecycle.Append(recordingHook("database", &events, nil))
+	lifecycle.Append(recordingHook("server", &events, nil))
+
+	assert.Nil(t, lifecycle.Start(context.Background()))
+	assert.False(t, lifecycle.Stopping())
+	assert.Nil(t, lifecycle.Stop(context.Background()))
+	assert.True(t, lifecycle.Stopping())
+
+	assert.Equal(t, []string{"start database", "start server", "stop server", "stop database"}, events)
+}
+
+func TestLifecycleStartFailure(t *testing.T) {
+	var events []string
+	lifecycle := app.NewLifecycle()
+	lifecycle.Append(recordingHook("database", &events, nil))
+	lifecycle.Append(recordingHook("server", &events, errors.New("address in use")))
+	lifecycle.Append(recordingHook("worker", &events, nil))
+
+	err := lifecycle.Start(context.Background())
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "address in use")
+	assert.Equal(t, []string{"start database", "start server", "stop database"}, events)
+}
+
+func TestServerHookDrainsRequests(t *testing.T) {
+	started := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(200 * time.Millisecond)
+		w.Write([]byte("done"))
+	})
+
+	serverConfig := config.Default().Server
+	serverConfig.Addr = "localhost:3998"
+	server := app.NewServer(serverConfig, handler)
+
+	lifecycle := app.NewLifecycle()
+	lifecycle.Append(app.ServerHook(server, 0, make(chan error, 1)))
+	assert.Nil(t, lifecycle.Start(context.Background()))
+
+	responses := make(chan string, 1)
+	go func() {
+		response, err := http.Get("http://localhost:3998/")
+		if err != nil {
+			responses <- err.Error()
+			return
+		}
+		defer response.Body.Close()
+		body, _ := io.ReadAll(response.Body)
+		responses <- string(body)
+	}()
+
+	<-started
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.Nil(t, lifecycle.Stop(ctx))
+
+	assert.Equal(t, "done", <-responses)
+}
+
+func TestServerHookDrainDelay(t *testing.T) {
+	storage := setupTestStorage()
+	cfg := setupTestConfig()
+	lifecycle := app.NewLifecycle()
+	checker, err := app.NewHealthChecker(storage, cfg.Database, cfg.Health, lifecycle, logging.Discard())
+	assert.Nil(t, err)
+
+	serverConfig := config.Default().Server
+	serverConfig.Addr = "localhost:3997"
+	server := app.NewServer(serverConfig, app.NewHandler(http.NotFoundHandler(), checker.Endpoints()))
+	lifecycle.Append(app.ServerHook(server, 300*time.Millisecond, make(chan error, 1)))
+	assert.Nil(t, lifecycle.Start(context.Background()))
+
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	readyz := func() (int, error) {
+		response, err := client.Get("http://localhost:3997/readyz")
+		if err != nil {
+			return 0, err
+		}
+		response.Body.Close()
+		return response.StatusCode, nil
+	}
+
+	code, err := readyz()
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, code)
+
+	stopped := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		stopped <- lifecycle.Stop(ctx)
+	}()
+
+	// the listener stays open while /readyz reports the shutdown
+	for !lifecycle.Stopping() {
+		time.Sleep(time.Millisecond)
+	}
+	code, err = readyz()
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+
+	assert.Nil(t, <-stopped)
+	_, err = readyz()
+	assert.NotNil(t, err)
+}
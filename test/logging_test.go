@@ -0,0 +1,109 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sudutkampus/gorestfulapi/logging"
+	"sudutkampus/gorestfulapi/requestid"
+)
+
+func logEntries(t *testing.T, output *bytes.Buffer) []map[string]interface{} {
+	var entries []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(output.String()), "\n") {
+		if line == "" {
+			continue
+		}
+
+		var entry map[string]interface{}
+		assert.Nil(t, json.Unmarshal([]byte(line), &entry), line)
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+func TestLogger(t *testing.T) {
+	var output bytes.Buffer
+	logger := logging.New(&output, logging.LevelInfo).With("component", "test")
+	ctx := requestid.NewContext(context.Background(), "req-1")
+
+	logger.Debug(ctx, "hidden")
+	logger.Info(ctx, "shown", "count", 2)
+	logger.Error(context.Background(), "failed", "error", errors.New("boom"), "dangling")
+
+	entries := logEntries(t, &output)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "info", entries[0]["level"])
+	assert.Equal(t, "shown", entries[0]["msg"])
+	assert.Equal(t, "req-1", entries[0]["request_id"])
+	assert.Equal(t, "test", entries[0]["component"])
+	assert.Equal(t, float64(2), entries[0]["count"])
+	assert.Equal(t, "boom", entries[1]["error"])
+	assert.Nil(t, entries[1]["request_id"])
+	assert.Contains(t, entries[1], "dangling")
+
+	level, err := logging.ParseLevel("warn")
+	assert.Nil(t, err)
+	assert.Equal(t, logging.LevelWarn, level)
+	_, err = logging.ParseLevel("verbose")
+	assert.NotNil(t, err)
+}
+
+func TestAccessLog(t *testing.T) {
+	storage := setupTestStorage()
+	truncateCategory(storage)
+	var output bytes.Buffer
+	router := setupRouterWithLogger(storage, setupTestConfig(), logging.New(&output, logging.LevelInfo))
+	category := createCategory(storage, "Gadget")
+
+	recorder, _ := doRequest(router, http.MethodGet, "/api/categories/"+strconv.Itoa(category.Id)+"/children", map[string]string{"X-API-Key": "RAHASIA", "X-Request-ID": "req-children"}, "")
+	assert.Equal(t, "req-children", recorder.Header().Get("X-Request-ID"))
+
+	recorder, _ = doRequest(router, http.MethodGet, "/api/unknown", map[string]string{"X-API-Key": "RAHASIA", "X-Request-ID": "not valid!"}, "")
+	generated := recorder.Header().Get("X-Request-ID")
+	assert.Len(t, generated, 32)
+
+	entries := logEntries(t, &output)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "request", entries[0]["msg"])
+	assert.Equal(t, "GET", entries[0]["method"])
+	assert.Equal(t, "/api/categories/"+strconv.Itoa(category.Id)+"/children", entries[0]["path"])
+	assert.Equal(t, "/api/categories/:category/:relation", entries[0]["route"])
+	assert.Equal(t, float64(http.StatusOK), entries[0]["status"])
+	assert.Equal(t, "req-children", entries[0]["request_id"])
+	assert.Greater(t, entries[0]["bytes"], float64(0))
+	assert.Contains(t, entries[0], "latency_ms")
+	assert.Contains(t, entries[0], "remote_addr")
+
+	assert.Equal(t, "", entries[1]["route"])
+	assert.Equal(t, float64(http.StatusNotFound), entries[1]["status"])
+	assert.Equal(t, generated, entries[1]["request_id"])
+}
+
+func TestServiceLog(t *testing.T) {
+	storage := setupTestStorage()
+	var output bytes.Buffer
+	cfg := setupTestConfig()
+	cfg.Log.AccessLog = false
+	router := setupRouterWithLogger(storage, cfg, logging.New(&output, logging.LevelInfo))
+
+	id, _ := issueApiKey(router, `"categories:read"`)
+	doRequest(router, http.MethodDelete, "/api/api-keys/"+strconv.Itoa(id), apiKeyHeader("RAHASIA"), "")
+	doRequest(router, http.MethodDelete, "/api/api-keys/"+strconv.Itoa(id), apiKeyHeader("RAHASIA"), "")
+
+	entries := logEntries(t, &output)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "issued api key", entries[0]["msg"])
+	assert.Equal(t, float64(id), entries[0]["api_key_id"])
+	assert.Equal(t, "revoked api key", entries[1]["msg"])
+	assert.Equal(t, float64(id), entries[1]["api_key_id"])
+}
@@ -0,0 +1,54 @@
+package test
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sudutkampus/gorestfulapi/exception"
+)
+
+func TestMetrics(t *testing.T) {
+	storage := setupTestStorage()
+	truncateCategory(storage)
+	router := setupRouter(storage)
+	category := createCategory(storage, "Gadget")
+
+	doRequest(router, http.MethodGet, "/api/categories/"+strconv.Itoa(category.Id), apiKeyHeader("RAHASIA"), "")
+	doRequest(router, http.MethodGet, "/api/categories/404", apiKeyHeader("RAHASIA"), "")
+
+	recorder, _ := doRequest(router, http.MethodGet, "/metrics", apiKeyHeader("SALAH"), "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	body := recorder.Body.String()
+	assert.Contains(t, body, `gorestfulapi_http_requests_total{method="GET",route="/api/categories/:category",status="200"} 1`)
+	assert.Contains(t, body, `gorestfulapi_http_requests_total{method="GET",route="/api/categories/:category",status="404"} 1`)
+	assert.Contains(t, body, `gorestfulapi_http_request_duration_seconds_count{method="GET",route="/api/categories/:category"} 2`)
+	assert.Contains(t, body, `gorestfulapi_http_errors_total{type="NotFoundError"} 1`)
+	if storage.DB != nil {
+		assert.Contains(t, body, `go_sql_open_connections{db_name="gorestfulapi"}`)
+	}
+}
+
+func TestMetricsDisabled(t *testing.T) {
+	storage := setupTestStorage()
+	cfg := setupTestConfig()
+	cfg.Metrics.Enabled = false
+	router := setupRouterWithConfig(storage, cfg)
+
+	recorder, _ := doRequest(router, http.MethodGet, "/metrics", apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestErrorTypeOf(t *testing.T) {
+	assert.Equal(t, "NotFoundError", exception.TypeOf(exception.NewNotFoundError("category not found")))
+	assert.Equal(t, "ConflictError", exception.TypeOf(exception.NewConflictError("category name already exists")))
+	assert.Equal(t, "InternalError", exception.TypeOf(http.ErrHandlerTimeout))
+
+	assert.True(t, exception.IsInternal(http.ErrHandlerTimeout))
+	assert.True(t, exception.IsInternal(exception.NewInternalError(http.ErrHandlerTimeout)))
+	assert.False(t, exception.IsInternal(fmt.Errorf("login: %w", exception.NewUnauthorizedError("invalid credentials"))))
+}
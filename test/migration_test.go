@@ -0,0 +1,109 @@
+package test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+
+	"sudutkampus/gorestfulapi/migration"
+)
+
+func TestMigrationUpDownStatus(t *testing.T) {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "migration.db"))
+	assert.Nil(t, err)
+	defer db.Close()
+
+	migrator, err := migration.NewMigrator(db, "sqlite3")
+	assert.Nil(t, err)
+	ctx := context.Background()
+
+	pending, err := migrator.Pending(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, len(migrator.Migrations), pending)
+
+	// Pending only reads, it leaves creating schema_migrations to Up
+	var tables int
+	assert.Nil(t, db.QueryRow("select count(*) from sqlite_master where name = 'schema_migrations'").Scan(&tables))
+	assert.Equal(t, 0, tables)
+
+	applied, err := migrator.Up(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, len(migrator.Migrations), len(applied))
+
+	_, err = db.Exec("insert into categories(name) values ('Gadget')")
+	assert.Nil(t, err)
+
+	applied, err = migrator.Up(ctx)
+	assert.Nil(t, err)
+	assert.Empty(t, applied)
+
+	statuses, err := migrator.Status(ctx)
+	assert.Nil(t, err)
+	for _, status := range statuses {
+		assert.NotEmpty(t, status.AppliedAt)
+	}
+
+	reverted, err := migrator.Down(ctx, len(migrator.Migrations))
+	assert.Nil(t, err)
+	assert.Equal(t, len(migrator.Migrations), len(reverted))
+
+	_, err = db.Exec("select * from categories")
+	assert.NotNil(t, err)
+}
+
+func TestMigrationUpExistingCategories(t *testing.T) {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "existing.db"))
+	assert.Nil(t, err)
+	defer db.Close()
+
+	_, err = db.Exec("create table categories (id integer primary key autoincrement, name varchar(255) not null)")
+	assert.Nil(t, err)
+	_, err = db.Exec("insert into categories(name) values ('Gadget')")
+	assert.Nil(t, err)
+
+	migrator, err := migration.NewMigrator(db, "sqlite3")
+	assert.Nil(t, err)
+
+	_, err = migrator.Up(context.Background())
+	assert.Nil(t, err)
+
+	var name string
+	assert.Nil(t, db.QueryRow("select name from categories where id = 1").Scan(&name))
+	assert.Equal(t, "Gadget", name)
+}
+
+func TestMigrationsExistForEveryDriver(t *testing.T) {
+	mysql, err := migration.Load("mysql")
+	assert.Nil(t, err)
+
+	sqlite, err := migration.Load("sqlite3")
+	assert.Nil(t, err)
+
+	assert.Equal(t, len(mysql), len(sqlite))
+	for i := range mysql {
+		assert.Equal(t, mysql[i].Version, sqlite[i].Version)
+		assert.NotEmpty(t, mysql[i].Up)
+		assert.NotEmpty(t, mysql[i].Down)
+		assert.NotEmpty(t, sqlite[i].Up)
+		assert.NotEmpty(t, sqlite[i].Down)
+	}
+}
+
+func TestMigrationCreate(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, os.Mkdir(filepath.Join(dir, "mysql"), 0755))
+	assert.Nil(t, os.Mkdir(filepath.Join(dir, "sqlite3"), 0755))
+
+	paths, err := migration.Create(dir, "Add Slug", time.Date(2022, 6, 2, 10, 0, 0, 0, time.UTC))
+
+	assert.Nil(t, err)
+	assert.Contains(t, paths, filepath.Join(dir, "mysql", "20220602100000_add_slug.up.sql"))
+	assert.Contains(t, paths, filepath.Join(dir, "sqlite3", "20220602100000_add_slug.down.sql"))
+	assert.Equal(t, 4, len(paths))
+}
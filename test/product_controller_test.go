@@ -0,0 +1,114 @@
+package test
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createProduct(router http.Handler, categoryId int, name string, sku string, price string) map[string]interface{} {
+	body := `{"category_id": ` + strconv.Itoa(categoryId) + `, "name": "` + name + `", "sku": "` + sku + `", "price": ` + price + `, "stock": 5}`
+	_, responseBody := doRequest(router, http.MethodPost, "/api/products", apiKeyHeader("RAHASIA"), body)
+	return responseBody
+}
+
+func TestProductCrud(t *testing.T) {
+	storage := setupTestStorage()
+	truncateCategory(storage)
+	router := setupRouter(storage)
+	category := createCategory(storage, "Gadget")
+
+	responseBody := createProduct(router, category.Id, "Phone", "PH-1", `"19.9"`)
+	assert.Equal(t, 200, int(responseBody["code"].(float64)))
+	product := responseBody["data"].(map[string]interface{})
+	assert.Equal(t, "19.90", product["price"])
+	assert.Equal(t, category.Id, int(product["category_id"].(float64)))
+	id := strconv.Itoa(int(product["id"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodGet, "/api/products/"+id, apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, "Phone", responseBody["data"].(map[string]interface{})["name"])
+
+	_, responseBody = doRequest(router, http.MethodPut, "/api/products/"+id, apiKeyHeader("RAHASIA"), `{"category_id": `+strconv.Itoa(category.Id)+`, "name": "Smartphone", "sku": "PH-1", "price": 1250.5, "stock": 0}`)
+	assert.Equal(t, 200, int(responseBody["code"].(float64)))
+	assert.Equal(t, "1250.50", responseBody["data"].(map[string]interface{})["price"])
+
+	_, responseBody = doRequest(router, http.MethodDelete, "/api/products/"+id, apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, 200, int(responseBody["code"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodGet, "/api/products/"+id, apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, http.StatusNotFound, int(responseBody["code"].(float64)))
+}
+
+func TestCreateProductFailed(t *testing.T) {
+	storage := setupTestStorage()
+	truncateCategory(storage)
+	router := setupRouter(storage)
+	category := createCategory(storage, "Gadget")
+	createProduct(router, category.Id, "Phone", "PH-1", `"10"`)
+
+	tests := []struct {
+		name       string
+		categoryId int
+		sku        string
+		price      string
+		code       int
+	}{
+		{"negative price", category.Id, "PH-2", `"-1"`, http.StatusBadRequest},
+		{"too many decimals", category.Id, "PH-2", `"1.005"`, http.StatusBadRequest},
+		{"too large", category.Id, "PH-2", `"10000000000"`, http.StatusBadRequest},
+		{"unknown category", 99999, "PH-2", `"1"`, http.StatusBadRequest},
+		{"duplicate sku", category.Id, "PH-1", `"1"`, http.StatusConflict},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			responseBody := createProduct(router, test.categoryId, "Tablet", test.sku, test.price)
+			assert.Equal(t, test.code, int(responseBody["code"].(float64)))
+		})
+	}
+}
+
+func TestListProducts(t *testing.T) {
+	storage := setupTestStorage()
+	truncateCategory(storage)
+	router := setupRouter(storage)
+	gadget := createCategory(storage, "Gadget")
+	book := createCategory(storage, "Book")
+	createProduct(router, gadget.Id, "Phone", "PH-1", `"10"`)
+	createProduct(router, gadget.Id, "Tablet", "TB-1", `"20"`)
+	createProduct(router, book.Id, "Novel", "NV-1", `"5"`)
+
+	_, responseBody := doRequest(router, http.MethodGet, "/api/products", apiKeyHeader("RAHASIA"), "")
+	assert.Len(t, responseBody["data"], 3)
+	assert.Equal(t, 3, int(responseBody["meta"].(map[string]interface{})["total"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodGet, "/api/products?category_id="+strconv.Itoa(book.Id), apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, []string{"Novel"}, categoryNames(responseBody))
+
+	_, responseBody = doRequest(router, http.MethodGet, "/api/categories/"+strconv.Itoa(gadget.Id)+"/products?q=tab", apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, []string{"Tablet"}, categoryNames(responseBody))
+
+	_, responseBody = doRequest(router, http.MethodGet, "/api/categories/99999/products", apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, http.StatusNotFound, int(responseBody["code"].(float64)))
+}
+
+func TestDeleteCategoryWithProducts(t *testing.T) {
+	storage := setupTestStorage()
+	truncateCategory(storage)
+	router := setupRouter(storage)
+	category := createCategory(storage, "Gadget")
+	product := createProduct(router, category.Id, "Phone", "PH-1", `"10"`)["data"].(map[string]interface{})
+
+	_, responseBody := doRequest(router, http.MethodDelete, "/api/categories/"+strconv.Itoa(category.Id), apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, http.StatusConflict, int(responseBody["code"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodDelete, "/api/categories/"+strconv.Itoa(category.Id)+"?purge=true", apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, http.StatusConflict, int(responseBody["code"].(float64)))
+
+	doRequest(router, http.MethodDelete, "/api/products/"+strconv.Itoa(int(product["id"].(float64))), apiKeyHeader("RAHASIA"), "")
+
+	_, responseBody = doRequest(router, http.MethodDelete, "/api/categories/"+strconv.Itoa(category.Id), apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, 200, int(responseBody["code"].(float64)))
+}
@@ -0,0 +1,130 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"sudutkampus/gorestfulapi/config"
+	"sudutkampus/gorestfulapi/ratelimit"
+)
+
+func TestRateLimit(t *testing.T) {
+	storage := setupTestStorage()
+	cfg := setupTestConfig()
+	cfg.RateLimit.Enabled = true
+	cfg.RateLimit.Default = config.RateLimit{Rate: 0.001, Burst: 2}
+	cfg.RateLimit.Groups = map[string]config.RateLimit{"auth": {Rate: 0.001, Burst: 1}}
+	router := setupRouterWithConfig(storage, cfg)
+	_, key := issueApiKey(router, `"categories:read"`)
+
+	recorder, _ := doRequest(router, http.MethodGet, "/api/categories", apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "2", recorder.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "1", recorder.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "1000", recorder.Header().Get("X-RateLimit-Reset"))
+
+	recorder, _ = doRequest(router, http.MethodGet, "/api/categories", apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "0", recorder.Header().Get("X-RateLimit-Remaining"))
+
+	recorder, responseBody := doRequest(router, http.MethodGet, "/api/categories/tree", apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	assert.Equal(t, "1000", recorder.Header().Get("Retry-After"))
+	assert.Equal(t, "0", recorder.Header().Get("X-RateLimit-Remaining"))
+
+	assert.Equal(t, float64(http.StatusTooManyRequests), responseBody["code"])
+	assert.Equal(t, "Too Many Requests", responseBody["status"])
+
+	// other route groups and other clients have buckets of their own
+	recorder, _ = doRequest(router, http.MethodGet, "/api/products", apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	recorder, _ = doRequest(router, http.MethodGet, "/api/categories", apiKeyHeader(key), "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	// anonymous clients are told apart by IP address
+	recorder, _ = doRequest(router, http.MethodPost, "/api/auth/login", nil, `{"email": "budi@example.com", "password": "salah"}`)
+	assert.NotEqual(t, http.StatusTooManyRequests, recorder.Code)
+	assert.Equal(t, "1", recorder.Header().Get("X-RateLimit-Limit"))
+	recorder, _ = doRequest(router, http.MethodPost, "/api/auth/login", nil, `{"email": "budi@example.com", "password": "salah"}`)
+	assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+}
+
+func TestRateLimitAuthFailures(t *testing.T) {
+	storage := setupTestStorage()
+	cfg := setupTestConfig()
+	cfg.RateLimit.Enabled = true
+	cfg.RateLimit.AuthFailures = config.RateLimit{Rate: 0.001, Burst: 3}
+	router := setupRouterWithConfig(storage, cfg)
+	_, key := issueApiKey(router, `"categories:read"`)
+	prefix := strings.SplitN(key, ".", 2)[0]
+
+	// valid keys do not take from the bucket of failures
+	for i := 0; i < 5; i++ {
+		recorder, _ := doRequest(router, http.MethodGet, "/api/categories", apiKeyHeader(key), "")
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	}
+
+	for i := 0; i < 3; i++ {
+		recorder, _ := doRequest(router, http.MethodGet, "/api/categories", apiKeyHeader(prefix+".guess"+strconv.Itoa(i)), "")
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	}
+
+	recorder, _ := doRequest(router, http.MethodGet, "/api/categories", apiKeyHeader(prefix+".guess"), "")
+	assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	assert.Equal(t, "1000", recorder.Header().Get("Retry-After"))
+
+	recorder, _ = doRequest(router, http.MethodGet, "/api/categories", bearerHeader("guess"), "")
+	assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+
+	// requests without credentials are left to the route limits
+	recorder, _ = doRequest(router, http.MethodGet, "/api/categories", nil, "")
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
+
+func TestMemoryStore(t *testing.T) {
+	now := time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+	store := ratelimit.NewMemoryStore()
+	store.Now = func() time.Time { return now }
+	limit := ratelimit.Limit{Rate: 2, Burst: 2}
+	ctx := context.Background()
+
+	result, _ := store.Take(ctx, "user:1", limit)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 1, result.Remaining)
+	assert.Equal(t, 500*time.Millisecond, result.Reset)
+
+	result, _ = store.Take(ctx, "user:1", limit)
+	assert.True(t, result.Allowed)
+	result, _ = store.Take(ctx, "user:1", limit)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 500*time.Millisecond, result.RetryAfter)
+
+	result, _ = store.Take(ctx, "user:2", limit)
+	assert.True(t, result.Allowed)
+
+	// peeking reports the bucket without taking from it
+	result, _ = store.Peek(ctx, "user:2", limit)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 1, result.Remaining)
+	result, _ = store.Peek(ctx, "user:3", limit)
+	assert.Equal(t, 2, result.Remaining)
+	result, _ = store.Peek(ctx, "user:1", limit)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 500*time.Millisecond, result.RetryAfter)
+
+	now = now.Add(250 * time.Millisecond)
+	result, _ = store.Take(ctx, "user:1", limit)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 250*time.Millisecond, result.RetryAfter)
+
+	now = now.Add(time.Hour)
+	result, _ = store.Take(ctx, "user:1", limit)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 1, result.Remaining)
+}
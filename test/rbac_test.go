@@ -0,0 +1,70 @@
+package test
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sudutkampus/gorestfulapi/auth"
+)
+
+func TestPolicies(t *testing.T) {
+	editor := auth.Principal{Roles: []string{auth.RoleEditor}, Scopes: auth.RolePermissions([]string{auth.RoleEditor})}
+
+	assert.Nil(t, auth.Permission(auth.ScopeCategoriesWrite)(editor))
+	assert.EqualError(t, auth.Permission(auth.ScopeUsersAdmin)(editor), "missing permission users:admin")
+	assert.Nil(t, auth.Role(auth.RoleEditor)(editor))
+	assert.EqualError(t, auth.Role(auth.RoleAdmin)(editor), "missing role admin")
+
+	assert.Nil(t, auth.AnyOf(auth.Role(auth.RoleAdmin), auth.Permission(auth.ScopeCategoriesRead))(editor))
+	assert.EqualError(t, auth.AnyOf(auth.Role(auth.RoleAdmin), auth.Permission(auth.ScopeUsersAdmin))(editor),
+		"missing role admin or missing permission users:admin")
+	assert.EqualError(t, auth.AllOf(auth.Role(auth.RoleEditor), auth.Role(auth.RoleAdmin))(editor), "missing role admin")
+
+	assert.Equal(t, []string{"categories:read", "products:read", "categories:write", "products:write", "api_keys:admin", "users:admin", "audit:read"},
+		auth.RolePermissions([]string{auth.RoleViewer, auth.RoleAdmin, "unknown"}))
+}
+
+func TestAssignRoles(t *testing.T) {
+	storage := setupTestStorage()
+	truncateCategory(storage)
+	truncateUser(storage)
+	router := setupRouter(storage)
+	accessToken, _ := registerAndLogin(router, "viewer@example.com", "rahasia123")
+
+	_, responseBody := doRequest(router, http.MethodGet, "/api/auth/me", bearerHeader(accessToken), "")
+	userId := strconv.Itoa(int(responseBody["data"].(map[string]interface{})["id"].(float64)))
+	assert.Equal(t, []interface{}{"viewer"}, responseBody["data"].(map[string]interface{})["roles"])
+
+	_, responseBody = doRequest(router, http.MethodGet, "/api/categories", bearerHeader(accessToken), "")
+	assert.Equal(t, http.StatusOK, int(responseBody["code"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodPost, "/api/categories", bearerHeader(accessToken), `{"name": "Gadget"}`)
+	assert.Equal(t, http.StatusForbidden, int(responseBody["code"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodGet, "/api/users", bearerHeader(accessToken), "")
+	assert.Equal(t, http.StatusForbidden, int(responseBody["code"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodPut, "/api/users/"+userId+"/roles", apiKeyHeader("RAHASIA"), `{"roles": ["editor", "editor"]}`)
+	assert.Equal(t, http.StatusOK, int(responseBody["code"].(float64)))
+	assert.Equal(t, []interface{}{"editor"}, responseBody["data"].(map[string]interface{})["roles"])
+
+	// roles are read on every request, the session does not need renewing
+	_, responseBody = doRequest(router, http.MethodPost, "/api/categories", bearerHeader(accessToken), `{"name": "Gadget"}`)
+	assert.Equal(t, http.StatusOK, int(responseBody["code"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodPut, "/api/users/"+userId+"/roles", apiKeyHeader("RAHASIA"), `{"roles": ["admin"]}`)
+	assert.Equal(t, http.StatusOK, int(responseBody["code"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodGet, "/api/users", bearerHeader(accessToken), "")
+	assert.Equal(t, http.StatusOK, int(responseBody["code"].(float64)))
+	assert.Len(t, responseBody["data"], 1)
+
+	_, responseBody = doRequest(router, http.MethodPut, "/api/users/"+userId+"/roles", bearerHeader(accessToken), `{"roles": ["owner"]}`)
+	assert.Equal(t, http.StatusBadRequest, int(responseBody["code"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodPut, "/api/users/99999/roles", bearerHeader(accessToken), `{"roles": ["viewer"]}`)
+	assert.Equal(t, http.StatusNotFound, int(responseBody["code"].(float64)))
+}
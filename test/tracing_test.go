@@ -0,0 +1,126 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"sudutkampus/gorestfulapi/app"
+	"sudutkampus/gorestfulapi/config"
+	"sudutkampus/gorestfulapi/logging"
+	"sudutkampus/gorestfulapi/tracing"
+)
+
+func spanNamed(spans []sdktrace.ReadOnlySpan, name string) sdktrace.ReadOnlySpan {
+	for _, span := range spans {
+		if span.Name() == name {
+			return span
+		}
+	}
+
+	return nil
+}
+
+func spanAttribute(span sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+
+	return attribute.Value{}
+}
+
+func TestTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := tracing.Tracer(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	storage := setupTestStorageWithTracer(tracer)
+	truncateCategory(storage)
+	var output bytes.Buffer
+	router := setupRouterWithTracer(storage, setupTestConfig(), logging.New(&output, logging.LevelInfo), tracer)
+	category := createCategory(storage, "Gadget")
+	before := len(recorder.Ended())
+
+	response, _ := doRequest(router, http.MethodGet, "/api/categories/"+strconv.Itoa(category.Id), map[string]string{
+		"X-API-Key":   "RAHASIA",
+		"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	}, "")
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	spans := recorder.Ended()[before:]
+
+	server := spanNamed(spans, "GET /api/categories/:category")
+	assert.NotNil(t, server)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", server.Parent().SpanID().String())
+	assert.Equal(t, int64(200), spanAttribute(server, "http.status_code").AsInt64())
+
+	controller := spanNamed(spans, "CategoryController.FindById")
+	assert.NotNil(t, controller)
+	assert.Equal(t, server.SpanContext().SpanID(), controller.Parent().SpanID())
+
+	service := spanNamed(spans, "CategoryService.FindById")
+	assert.NotNil(t, service)
+	assert.Equal(t, controller.SpanContext().SpanID(), service.Parent().SpanID())
+
+	repository := spanNamed(spans, "CategoryRepository.FindById")
+	assert.NotNil(t, repository)
+	assert.Equal(t, server.SpanContext().TraceID(), repository.SpanContext().TraceID())
+
+	if storage.DB != nil {
+		statement := spanNamed(spans, "select")
+		assert.NotNil(t, statement)
+		assert.Equal(t, repository.SpanContext().SpanID(), statement.Parent().SpanID())
+		assert.Contains(t, spanAttribute(statement, "db.statement").AsString(), "from categories")
+	}
+
+	entries := logEntries(t, &output)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", entries[len(entries)-1]["trace_id"])
+}
+
+func TestTracingErrors(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := tracing.Tracer(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	storage := setupTestStorageWithTracer(tracer)
+	truncateCategory(storage)
+	router := setupRouterWithTracer(storage, setupTestConfig(), logging.Discard(), tracer)
+
+	doRequest(router, http.MethodGet, "/api/categories/404", apiKeyHeader("RAHASIA"), "")
+	doRequest(router, http.MethodGet, "/api/unknown", apiKeyHeader("RAHASIA"), "")
+
+	spans := recorder.Ended()
+	assert.Equal(t, codes.Unset, spanNamed(spans, "GET /api/categories/:category").Status().Code)
+	assert.Equal(t, codes.Error, spanNamed(spans, "CategoryService.FindById").Status().Code)
+	assert.Equal(t, codes.Unset, spanNamed(spans, "CategoryRepository.FindById").Status().Code)
+	assert.NotNil(t, spanNamed(spans, "GET"))
+}
+
+func TestTracingFileExporter(t *testing.T) {
+	tracingConfig := config.Default().Tracing
+	tracingConfig.Enabled = true
+	tracingConfig.Exporter = config.ExporterFile
+	tracingConfig.File = filepath.Join(t.TempDir(), "spans.json")
+
+	tracer, hook, err := app.NewTracer(tracingConfig)
+	assert.Nil(t, err)
+
+	_, span := tracer.Start(context.Background(), "offline")
+	span.End()
+	assert.Nil(t, hook.OnStop(context.Background()))
+
+	content, err := os.ReadFile(tracingConfig.File)
+	assert.Nil(t, err)
+	assert.True(t, strings.Contains(string(content), `"Name":"offline"`), string(content))
+	assert.Contains(t, string(content), `"Value":"gorestfulapi"`)
+}
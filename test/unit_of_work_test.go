@@ -0,0 +1,60 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sudutkampus/gorestfulapi/logging"
+	"sudutkampus/gorestfulapi/model/domain"
+	"sudutkampus/gorestfulapi/repository"
+)
+
+func TestMemoryUnitOfWorkRollback(t *testing.T) {
+	unitOfWork := repository.NewMemoryUnitOfWork(repository.NewMemoryDB(), logging.Discard())
+	categoryRepository := repository.NewCategoryRepositoryMemory()
+	ctx := context.Background()
+
+	err := unitOfWork.Do(ctx, func(tx repository.Tx) error {
+		_, err := categoryRepository.Save(ctx, tx, domain.Category{Name: "Gadget"})
+		assert.Nil(t, err)
+		return errors.New("rollback")
+	})
+	assert.NotNil(t, err)
+
+	err = unitOfWork.Do(ctx, func(tx repository.Tx) error {
+		total, err := categoryRepository.Count(ctx, tx, domain.CategoryFilter{})
+		assert.Equal(t, 0, total)
+		return err
+	})
+	assert.Nil(t, err)
+}
+
+func TestMemoryUnitOfWorkConcurrent(t *testing.T) {
+	unitOfWork := repository.NewMemoryUnitOfWork(repository.NewMemoryDB(), logging.Discard())
+	categoryRepository := repository.NewCategoryRepositoryMemory()
+	ctx := context.Background()
+
+	var group sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		group.Add(1)
+		go func() {
+			defer group.Done()
+			unitOfWork.Do(ctx, func(tx repository.Tx) error {
+				_, err := categoryRepository.Save(ctx, tx, domain.Category{Name: "Gadget"})
+				return err
+			})
+		}()
+	}
+	group.Wait()
+
+	unitOfWork.Do(ctx, func(tx repository.Tx) error {
+		categories, err := categoryRepository.FindAll(ctx, tx, domain.CategoryFilter{Sort: "-id"})
+		assert.Equal(t, 50, len(categories))
+		assert.Equal(t, 50, categories[0].Id)
+		return err
+	})
+}
@@ -0,0 +1,131 @@
+package test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sudutkampus/gorestfulapi/app"
+)
+
+func truncateUser(storage *app.Storage) {
+	if storage.DB != nil {
+		storage.DB.Exec("DELETE FROM sessions")
+		storage.DB.Exec("DELETE FROM users")
+	}
+}
+
+// registerAndLogin returns the access and refresh tokens of a new user.
+func registerAndLogin(router http.Handler, email string, password string) (string, string) {
+	doRequest(router, http.MethodPost, "/api/auth/register", nil, `{"email": "`+email+`", "name": "Budi", "password": "`+password+`"}`)
+	_, responseBody := doRequest(router, http.MethodPost, "/api/auth/login", nil, `{"email": "`+email+`", "password": "`+password+`"}`)
+	data := responseBody["data"].(map[string]interface{})
+
+	return data["access_token"].(string), data["refresh_token"].(string)
+}
+
+func TestRegisterUser(t *testing.T) {
+	storage := setupTestStorage()
+	truncateUser(storage)
+	router := setupRouter(storage)
+
+	_, responseBody := doRequest(router, http.MethodPost, "/api/auth/register", nil, `{"email": " Register@Example.com ", "name": "Budi", "password": "rahasia123"}`)
+	assert.Equal(t, http.StatusOK, int(responseBody["code"].(float64)))
+	data := responseBody["data"].(map[string]interface{})
+	assert.Equal(t, "register@example.com", data["email"])
+	assert.Nil(t, data["password"])
+	assert.Nil(t, data["password_hash"])
+
+	_, responseBody = doRequest(router, http.MethodPost, "/api/auth/register", nil, `{"email": "REGISTER@example.com", "name": "Budi", "password": "rahasia123"}`)
+	assert.Equal(t, http.StatusConflict, int(responseBody["code"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodPost, "/api/auth/register", nil, `{"email": "not-an-email", "name": "Budi", "password": "short"}`)
+	assert.Equal(t, http.StatusBadRequest, int(responseBody["code"].(float64)))
+	assert.Len(t, responseBody["data"], 2)
+}
+
+func TestLoginUser(t *testing.T) {
+	storage := setupTestStorage()
+	truncateUser(storage)
+	router := setupRouter(storage)
+	accessToken, _ := registerAndLogin(router, "login@example.com", "rahasia123")
+
+	_, responseBody := doRequest(router, http.MethodGet, "/api/auth/me", bearerHeader(accessToken), "")
+	assert.Equal(t, http.StatusOK, int(responseBody["code"].(float64)))
+	assert.Equal(t, "login@example.com", responseBody["data"].(map[string]interface{})["email"])
+
+	_, responseBody = doRequest(router, http.MethodGet, "/api/categories", bearerHeader(accessToken), "")
+	assert.Equal(t, http.StatusOK, int(responseBody["code"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodPost, "/api/auth/login", nil, `{"email": "login@example.com", "password": "salah12345"}`)
+	assert.Equal(t, http.StatusUnauthorized, int(responseBody["code"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodPost, "/api/auth/login", nil, `{"email": "nobody@example.com", "password": "rahasia123"}`)
+	assert.Equal(t, http.StatusUnauthorized, int(responseBody["code"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodGet, "/api/auth/me", bearerHeader("at_invalid"), "")
+	assert.Equal(t, http.StatusUnauthorized, int(responseBody["code"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodGet, "/api/auth/me", nil, "")
+	assert.Equal(t, http.StatusUnauthorized, int(responseBody["code"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodGet, "/api/auth/me", apiKeyHeader("RAHASIA"), "")
+	assert.Equal(t, http.StatusForbidden, int(responseBody["code"].(float64)))
+}
+
+func TestRefreshAndLogout(t *testing.T) {
+	storage := setupTestStorage()
+	truncateUser(storage)
+	router := setupRouter(storage)
+	accessToken, refreshToken := registerAndLogin(router, "refresh@example.com", "rahasia123")
+
+	_, responseBody := doRequest(router, http.MethodPost, "/api/auth/refresh", nil, `{"refresh_token": "`+refreshToken+`"}`)
+	assert.Equal(t, http.StatusOK, int(responseBody["code"].(float64)))
+	data := responseBody["data"].(map[string]interface{})
+	assert.Equal(t, "Bearer", data["token_type"])
+	newAccessToken := data["access_token"].(string)
+
+	// refreshing replaces both tokens
+	_, responseBody = doRequest(router, http.MethodGet, "/api/auth/me", bearerHeader(accessToken), "")
+	assert.Equal(t, http.StatusUnauthorized, int(responseBody["code"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodPost, "/api/auth/refresh", nil, `{"refresh_token": "`+refreshToken+`"}`)
+	assert.Equal(t, http.StatusUnauthorized, int(responseBody["code"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodPost, "/api/auth/logout", bearerHeader(newAccessToken), "")
+	assert.Equal(t, http.StatusOK, int(responseBody["code"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodGet, "/api/auth/me", bearerHeader(newAccessToken), "")
+	assert.Equal(t, http.StatusUnauthorized, int(responseBody["code"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodPost, "/api/auth/refresh", nil, `{"refresh_token": "`+data["refresh_token"].(string)+`"}`)
+	assert.Equal(t, http.StatusUnauthorized, int(responseBody["code"].(float64)))
+}
+
+func TestChangePassword(t *testing.T) {
+	storage := setupTestStorage()
+	truncateUser(storage)
+	router := setupRouter(storage)
+	accessToken, _ := registerAndLogin(router, "password@example.com", "rahasia123")
+	otherAccessToken, _ := registerAndLogin(router, "password@example.com", "rahasia123")
+
+	_, responseBody := doRequest(router, http.MethodPut, "/api/auth/password", bearerHeader(accessToken), `{"current_password": "salah12345", "new_password": "rahasia456"}`)
+	assert.Equal(t, http.StatusBadRequest, int(responseBody["code"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodPut, "/api/auth/password", bearerHeader(accessToken), `{"current_password": "rahasia123", "new_password": "rahasia456"}`)
+	assert.Equal(t, http.StatusOK, int(responseBody["code"].(float64)))
+
+	// other sessions are logged out, the current one stays valid
+	_, responseBody = doRequest(router, http.MethodGet, "/api/auth/me", bearerHeader(otherAccessToken), "")
+	assert.Equal(t, http.StatusUnauthorized, int(responseBody["code"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodGet, "/api/auth/me", bearerHeader(accessToken), "")
+	assert.Equal(t, http.StatusOK, int(responseBody["code"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodPost, "/api/auth/login", nil, `{"email": "password@example.com", "password": "rahasia123"}`)
+	assert.Equal(t, http.StatusUnauthorized, int(responseBody["code"].(float64)))
+
+	_, responseBody = doRequest(router, http.MethodPost, "/api/auth/login", nil, `{"email": "password@example.com", "password": "rahasia456"}`)
+	assert.Equal(t, http.StatusOK, int(responseBody["code"].(float64)))
+}
@@ -0,0 +1,94 @@
+// Package tracing sets up the OpenTelemetry tracer shared by the HTTP,
+// controller, service and repository layers.
+package tracing
+
+import (
+	"context"
+	"os"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"sudutkampus/gorestfulapi/config"
+)
+
+const instrumentationName = "sudutkampus/gorestfulapi"
+
+// NewProvider returns a provider that batches spans to the exporter
+// tracingConfig selects. Shutting it down flushes the last batch.
+func NewProvider(tracingConfig config.TracingConfig) (*sdktrace.TracerProvider, error) {
+	exporter, err := newExporter(tracingConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String(tracingConfig.ServiceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(tracingConfig.SampleRatio))),
+	), nil
+}
+
+func newExporter(tracingConfig config.TracingConfig) (sdktrace.SpanExporter, error) {
+	if tracingConfig.Exporter == config.ExporterFile {
+		file, err := os.OpenFile(tracingConfig.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, err
+		}
+
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+
+		return &fileExporter{Exporter: exporter, file: file}, nil
+	}
+
+	options := []otlptracehttp.Option{otlptracehttp.WithEndpoint(tracingConfig.Endpoint)}
+	if tracingConfig.Insecure {
+		options = append(options, otlptracehttp.WithInsecure())
+	}
+
+	return otlptracehttp.New(context.Background(), options...)
+}
+
+// fileExporter closes the file the spans are written to on shutdown.
+type fileExporter struct {
+	*stdouttrace.Exporter
+	file *os.File
+}
+
+func (exporter *fileExporter) Shutdown(ctx context.Context) error {
+	err := exporter.Exporter.Shutdown(ctx)
+	if err != nil {
+		return err
+	}
+
+	return exporter.file.Close()
+}
+
+// Tracer returns the tracer of this application from provider.
+func Tracer(provider trace.TracerProvider) trace.Tracer {
+	return provider.Tracer(instrumentationName)
+}
+
+// Noop returns a tracer that records nothing, for when tracing is disabled.
+func Noop() trace.Tracer {
+	return Tracer(trace.NewNoopTracerProvider())
+}
+
+// End marks span as failed when err is not nil and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}